@@ -0,0 +1,102 @@
+package webdriver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+
+	"github.com/mediabuyerbot/httpclient"
+
+	"github.com/mediabuyerbot/go-webdriver/pkg/w3cproto"
+)
+
+var ErrNoSessionAddress = errors.New("webdriver: session descriptor has no address")
+
+// SessionDescriptor describes the session, so that the session can be attached to
+// after the restart of the process.
+type SessionDescriptor struct {
+	// Address is the address of the remote end, e.g. http://localhost:4444.
+	Address      string                `json:"address"`
+	ID           string                `json:"sessionId"`
+	Capabilities w3cproto.Capabilities `json:"capabilities"`
+}
+
+// Save writes the descriptor to the file as JSON.
+func (d SessionDescriptor) Save(filename string) error {
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, data, 0600)
+}
+
+// LoadSessionDescriptor reads the descriptor written by SessionDescriptor.Save.
+func LoadSessionDescriptor(filename string) (d SessionDescriptor, err error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return d, err
+	}
+	if err := json.Unmarshal(data, &d); err != nil {
+		return d, err
+	}
+	if len(d.ID) == 0 {
+		return d, w3cproto.ErrUnknownSession
+	}
+	return d, nil
+}
+
+// AttachSession attaches to the existing session on the remote end at the address instead of creating
+// a new one. Returns an error if the session is not alive, e.g. the invalid session id error.
+func AttachSession(ctx context.Context, addr string, id string, caps w3cproto.Capabilities, middlewares ...w3cproto.Middleware) (*Session, error) {
+	client, err := httpclient.New(httpclient.WithBaseURL(addr))
+	if err != nil {
+		return nil, err
+	}
+	sess, err := AttachSessionFromDoer(ctx, w3cproto.WithClient(client), id, caps, middlewares...)
+	if err != nil {
+		return nil, err
+	}
+	sess.addr = addr
+	return sess, nil
+}
+
+// AttachSessionFromDoer attaches to the existing session on top of the doer.
+// The commands pass through the middlewares, the first middleware is the outermost one.
+func AttachSessionFromDoer(ctx context.Context, doer w3cproto.Doer, id string, caps w3cproto.Capabilities, middlewares ...w3cproto.Middleware) (*Session, error) {
+	cli := w3cproto.Chain(doer, middlewares...)
+	sess, err := w3cproto.AttachSession(ctx, cli, id, caps)
+	if err != nil {
+		return nil, err
+	}
+	return newSession(cli, sess), nil
+}
+
+// AttachRemoteBrowser attaches to the session described by the descriptor. The context is the default
+// context of the browser methods without the context parameter. Closing the browser deletes the session.
+func AttachRemoteBrowser(ctx context.Context, d SessionDescriptor, middlewares ...w3cproto.Middleware) (*Browser, error) {
+	if len(d.Address) == 0 {
+		return nil, ErrNoSessionAddress
+	}
+	sess, err := AttachSession(ctx, d.Address, d.ID, d.Capabilities, middlewares...)
+	if err != nil {
+		return nil, err
+	}
+	return newBrowser(ctx, sess, nil), nil
+}
+
+// Descriptor returns the descriptor of the session. The address is empty if the session
+// is created on top of the doer.
+func (b *Session) Descriptor() SessionDescriptor {
+	return SessionDescriptor{
+		Address:      b.addr,
+		ID:           b.session.ID(),
+		Capabilities: b.session.Capabilities(),
+	}
+}
+
+// Descriptor returns the descriptor of the browser session, e.g. to attach to the session
+// after the restart with AttachRemoteBrowser.
+func (b *Browser) Descriptor() SessionDescriptor {
+	return b.sess.Descriptor()
+}
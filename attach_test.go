@@ -0,0 +1,50 @@
+package webdriver
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mediabuyerbot/go-webdriver/pkg/w3cproto"
+	"github.com/mediabuyerbot/go-webdriver/pkg/w3ctest"
+)
+
+func TestAttachRemoteBrowser(t *testing.T) {
+	ctx := context.Background()
+	srv := w3ctest.NewServer(&w3ctest.Page{URL: "https://example.com/", Title: "Example"})
+	defer srv.Close()
+
+	browser, err := OpenRemoteBrowser(ctx, srv.URL, w3cproto.NewBrowserOptions(nil, nil))
+	assert.Nil(t, err)
+	assert.Nil(t, browser.NavigateTo("https://example.com/"))
+
+	// saves and loads the descriptor
+	filename := filepath.Join(t.TempDir(), "session.json")
+	d := browser.Descriptor()
+	assert.Equal(t, srv.URL, d.Address)
+	assert.Equal(t, browser.UID(), d.ID)
+	assert.Nil(t, d.Save(filename))
+	loaded, err := LoadSessionDescriptor(filename)
+	assert.Nil(t, err)
+	assert.Equal(t, d, loaded)
+
+	attached, err := AttachRemoteBrowser(ctx, loaded)
+	assert.Nil(t, err)
+	assert.Equal(t, browser.UID(), attached.UID())
+	assert.Equal(t, "w3ctest", attached.Descriptor().Capabilities.GetString(w3cproto.CapabilityBrowserName))
+	url, err := attached.CurrentURL()
+	assert.Nil(t, err)
+	assert.Equal(t, "https://example.com/", url)
+
+	// returns error if the session is deleted
+	assert.Nil(t, attached.Close())
+	_, err = AttachRemoteBrowser(ctx, loaded)
+	assert.True(t, errors.Is(err, w3cproto.ErrInvalidSessionID))
+	_, err = AttachRemoteBrowser(ctx, SessionDescriptor{ID: d.ID})
+	assert.Equal(t, ErrNoSessionAddress, err)
+	_, err = AttachSessionFromDoer(ctx, w3cproto.WithClient(nil), "", nil)
+	assert.Equal(t, w3cproto.ErrUnknownSession, err)
+}
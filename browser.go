@@ -5,25 +5,72 @@ import (
 	"image"
 	"io"
 	"net"
+	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/mediabuyerbot/go-webdriver/pkg/w3cproto"
 )
 
+const (
+	mouseInputID    = "mouse"
+	keyboardInputID = "keyboard"
+	wheelInputID    = "wheel"
+
+	dragDuration = 250 * time.Millisecond
+)
+
 type Driver interface {
 	Run(context.Context) error
 	Stop(context.Context) error
 }
 
+// Browser controls the browser session. The methods with the context parameter use the context
+// for the cancellation and the deadline of the commands, the methods without the context parameter
+// use the default context of the browser. The browser is safe for concurrent use, the commands of
+// the session are serialized.
 type Browser struct {
-	ctx    context.Context
 	sess   *Session
 	driver Driver
+
+	lock sync.RWMutex
+	ctx  context.Context
 }
 
+func newBrowser(ctx context.Context, sess *Session, driver Driver) *Browser {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return &Browser{
+		ctx:    ctx,
+		sess:   sess,
+		driver: driver,
+	}
+}
+
+// WithContext sets the default context of the methods without the context parameter.
+//
+// Deprecated: pass the context to the methods with the Context suffix, e.g. NavigateToContext.
 func (b *Browser) WithContext(ctx context.Context) {
+	if ctx == nil {
+		return
+	}
+	b.lock.Lock()
 	b.ctx = ctx
+	b.lock.Unlock()
+}
+
+// context returns the default context.
+func (b *Browser) context() context.Context {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	return b.ctx
+}
+
+// withContext returns the browser sharing the session with the default context replaced.
+func (b *Browser) withContext(ctx context.Context) *Browser {
+	return newBrowser(ctx, b.sess, b.driver)
 }
 
 // UID returns the unique browser id.
@@ -31,17 +78,17 @@ func (b *Browser) UID() string {
 	return b.sess.Session().ID()
 }
 
-// Execute inject a snippet of JavaScript into the page for execution in the context of the currently
+// ExecuteContext inject a snippet of JavaScript into the page for execution in the context of the currently
 // selected frame. The executed script is assumed to be synchronous and the result of evaluating the script
 // is returned to the client. The script argument defines the script to execute in the form of a function body.
 // The value returned by that function will be returned to the client. The function will be invoked with
 // the provided args array and the values may be accessed via the arguments object in the order specified.
 // Arguments may be any JSON-primitive, array, or JSON object.
-func (b *Browser) Execute(script string, args []interface{}) ([]byte, error) {
-	return b.sess.Document().ExecuteScript(b.ctx, script, args)
+func (b *Browser) ExecuteContext(ctx context.Context, script string, args []interface{}) ([]byte, error) {
+	return b.sess.Document().ExecuteScript(ctx, script, args)
 }
 
-// ExecuteAsync inject a snippet of JavaScript into the page for execution in the context of the
+// ExecuteAsyncContext inject a snippet of JavaScript into the page for execution in the context of the
 // currently selected frame. The executed script is assumed to be asynchronous and must signal that
 // is done by invoking the provided callback, which is always provided as the final argument to the function.
 // The value to this callback will be returned to the client. Asynchronous script commands may not span page loads.
@@ -50,97 +97,97 @@ func (b *Browser) Execute(script string, args []interface{}) ([]byte, error) {
 // with the provided args array and the values may be accessed via the arguments object in the order specified.
 // The final argument will always be a callback function that must be invoked to signal that the script has finished.
 // Arguments may be any JSON-primitive, array, or JSON object.
-func (b *Browser) ExecuteAsync(script string, args []interface{}) ([]byte, error) {
-	return b.sess.Document().ExecuteAsyncScript(b.ctx, script, args)
+func (b *Browser) ExecuteAsyncContext(ctx context.Context, script string, args []interface{}) ([]byte, error) {
+	return b.sess.Document().ExecuteAsyncScript(ctx, script, args)
 }
 
-// Source returns a string serialization of the DOM of the current browsing context active document.
-func (b *Browser) Source() (source string, err error) {
-	return b.sess.Document().GetPageSource(b.ctx)
+// SourceContext returns a string serialization of the DOM of the current browsing context active document.
+func (b *Browser) SourceContext(ctx context.Context) (source string, err error) {
+	return b.sess.Document().GetPageSource(ctx)
 }
 
-// Cookies returns an all cookies visible to the current page.
-func (b *Browser) Cookies() ([]w3cproto.Cookie, error) {
-	return b.sess.Cookies().All(b.ctx)
+// CookiesContext returns an all cookies visible to the current page.
+func (b *Browser) CookiesContext(ctx context.Context) ([]w3cproto.Cookie, error) {
+	return b.sess.Cookies().All(ctx)
 }
 
-// GetCookie returns a cookie by name visible to the current page.
-func (b *Browser) GetCookie(name string) (w3cproto.Cookie, error) {
-	return b.sess.Cookies().Get(b.ctx, name)
+// GetCookieContext returns a cookie by name visible to the current page.
+func (b *Browser) GetCookieContext(ctx context.Context, name string) (w3cproto.Cookie, error) {
+	return b.sess.Cookies().Get(ctx, name)
 }
 
-// AddCookie adds a cookie.
-func (b *Browser) AddCookie(c w3cproto.Cookie) error {
-	return b.sess.Cookies().Add(b.ctx, c)
+// AddCookieContext adds a cookie.
+func (b *Browser) AddCookieContext(ctx context.Context, c w3cproto.Cookie) error {
+	return b.sess.Cookies().Add(ctx, c)
 }
 
-// DeleteCookie deletes cookies by name visible to the current page.
-func (b *Browser) DeleteCookie(name string) error {
-	return b.sess.Cookies().Delete(b.ctx, name)
+// DeleteCookieContext deletes cookies by name visible to the current page.
+func (b *Browser) DeleteCookieContext(ctx context.Context, name string) error {
+	return b.sess.Cookies().Delete(ctx, name)
 }
 
-// DeleteCookies deletes all cookies visible to the current page.
-func (b *Browser) DeleteCookies() error {
-	return b.sess.Cookies().DeleteAll(b.ctx)
+// DeleteCookiesContext deletes all cookies visible to the current page.
+func (b *Browser) DeleteCookiesContext(ctx context.Context) error {
+	return b.sess.Cookies().DeleteAll(ctx)
 }
 
-// NavigateTo navigates to a new URL.
-func (b *Browser) NavigateTo(u string) error {
-	return b.sess.Navigation().NavigateTo(b.ctx, u)
+// NavigateToContext navigates to a new URL.
+func (b *Browser) NavigateToContext(ctx context.Context, u string) error {
+	return b.sess.Navigation().NavigateTo(ctx, u)
 }
 
-// Url  navigates to a new URL (alias for NavigateTo).
-func (b *Browser) Url(u string) error {
-	return b.sess.Navigation().NavigateTo(b.ctx, u)
+// UrlContext  navigates to a new URL (alias for NavigateTo).
+func (b *Browser) UrlContext(ctx context.Context, u string) error {
+	return b.sess.Navigation().NavigateTo(ctx, u)
 }
 
-// CurrentURL returns the URL of the current page.
-func (b *Browser) CurrentURL() (url string, err error) {
-	return b.sess.Navigation().GetCurrentURL(b.ctx)
+// CurrentURLContext returns the URL of the current page.
+func (b *Browser) CurrentURLContext(ctx context.Context) (url string, err error) {
+	return b.sess.Navigation().GetCurrentURL(ctx)
 }
 
-// Back navigate backwards in the browser history, if possible.
-func (b *Browser) Back() error {
-	return b.sess.Navigation().Back(b.ctx)
+// BackContext navigate backwards in the browser history, if possible.
+func (b *Browser) BackContext(ctx context.Context) error {
+	return b.sess.Navigation().Back(ctx)
 }
 
-// Refresh refresh the current page.
-func (b *Browser) Refresh(ctx context.Context) error {
-	return b.sess.Navigation().Refresh(b.ctx)
+// RefreshContext refresh the current page.
+func (b *Browser) RefreshContext(ctx context.Context) error {
+	return b.sess.Navigation().Refresh(ctx)
 }
 
-// Title returns the current page title.
-func (b *Browser) Title(ctx context.Context) (title string, err error) {
-	return b.sess.Navigation().GetTitle(b.ctx)
+// TitleContext returns the current page title.
+func (b *Browser) TitleContext(ctx context.Context) (title string, err error) {
+	return b.sess.Navigation().GetTitle(ctx)
 }
 
-// Forward navigate forwards in the browser history, if possible.
-func (b *Browser) Forward() error {
-	return b.sess.Navigation().Forward(b.ctx)
+// ForwardContext navigate forwards in the browser history, if possible.
+func (b *Browser) ForwardContext(ctx context.Context) error {
+	return b.sess.Navigation().Forward(ctx)
 }
 
-// GetTimeout returns the timeouts implicit, pageLoad, script.
-func (b *Browser) GetTimeout() (w3cproto.Timeout, error) {
-	return b.sess.Timeouts().Get(b.ctx)
+// GetTimeoutContext returns the timeouts implicit, pageLoad, script.
+func (b *Browser) GetTimeoutContext(ctx context.Context) (w3cproto.Timeout, error) {
+	return b.sess.Timeouts().Get(ctx)
 }
 
-// SetImplicitTimeout sets the amount of time the browser should wait when
+// SetImplicitTimeoutContext sets the amount of time the browser should wait when
 // searching for elements. The timeout will be rounded to nearest millisecond.
-func (b *Browser) SetImplicitTimeout(d time.Duration) error {
-	return b.sess.Timeouts().SetImplicit(b.ctx, d)
+func (b *Browser) SetImplicitTimeoutContext(ctx context.Context, d time.Duration) error {
+	return b.sess.Timeouts().SetImplicit(ctx, d)
 }
 
-// SetPageLoadTimeout sets the amount of time the browser should wait when
+// SetPageLoadTimeoutContext sets the amount of time the browser should wait when
 // loading a page. The timeout will be rounded to nearest millisecond.
-func (b *Browser) SetPageLoadTimeout(d time.Duration) error {
-	return b.sess.Timeouts().SetPageLoad(b.ctx, d)
+func (b *Browser) SetPageLoadTimeoutContext(ctx context.Context, d time.Duration) error {
+	return b.sess.Timeouts().SetPageLoad(ctx, d)
 }
 
-// SetScriptTimeout sets the amount of time that asynchronous scripts
+// SetScriptTimeoutContext sets the amount of time that asynchronous scripts
 // are permitted to run before they are aborted. The timeout will be rounded
 // to nearest millisecond.
-func (b *Browser) SetScriptTimeout(d time.Duration) error {
-	return b.sess.Timeouts().SetScript(b.ctx, d)
+func (b *Browser) SetScriptTimeoutContext(ctx context.Context, d time.Duration) error {
+	return b.sess.Timeouts().SetScript(ctx, d)
 }
 
 // Capabilities returns the browser capabilities.
@@ -148,146 +195,151 @@ func (b *Browser) Capabilities() w3cproto.Capabilities {
 	return b.sess.Session().Capabilities()
 }
 
-// Status returns information about whether a browser  is in a state
+// StatusContext returns information about whether a browser  is in a state
 // in which it can create new sessions, but may additionally include arbitrary
 // meta information that is specific to the implementation.
-func (b *Browser) Status() (w3cproto.Status, error) {
-	return b.sess.Session().Status(b.ctx)
+func (b *Browser) StatusContext(ctx context.Context) (w3cproto.Status, error) {
+	return b.sess.Session().Status(ctx)
 }
 
-// FindElementByID finds an element on the page, starting from the document root.
-func (b *Browser) FindElementByID(id string) (we WebElement, err error) {
-	if len(id) == 0 {
-		return we, w3cproto.ErrInvalidArguments
-	}
-	if !strings.HasPrefix(id, "#") {
-		id = "#" + id
-	}
-	w3cWebElem, err := b.sess.Elements().FindOne(b.ctx, w3cproto.ByCSSSelector, id)
+// FindElementContext finds an element on the page, starting from the document root.
+func (b *Browser) FindElementContext(ctx context.Context, loc Locator) (we WebElement, err error) {
+	w3cWebElem, err := b.sess.Elements().FindOne(ctx, loc.Strategy, loc.Value)
 	if err != nil {
 		return we, err
 	}
-	return WebElement{
-		elem: w3cWebElem,
-		ctx:  b.ctx,
-		q: selector{
-			id:       id,
-			strategy: w3cproto.ByCSSSelector,
-		},
-	}, nil
+	return newWebElement(b.context(), b.sess, w3cWebElem, loc), nil
 }
 
-// FindElementByXPATH finds an element on the page, starting from the document root.
-func (b *Browser) FindElementByXPATH(xpath string) (we WebElement, err error) {
-	w3cWebElem, err := b.sess.Elements().FindOne(b.ctx, w3cproto.ByXPATH, xpath)
+// FindElementsContext finds multiple elements on the page, starting from the document root.
+func (b *Browser) FindElementsContext(ctx context.Context, loc Locator) ([]WebElement, error) {
+	w3cWebElems, err := b.sess.Elements().Find(ctx, loc.Strategy, loc.Value)
 	if err != nil {
-		return we, err
+		return nil, err
 	}
-	return WebElement{
-		elem: w3cWebElem,
-		ctx:  b.ctx,
-		q: selector{
-			id:       xpath,
-			strategy: w3cproto.ByXPATH,
-		},
-	}, nil
+	return newWebElements(b.context(), b.sess, w3cWebElems, loc), nil
 }
 
-// FindElementByLinkText finds an element on the page, starting from the document root.
-func (b *Browser) FindElementByLinkText(text string) (we WebElement, err error) {
-	w3cWebElem, err := b.sess.Elements().FindOne(b.ctx, w3cproto.ByLinkText, text)
+// ActiveElementContext returns the currently active element on the page.
+func (b *Browser) ActiveElementContext(ctx context.Context) (we WebElement, err error) {
+	w3cWebElem, err := b.sess.Elements().Active(ctx)
 	if err != nil {
 		return we, err
 	}
-	return WebElement{
-		elem: w3cWebElem,
-		ctx:  b.ctx,
-		q: selector{
-			id:       text,
-			strategy: w3cproto.ByLinkText,
-		},
-	}, nil
+	return newWebElement(b.context(), b.sess, w3cWebElem, Locator{}), nil
+}
+
+// FindElementByIDContext finds an element on the page, starting from the document root.
+func (b *Browser) FindElementByIDContext(ctx context.Context, id string) (we WebElement, err error) {
+	if len(id) == 0 {
+		return we, w3cproto.ErrInvalidArgument
+	}
+	if !strings.HasPrefix(id, "#") {
+		id = "#" + id
+	}
+	return b.FindElementContext(ctx, By.CSS(id))
 }
 
-// Windows returns the list of all window handles(ids) available to the session.
-func (b *Browser) Windows() (ids []w3cproto.WindowHandle, err error) {
-	handles, err := b.sess.Context().GetWindowHandles(b.ctx)
+// FindElementByXPATHContext finds an element on the page, starting from the document root.
+func (b *Browser) FindElementByXPATHContext(ctx context.Context, xpath string) (we WebElement, err error) {
+	return b.FindElementContext(ctx, By.XPath(xpath))
+}
+
+// FindElementByLinkTextContext finds an element on the page, starting from the document root.
+func (b *Browser) FindElementByLinkTextContext(ctx context.Context, text string) (we WebElement, err error) {
+	return b.FindElementContext(ctx, By.LinkText(text))
+}
+
+// WindowsContext returns the list of all window handles(ids) available to the session.
+func (b *Browser) WindowsContext(ctx context.Context) (ids []w3cproto.WindowHandle, err error) {
+	handles, err := b.sess.Context().GetWindowHandles(ctx)
 	if err != nil {
 		return nil, err
 	}
 	return handles, err
 }
 
-// ActiveWindow returns the ID of current window handle.
-func (b *Browser) ActiveWindow() (id w3cproto.WindowHandle, err error) {
-	handle, err := b.sess.Context().GetWindowHandle(b.ctx)
+// ActiveWindowContext returns the ID of current window handle.
+func (b *Browser) ActiveWindowContext(ctx context.Context) (id w3cproto.WindowHandle, err error) {
+	handle, err := b.sess.Context().GetWindowHandle(ctx)
 	if err != nil {
 		return id, err
 	}
 	return handle, nil
 }
 
-// CloseActiveWindow closes the current window.
-func (b *Browser) CloseActiveWindow() error {
-	_, err := b.sess.Context().CloseWindow(b.ctx)
+// CloseActiveWindowContext closes the current window.
+func (b *Browser) CloseActiveWindowContext(ctx context.Context) error {
+	_, err := b.sess.Context().CloseWindow(ctx)
 	return err
 }
 
-// CloseWindow closes a window.
-func (b *Browser) CloseWindow(id w3cproto.WindowHandle) error {
+// CloseWindowContext switches to the window and closes it. The other commands of the session
+// wait until the window is closed.
+func (b *Browser) CloseWindowContext(ctx context.Context, id w3cproto.WindowHandle) error {
 	if id.IsEmpty() {
 		return nil
 	}
-	if err := b.SwitchTo(id); err != nil {
+	ctx, release, err := b.sess.lock.acquire(ctx)
+	if err != nil {
 		return err
 	}
-	return b.CloseActiveWindow()
+	defer release()
+	if err := b.SwitchToContext(ctx, id); err != nil {
+		return err
+	}
+	return b.CloseActiveWindowContext(ctx)
 }
 
-// OpenTab creates a new tab.
-func (b *Browser) OpenTab() (id w3cproto.WindowHandle, err error) {
-	win, err := b.sess.Context().NewWindow(b.ctx, w3cproto.Tab)
+// OpenTabContext creates a new tab.
+func (b *Browser) OpenTabContext(ctx context.Context) (id w3cproto.WindowHandle, err error) {
+	win, err := b.sess.Context().NewWindow(ctx, w3cproto.Tab)
 	if err != nil {
 		return id, err
 	}
 	return win.Handle, nil
 }
 
-// OpenWindow creates a new window.
-func (b *Browser) OpenWindow() (id w3cproto.WindowHandle, err error) {
-	win, err := b.sess.Context().NewWindow(b.ctx, w3cproto.Win)
+// OpenWindowContext creates a new window.
+func (b *Browser) OpenWindowContext(ctx context.Context) (id w3cproto.WindowHandle, err error) {
+	win, err := b.sess.Context().NewWindow(ctx, w3cproto.Win)
 	if err != nil {
 		return id, err
 	}
 	return win.Handle, nil
 }
 
-// SwitchTo switches between tabs or windows.
-func (b *Browser) SwitchTo(id w3cproto.WindowHandle) error {
+// SwitchToContext switches between tabs or windows.
+func (b *Browser) SwitchToContext(ctx context.Context, id w3cproto.WindowHandle) error {
 	if id.IsEmpty() {
 		return w3cproto.ErrUnknownWindowHandler
 	}
-	return b.sess.Context().SwitchToWindow(b.ctx, id)
+	return b.sess.Context().SwitchToWindow(ctx, id)
 }
 
-// SwitchToFrame changes focus to another frame on the page.
-func (b *Browser) SwitchToFrame(id w3cproto.FrameHandle) error {
-	return b.sess.Context().SwitchToFrame(b.ctx, id)
+// SwitchToFrameContext changes focus to another frame on the page.
+func (b *Browser) SwitchToFrameContext(ctx context.Context, id w3cproto.FrameHandle) error {
+	return b.sess.Context().SwitchToFrame(ctx, id)
 }
 
-// SwitchToParentFrame changes focus back to parent frame.
-func (b *Browser) SwitchToParentFrame() error {
-	return b.sess.Context().SwitchToParentFrame(b.ctx)
+// SwitchToParentFrameContext changes focus back to parent frame.
+func (b *Browser) SwitchToParentFrameContext(ctx context.Context) error {
+	return b.sess.Context().SwitchToParentFrame(ctx)
 }
 
-// ResizeWindow alters the size or position of the operating system window.
-func (b *Browser) ResizeWindow(r w3cproto.Rect) (winRect w3cproto.Rect, err error) {
-	return b.sess.Context().SetRect(b.ctx, r)
+// ResizeWindowContext alters the size or position of the operating system window.
+func (b *Browser) ResizeWindowContext(ctx context.Context, r w3cproto.Rect) (winRect w3cproto.Rect, err error) {
+	return b.sess.Context().SetRect(ctx, r)
 }
 
-// MoveTo alters the position of the operating system window.
-func (b *Browser) MoveTo(x, y int) error {
-	rect, err := b.sess.Context().GetRect(b.ctx)
+// MoveToContext alters the position of the operating system window.
+func (b *Browser) MoveToContext(ctx context.Context, x, y int) error {
+	ctx, release, err := b.sess.lock.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+	rect, err := b.sess.Context().GetRect(ctx)
 	if err != nil {
 		return err
 	}
@@ -295,15 +347,20 @@ func (b *Browser) MoveTo(x, y int) error {
 	rect.X = x
 	rect.Y = y
 
-	if _, err := b.sess.Context().SetRect(b.ctx, rect); err != nil {
+	if _, err := b.sess.Context().SetRect(ctx, rect); err != nil {
 		return err
 	}
 	return nil
 }
 
-// ResizeTo alters the size of the operating system window.
-func (b *Browser) ResizeTo(width, height int) error {
-	rect, err := b.sess.Context().GetRect(b.ctx)
+// ResizeToContext alters the size of the operating system window.
+func (b *Browser) ResizeToContext(ctx context.Context, width, height int) error {
+	ctx, release, err := b.sess.lock.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+	rect, err := b.sess.Context().GetRect(ctx)
 	if err != nil {
 		return err
 	}
@@ -311,81 +368,219 @@ func (b *Browser) ResizeTo(width, height int) error {
 	rect.Width = width
 	rect.Height = height
 
-	if _, err := b.sess.Context().SetRect(b.ctx, rect); err != nil {
+	if _, err := b.sess.Context().SetRect(ctx, rect); err != nil {
 		return err
 	}
 	return nil
 }
 
-// ScreenSize returns a window size on the screen of the operating system.
-func (b *Browser) ScreenSize() (width int, height int, err error) {
-	rect, err := b.sess.Context().GetRect(b.ctx)
+// ScreenSizeContext returns a window size on the screen of the operating system.
+func (b *Browser) ScreenSizeContext(ctx context.Context) (width int, height int, err error) {
+	rect, err := b.sess.Context().GetRect(ctx)
 	if err != nil {
 		return width, height, err
 	}
 	return rect.Width, rect.Height, nil
 }
 
-// ScreenPosition returns a window position on the screen of the operating system.
-func (b *Browser) ScreenPosition() (x int, y int, err error) {
-	rect, err := b.sess.Context().GetRect(b.ctx)
+// ScreenPositionContext returns a window position on the screen of the operating system.
+func (b *Browser) ScreenPositionContext(ctx context.Context) (x int, y int, err error) {
+	rect, err := b.sess.Context().GetRect(ctx)
 	if err != nil {
 		return x, y, err
 	}
 	return rect.X, rect.Y, nil
 }
 
-// Maximize increases the window to the maximum available size without going full-screen.
-func (b *Browser) Maximize() error {
-	if _, err := b.sess.Context().Maximize(b.ctx); err != nil {
+// MaximizeContext increases the window to the maximum available size without going full-screen.
+func (b *Browser) MaximizeContext(ctx context.Context) error {
+	if _, err := b.sess.Context().Maximize(ctx); err != nil {
 		return err
 	}
 	return nil
 }
 
-// Minimize decreases the window to the minimum available size.
-func (b *Browser) Minimize() error {
-	if _, err := b.sess.Context().Minimize(b.ctx); err != nil {
+// MinimizeContext decreases the window to the minimum available size.
+func (b *Browser) MinimizeContext(ctx context.Context) error {
+	if _, err := b.sess.Context().Minimize(ctx); err != nil {
 		return err
 	}
 	return nil
 }
 
-// Fullscreen resizes the window to full screen.
-func (b *Browser) Fullscreen() error {
-	if _, err := b.sess.Context().Fullscreen(b.ctx); err != nil {
+// FullscreenContext resizes the window to full screen.
+func (b *Browser) FullscreenContext(ctx context.Context) error {
+	if _, err := b.sess.Context().Fullscreen(ctx); err != nil {
 		return err
 	}
 	return nil
 }
 
-// ScreenshotJPG takes a screenshot of the current page.
-func (b *Browser) ScreenshotJPG() (image.Image, error) {
-	return b.sess.ScreenshotJPG(b.ctx)
+// AlertTextContext returns the text of the currently displayed alert(), confirm(), or prompt() dialog.
+func (b *Browser) AlertTextContext(ctx context.Context) (string, error) {
+	return b.sess.Alert().Text(ctx)
+}
+
+// AcceptAlertContext accepts the currently displayed dialog.
+func (b *Browser) AcceptAlertContext(ctx context.Context) error {
+	return b.sess.Alert().Accept(ctx)
 }
 
-// ScreenshotPNG takes a screenshot of the current page.
-func (b *Browser) ScreenshotPNG() (image.Image, error) {
-	return b.sess.ScreenshotPNG(b.ctx)
+// DismissAlertContext dismisses the currently displayed dialog.
+func (b *Browser) DismissAlertContext(ctx context.Context) error {
+	return b.sess.Alert().Dismiss(ctx)
 }
 
-// Screenshot takes a screenshot of the current page.
-func (b *Browser) Screenshot() (io.Reader, error) {
-	return b.sess.ScreenCapture().Take(b.ctx)
+// SetAlertTextContext sets the text field of the currently displayed prompt() dialog.
+func (b *Browser) SetAlertTextContext(ctx context.Context, text string) error {
+	return b.sess.Alert().SetText(ctx, text)
 }
 
-// ElementScreenshot takes a screenshot of the element on the current page.
-func (b *Browser) ElementScreenshot(elementID string) (io.Reader, error) {
-	return b.sess.ScreenCapture().TakeElement(b.ctx, elementID)
+// SetPromptHandler sets the policy for the dialogs that block commands, e.g. AcceptPrompts(),
+// DismissPrompts() or a callback that decides by the dialog text. The blocked command is
+// retried once after the dialog has been handled. A nil handler disables the policy.
+func (b *Browser) SetPromptHandler(h PromptHandler) {
+	b.sess.SetPromptHandler(h)
+}
+
+// PerformActionsContext performs a sequence of actions of the input sources.
+func (b *Browser) PerformActionsContext(ctx context.Context, sources ...w3cproto.InputSource) error {
+	return b.sess.Actions().Perform(ctx, sources...)
+}
+
+// ReleaseActionsContext releases all the keys and pointer buttons that are currently depressed.
+func (b *Browser) ReleaseActionsContext(ctx context.Context) error {
+	return b.sess.Actions().Release(ctx)
+}
+
+// MoveToElementContext moves the mouse to the center of the element.
+func (b *Browser) MoveToElementContext(ctx context.Context, we WebElement) error {
+	mouse := w3cproto.NewPointerSource(mouseInputID, w3cproto.MousePointer).
+		PointerMove(0, w3cproto.ElementOrigin(we.elem), 0, 0)
+	return b.PerformActionsContext(ctx, mouse)
+}
+
+// DoubleClickContext double-clicks on the element.
+func (b *Browser) DoubleClickContext(ctx context.Context, we WebElement) error {
+	mouse := w3cproto.NewPointerSource(mouseInputID, w3cproto.MousePointer).
+		PointerMove(0, w3cproto.ElementOrigin(we.elem), 0, 0).
+		PointerDown(w3cproto.LeftButton).
+		PointerUp(w3cproto.LeftButton).
+		PointerDown(w3cproto.LeftButton).
+		PointerUp(w3cproto.LeftButton)
+	return b.PerformActionsContext(ctx, mouse)
+}
+
+// ContextClickContext right-clicks on the element.
+func (b *Browser) ContextClickContext(ctx context.Context, we WebElement) error {
+	mouse := w3cproto.NewPointerSource(mouseInputID, w3cproto.MousePointer).
+		PointerMove(0, w3cproto.ElementOrigin(we.elem), 0, 0).
+		PointerDown(w3cproto.RightButton).
+		PointerUp(w3cproto.RightButton)
+	return b.PerformActionsContext(ctx, mouse)
+}
+
+// ClickWithKeysContext clicks on the element while holding down the modifier keys (e.g. ShiftKey, ControlKey).
+func (b *Browser) ClickWithKeysContext(ctx context.Context, we WebElement, keys ...w3cproto.Key) error {
+	keyboard := w3cproto.NewKeySource(keyboardInputID)
+	mouse := w3cproto.NewPointerSource(mouseInputID, w3cproto.MousePointer)
+	for _, key := range keys {
+		keyboard.KeyDown(key)
+	}
+	w3cproto.AlignTicks(keyboard, mouse)
+	mouse.PointerMove(0, w3cproto.ElementOrigin(we.elem), 0, 0).
+		PointerDown(w3cproto.LeftButton).
+		PointerUp(w3cproto.LeftButton)
+	w3cproto.AlignTicks(keyboard, mouse)
+	for _, key := range keys {
+		keyboard.KeyUp(key)
+	}
+	return b.PerformActionsContext(ctx, keyboard, mouse)
+}
+
+// DragAndDropContext drags the source element and drops it on the target element.
+func (b *Browser) DragAndDropContext(ctx context.Context, source WebElement, target WebElement) error {
+	mouse := w3cproto.NewPointerSource(mouseInputID, w3cproto.MousePointer).
+		PointerMove(0, w3cproto.ElementOrigin(source.elem), 0, 0).
+		PointerDown(w3cproto.LeftButton).
+		PointerMove(dragDuration, w3cproto.ElementOrigin(target.elem), 0, 0).
+		PointerUp(w3cproto.LeftButton)
+	return b.PerformActionsContext(ctx, mouse)
+}
+
+// DragAndDropByContext drags the source element and drops it at the x, y offset from its center.
+func (b *Browser) DragAndDropByContext(ctx context.Context, source WebElement, x, y int) error {
+	mouse := w3cproto.NewPointerSource(mouseInputID, w3cproto.MousePointer).
+		PointerMove(0, w3cproto.ElementOrigin(source.elem), 0, 0).
+		PointerDown(w3cproto.LeftButton).
+		PointerMove(dragDuration, w3cproto.PointerOrigin, x, y).
+		PointerUp(w3cproto.LeftButton)
+	return b.PerformActionsContext(ctx, mouse)
+}
+
+// ScrollByContext scrolls the page by the deltaX, deltaY offset.
+func (b *Browser) ScrollByContext(ctx context.Context, deltaX, deltaY int) error {
+	wheel := w3cproto.NewWheelSource(wheelInputID).
+		Scroll(0, w3cproto.ViewportOrigin, 0, 0, deltaX, deltaY)
+	return b.PerformActionsContext(ctx, wheel)
+}
+
+// ScreenshotJPGContext takes a screenshot of the current page.
+func (b *Browser) ScreenshotJPGContext(ctx context.Context) (image.Image, error) {
+	return b.sess.ScreenshotJPG(ctx)
+}
+
+// ScreenshotPNGContext takes a screenshot of the current page.
+func (b *Browser) ScreenshotPNGContext(ctx context.Context) (image.Image, error) {
+	return b.sess.ScreenshotPNG(ctx)
+}
+
+// ScreenshotContext takes a screenshot of the current page.
+func (b *Browser) ScreenshotContext(ctx context.Context) (io.Reader, error) {
+	return b.sess.ScreenCapture().Take(ctx)
+}
+
+// ElementScreenshotContext takes a screenshot of the element on the current page.
+func (b *Browser) ElementScreenshotContext(ctx context.Context, elementID string) (io.Reader, error) {
+	return b.sess.ScreenCapture().TakeElement(ctx, elementID)
+}
+
+// PrintPDFContext renders the current page as a PDF document. The default print options
+// are used if opts is nil, the zero orientation, scale and page size of opts are replaced
+// by the default ones.
+func (b *Browser) PrintPDFContext(ctx context.Context, opts *w3cproto.PrintOptions) (io.Reader, error) {
+	printOpts := w3cproto.DefaultPrintOptions()
+	if opts != nil {
+		printOpts = opts.WithDefaults()
+	}
+	return b.sess.Print().PDF(ctx, printOpts)
+}
+
+// SavePDFContext renders the current page as a PDF document and writes it to the file.
+func (b *Browser) SavePDFContext(ctx context.Context, filename string, opts *w3cproto.PrintOptions) error {
+	reader, err := b.PrintPDFContext(ctx, opts)
+	if err != nil {
+		return err
+	}
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(file, reader); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
 }
 
-func (b *Browser) Close() error {
+// CloseContext deletes the session and stops the driver of the browser.
+func (b *Browser) CloseContext(ctx context.Context) error {
 	defer func() {
 		if b.driver != nil {
-			_ = b.driver.Stop(b.ctx)
+			_ = b.driver.Stop(ctx)
 		}
 	}()
-	return b.sess.Close(b.ctx)
+	return b.sess.Close(ctx)
 }
 
 func freePort() (int, error) {
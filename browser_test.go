@@ -0,0 +1,103 @@
+package webdriver
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mediabuyerbot/go-webdriver/pkg/w3cproto"
+)
+
+func newTestBrowser(t *testing.T) (*Browser, *w3cproto.MockDoer, func()) {
+	ctrl := gomock.NewController(t)
+	cli := w3cproto.NewMockDoer(ctrl)
+	sess := w3cproto.NewMockSession(ctrl)
+	sess.EXPECT().ID().Return("123").AnyTimes()
+	return newBrowser(context.Background(), newSession(cli, sess), nil), cli, ctrl.Finish
+}
+
+type ctxKey struct{}
+
+func TestBrowser_Context(t *testing.T) {
+	browser, cli, done := newTestBrowser(t)
+	defer done()
+
+	// the methods with the context parameter send the commands with the context
+	ctx := context.WithValue(context.Background(), ctxKey{}, "call")
+	cli.EXPECT().Do(ctx, http.MethodGet, "/session/123/title", nil).Times(1).Return(
+		&w3cproto.Response{Value: []byte(`"title"`)}, nil)
+	title, err := browser.TitleContext(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, "title", title)
+	cli.EXPECT().Do(ctx, http.MethodPost, "/session/123/refresh", nil).Times(1).Return(
+		&w3cproto.Response{Value: []byte(`null`)}, nil)
+	assert.Nil(t, browser.RefreshContext(ctx))
+
+	// the compat methods with the context parameter send the commands with the context
+	cli.EXPECT().Do(ctx, http.MethodGet, "/session/123/title", nil).Times(1).Return(
+		&w3cproto.Response{Value: []byte(`"title"`)}, nil)
+	title, err = browser.Title(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, "title", title)
+	cli.EXPECT().Do(ctx, http.MethodPost, "/session/123/refresh", nil).Times(1).Return(
+		&w3cproto.Response{Value: []byte(`null`)}, nil)
+	assert.Nil(t, browser.Refresh(ctx))
+	cli.EXPECT().Do(ctx, http.MethodPost, "/session/123/element", gomock.Any()).Times(1).Return(
+		&w3cproto.Response{Value: []byte(`{"element-6066-11e4-a52e-4f735466cecf":"1"}`)}, nil)
+	we, err := browser.FindElementContext(ctx, By.CSS("#elem"))
+	assert.Nil(t, err)
+	cli.EXPECT().Do(ctx, http.MethodPost, "/session/123/element/1/click", nil).Times(1).Return(
+		&w3cproto.Response{Value: []byte(`null`)}, nil)
+	assert.Nil(t, we.ClickContext(ctx))
+
+	// the methods without the context parameter use the default context
+	defaultCtx := context.WithValue(context.Background(), ctxKey{}, "default")
+	browser.WithContext(defaultCtx)
+	cli.EXPECT().Do(defaultCtx, http.MethodGet, "/session/123/url", nil).Times(1).Return(
+		&w3cproto.Response{Value: []byte(`"https://example.com/"`)}, nil)
+	url, err := browser.CurrentURL()
+	assert.Nil(t, err)
+	assert.Equal(t, "https://example.com/", url)
+
+	// the element found with the context parameter keeps the default context
+	cli.EXPECT().Do(defaultCtx, http.MethodGet, "/session/123/element/1/text", nil).Times(1).Return(
+		&w3cproto.Response{Value: []byte(`"text"`)}, nil)
+	cli.EXPECT().Do(ctx, http.MethodPost, "/session/123/element", gomock.Any()).Times(1).Return(
+		&w3cproto.Response{Value: []byte(`{"element-6066-11e4-a52e-4f735466cecf":"1"}`)}, nil)
+	we, err = browser.FindElementContext(ctx, By.CSS("#elem"))
+	assert.Nil(t, err)
+	text, err := we.Text()
+	assert.Nil(t, err)
+	assert.Equal(t, "text", text)
+
+	// returns context error
+	cancelCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	cli.EXPECT().Do(cancelCtx, http.MethodGet, "/session/123/title", nil).MaxTimes(1).Return(nil, context.Canceled)
+	_, err = browser.TitleContext(cancelCtx)
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestBrowser_PrintPDFContext(t *testing.T) {
+	browser, cli, done := newTestBrowser(t)
+	defer done()
+	ctx := context.Background()
+
+	// the partial options are completed with the defaults
+	cli.EXPECT().Do(ctx, http.MethodPost, "/session/123/print", gomock.Any()).Times(1).DoAndReturn(
+		func(_ context.Context, _ string, _ string, p w3cproto.Params) (*w3cproto.Response, error) {
+			assert.Equal(t, w3cproto.Landscape, p["orientation"])
+			assert.Equal(t, float64(1), p["scale"])
+			assert.Equal(t, w3cproto.PageSize{Width: 21.59, Height: 27.94}, p["page"])
+			return &w3cproto.Response{Value: []byte(`"JVBERi0="`)}, nil
+		})
+	reader, err := browser.PrintPDFContext(ctx, &w3cproto.PrintOptions{Orientation: w3cproto.Landscape})
+	assert.Nil(t, err)
+	data, err := ioutil.ReadAll(reader)
+	assert.Nil(t, err)
+	assert.Equal(t, "%PDF-", string(data))
+}
@@ -2,52 +2,174 @@ package webdriver
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
-	"os"
+	"runtime"
+	"time"
 
 	"github.com/mitchellh/go-homedir"
 
 	bin "github.com/mediabuyerbot/go-webdriver/third_party/drivers"
 
 	"github.com/mediabuyerbot/go-webdriver/pkg/chromedriver"
+	"github.com/mediabuyerbot/go-webdriver/pkg/discovery"
+	"github.com/mediabuyerbot/go-webdriver/pkg/download"
 )
 
 const (
 	EnvChromeDriverPath = "CHROMEDRIVER_PATH"
 )
 
+var chromeDriver = discovery.Driver{
+	Name:     discovery.ChromeDriver.Name,
+	Env:      EnvChromeDriverPath,
+	Fallback: bin.ChromeDriver64,
+}
+
+// ChromeLauncherOptions configures the chromedriver started by LaunchChrome.
+type ChromeLauncherOptions struct {
+	// Context owns the lifetime of the driver and the browser. The driver is shut down
+	// when the context is done. context.Background() is used if nil.
+	Context context.Context
+
+	// DriverPath is the path to the chromedriver binary. If empty the driver is looked up
+	// in the CHROMEDRIVER_PATH environment variable, the PATH directories, the per-user
+	// cache directory and the bundled binaries. If the driver is not found, the release
+	// compatible with the browser is downloaded into the cache directory.
+	DriverPath string
+
+	// DisableDownload disables the download of the driver.
+	DisableDownload bool
+
+	// Downloader downloads the driver. A manager with the default settings is used if nil.
+	Downloader *download.Manager
+
+	// DriverSource resolves the driver releases, e.g. from a mirror.
+	// download.ChromeDriverSource with the default URLs is used if nil.
+	DriverSource download.Source
+
+	// SkipVersionCheck disables the check that the major versions of the driver and the browser are equal.
+	SkipVersionCheck bool
+
+	// Port is the port the driver listens on. A free port is picked if zero.
+	Port int
+
+	// LogPath is the file the driver writes the log to. The "~" prefix is expanded
+	// to the home directory. The driver logs to the Output if empty.
+	LogPath  string
+	LogLevel chromedriver.LogLevel
+
+	// Verbose makes the driver log verbosely.
+	Verbose bool
+
+	// Replayable makes the driver log verbosely and not truncate long strings, so that the log can be replayed.
+	Replayable bool
+
+	// Output receives the stderr of the driver. The output is discarded if nil.
+	Output io.Writer
+
+	// Env is the list of the environment variables in the form "key=value"
+	// added to the environment of the driver.
+	Env []string
+
+	// WhitelistedIPs is the list of the remote IPv4 addresses which are allowed to connect to the driver.
+	WhitelistedIPs []string
+
+	// StartTimeout is the time the driver has to become ready.
+	StartTimeout time.Duration
+}
+
+// DefaultChromeLauncherOptions returns the options used by Chrome.
+func DefaultChromeLauncherOptions() *ChromeLauncherOptions {
+	return &ChromeLauncherOptions{
+		Context:        context.Background(),
+		LogPath:        "~/chrome-driver.log",
+		LogLevel:       chromedriver.Severe,
+		Output:         log.Writer(),
+		WhitelistedIPs: []string{"0.0.0.0"},
+	}
+}
+
+// Chrome starts the chromedriver with the default launcher options and creates a new session.
 func Chrome(opts *ChromeOptionsBuilder) (*Browser, error) {
+	return LaunchChrome(DefaultChromeLauncherOptions(), opts)
+}
+
+// LaunchChrome starts the chromedriver configured by the launcher options and creates a new session.
+func LaunchChrome(lo *ChromeLauncherOptions, opts *ChromeOptionsBuilder) (*Browser, error) {
+	if lo == nil {
+		lo = DefaultChromeLauncherOptions()
+	}
 	if opts == nil {
 		opts = ChromeOptions()
 	}
-	port, err := freePort()
-	if err != nil {
-		return nil, err
+	ctx := lo.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	port := lo.Port
+	if port == 0 {
+		p, err := freePort()
+		if err != nil {
+			return nil, err
+		}
+		port = p
+	}
+	driverPath, err := chromeDriver.Find(lo.DriverPath)
+	if errors.Is(err, discovery.ErrDriverNotFound) && len(lo.DriverPath) == 0 && !lo.DisableDownload {
+		driverPath, err = downloadChromeDriver(ctx, lo, opts)
 	}
-	done := make(chan error)
-	logPath, err := homedir.Expand("~/chrome-driver.log")
 	if err != nil {
 		return nil, err
 	}
-	driverPath := os.Getenv(EnvChromeDriverPath)
-	if len(driverPath) == 0 {
-		driverPath = bin.ChromeDriver64()
+	if !lo.SkipVersionCheck {
+		if err := checkChrome(ctx, driverPath, opts); err != nil {
+			return nil, err
+		}
 	}
-	driver, err := chromedriver.New(driverPath,
+
+	done := make(chan error)
+	driverOpts := []chromedriver.Option{
 		chromedriver.WithPort(port),
-		chromedriver.WithStderr(log.Writer()),
-		chromedriver.WithWhitelistedIps([]string{"0.0.0.0"}),
-		chromedriver.WithLogPath(logPath),
-		chromedriver.WithLogLevel(chromedriver.Severe),
+		chromedriver.WithStartTimeout(lo.StartTimeout),
 		chromedriver.WithRunHook(func(pid int) {
 			done <- nil
 		}),
-	)
+	}
+	if lo.Output != nil {
+		driverOpts = append(driverOpts, chromedriver.WithStderr(lo.Output))
+	}
+	if len(lo.LogPath) > 0 {
+		logPath, err := homedir.Expand(lo.LogPath)
+		if err != nil {
+			return nil, err
+		}
+		driverOpts = append(driverOpts, chromedriver.WithLogPath(logPath))
+	}
+	if len(lo.LogLevel) > 0 {
+		driverOpts = append(driverOpts, chromedriver.WithLogLevel(lo.LogLevel))
+	}
+	if lo.Verbose {
+		driverOpts = append(driverOpts, chromedriver.WithVerbose())
+	}
+	if lo.Replayable {
+		driverOpts = append(driverOpts, chromedriver.WithReplayable())
+	}
+	if len(lo.Env) > 0 {
+		driverOpts = append(driverOpts, chromedriver.WithEnv(lo.Env...))
+	}
+	if len(lo.WhitelistedIPs) > 0 {
+		driverOpts = append(driverOpts, chromedriver.WithWhitelistedIps(lo.WhitelistedIPs))
+	}
+	if j := driverJanitor(); j != nil {
+		driverOpts = append(driverOpts, chromedriver.WithJanitor(j))
+	}
+	driver, err := chromedriver.New(driverPath, driverOpts...)
 	if err != nil {
 		return nil, err
 	}
-	ctx := context.Background()
 	go func() {
 		if err := driver.Run(ctx); err != nil {
 			select {
@@ -70,9 +192,48 @@ func Chrome(opts *ChromeOptionsBuilder) (*Browser, error) {
 		_ = driver.Stop(ctx)
 		return nil, err
 	}
-	return &Browser{
-		ctx:    ctx,
-		driver: driver,
-		sess:   sess,
-	}, nil
+	return newBrowser(ctx, sess, driver), nil
+}
+
+// checkChrome compares the versions of the driver and the browser binary of the options.
+// The check is skipped if the browser binary is not found.
+func checkChrome(ctx context.Context, driverPath string, opts *ChromeOptionsBuilder) error {
+	if runtime.GOOS == "windows" {
+		// chrome.exe does not print the version
+		return nil
+	}
+	browserPath, ok := chromeBinary(opts)
+	if !ok {
+		return nil
+	}
+	return discovery.CheckChrome(ctx, driverPath, browserPath)
+}
+
+// downloadChromeDriver downloads the driver compatible with the browser binary of the options.
+func downloadChromeDriver(ctx context.Context, lo *ChromeLauncherOptions, opts *ChromeOptionsBuilder) (string, error) {
+	browserPath, ok := chromeBinary(opts)
+	if !ok {
+		return "", fmt.Errorf("%w: chromedriver (Chrome is not found to download the compatible driver)",
+			discovery.ErrDriverNotFound)
+	}
+	version, err := discovery.BinaryVersion(ctx, browserPath)
+	if err != nil {
+		return "", err
+	}
+	manager := lo.Downloader
+	if manager == nil {
+		manager = &download.Manager{}
+	}
+	var src download.Source = download.ChromeDriverSource{}
+	if lo.DriverSource != nil {
+		src = lo.DriverSource
+	}
+	return manager.Install(ctx, src, version)
+}
+
+func chromeBinary(opts *ChromeOptionsBuilder) (string, bool) {
+	if path := opts.chromeCapabilities.GetString(ChromeCapabilityBinaryName); len(path) > 0 {
+		return path, true
+	}
+	return discovery.ChromeBinary()
 }
@@ -0,0 +1,91 @@
+package webdriver
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mediabuyerbot/go-webdriver/pkg/chromedriver"
+	"github.com/mediabuyerbot/go-webdriver/pkg/discovery"
+	"github.com/mediabuyerbot/go-webdriver/pkg/download"
+)
+
+func TestDefaultChromeLauncherOptions(t *testing.T) {
+	lo := DefaultChromeLauncherOptions()
+	assert.Equal(t, context.Background(), lo.Context)
+	assert.Equal(t, "~/chrome-driver.log", lo.LogPath)
+	assert.Equal(t, chromedriver.Severe, lo.LogLevel)
+	assert.Equal(t, []string{"0.0.0.0"}, lo.WhitelistedIPs)
+	assert.NotNil(t, lo.Output)
+	assert.Empty(t, lo.DriverPath)
+	assert.Zero(t, lo.Port)
+}
+
+func TestLaunchChrome(t *testing.T) {
+	// returns error if the driver is not found
+	browser, err := LaunchChrome(&ChromeLauncherOptions{
+		DriverPath: "/path/to/missing/chromedriver",
+	}, nil)
+	assert.Error(t, err)
+	assert.Nil(t, browser)
+}
+
+func TestDownloadChromeDriver(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("chrome.exe does not print the version")
+	}
+	tmp, err := ioutil.TempDir("", "webdriver")
+	assert.Nil(t, err)
+	defer os.RemoveAll(tmp)
+	defer os.Setenv("HOME", os.Getenv("HOME"))
+	defer os.Setenv("XDG_CACHE_HOME", os.Getenv("XDG_CACHE_HOME"))
+	assert.Nil(t, os.Setenv("HOME", tmp))
+	assert.Nil(t, os.Setenv("XDG_CACHE_HOME", filepath.Join(tmp, "cache")))
+
+	chromePath := filepath.Join(tmp, "chrome")
+	assert.Nil(t, ioutil.WriteFile(chromePath, []byte("#!/bin/sh\necho 'Google Chrome 120.0.6099.109'\n"), 0755))
+
+	// Chrome for Testing publishes no checksums
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/LATEST_RELEASE_120":
+			_, _ = w.Write([]byte("120.0.6099.109"))
+		case strings.HasSuffix(r.URL.Path, ".zip"):
+			// /<version>/<platform>/chromedriver-<platform>.zip
+			platform := strings.Split(r.URL.Path, "/")[2]
+			buf := new(bytes.Buffer)
+			zw := zip.NewWriter(buf)
+			fw, _ := zw.Create("chromedriver-" + platform + "/chromedriver")
+			_, _ = fw.Write([]byte("#!/bin/sh\necho 'ChromeDriver 120.0.6099.109'\n"))
+			_ = zw.Close()
+			_, _ = w.Write(buf.Bytes())
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	// the default downloader installs the release of the default source layout
+	lo := DefaultChromeLauncherOptions()
+	lo.DriverSource = download.ChromeDriverSource{BaseURL: srv.URL}
+	path, err := downloadChromeDriver(context.Background(), lo, ChromeOptions().SetBinary(chromePath))
+	if errors.Is(err, download.ErrUnsupportedPlatform) {
+		t.Skip(err)
+	}
+	assert.Nil(t, err)
+	cacheDir, err := discovery.CacheDir()
+	assert.Nil(t, err)
+	assert.Equal(t, discovery.ChromeDriver.CachePath(cacheDir, "120.0.6099.109"), path)
+	assert.Nil(t, discovery.CheckChrome(context.Background(), path, chromePath))
+}
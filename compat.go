@@ -0,0 +1,443 @@
+package webdriver
+
+import (
+	"context"
+	"image"
+	"io"
+	"time"
+
+	"github.com/mediabuyerbot/go-webdriver/pkg/w3cproto"
+)
+
+// The methods without the context parameter are kept for compatibility. The methods of Browser
+// use the default context of the browser, see Browser.WithContext. The methods of WebElement and
+// ShadowRoot use the default context of the browser at the moment the element was found.
+
+// Execute calls ExecuteContext with the default context.
+func (b *Browser) Execute(script string, args []interface{}) ([]byte, error) {
+	return b.ExecuteContext(b.context(), script, args)
+}
+
+// ExecuteAsync calls ExecuteAsyncContext with the default context.
+func (b *Browser) ExecuteAsync(script string, args []interface{}) ([]byte, error) {
+	return b.ExecuteAsyncContext(b.context(), script, args)
+}
+
+// Source calls SourceContext with the default context.
+func (b *Browser) Source() (source string, err error) {
+	return b.SourceContext(b.context())
+}
+
+// Cookies calls CookiesContext with the default context.
+func (b *Browser) Cookies() ([]w3cproto.Cookie, error) {
+	return b.CookiesContext(b.context())
+}
+
+// GetCookie calls GetCookieContext with the default context.
+func (b *Browser) GetCookie(name string) (w3cproto.Cookie, error) {
+	return b.GetCookieContext(b.context(), name)
+}
+
+// AddCookie calls AddCookieContext with the default context.
+func (b *Browser) AddCookie(c w3cproto.Cookie) error {
+	return b.AddCookieContext(b.context(), c)
+}
+
+// DeleteCookie calls DeleteCookieContext with the default context.
+func (b *Browser) DeleteCookie(name string) error {
+	return b.DeleteCookieContext(b.context(), name)
+}
+
+// DeleteCookies calls DeleteCookiesContext with the default context.
+func (b *Browser) DeleteCookies() error {
+	return b.DeleteCookiesContext(b.context())
+}
+
+// NavigateTo calls NavigateToContext with the default context.
+func (b *Browser) NavigateTo(u string) error {
+	return b.NavigateToContext(b.context(), u)
+}
+
+// Url calls UrlContext with the default context.
+func (b *Browser) Url(u string) error {
+	return b.UrlContext(b.context(), u)
+}
+
+// CurrentURL calls CurrentURLContext with the default context.
+func (b *Browser) CurrentURL() (url string, err error) {
+	return b.CurrentURLContext(b.context())
+}
+
+// Back calls BackContext with the default context.
+func (b *Browser) Back() error {
+	return b.BackContext(b.context())
+}
+
+// Forward calls ForwardContext with the default context.
+func (b *Browser) Forward() error {
+	return b.ForwardContext(b.context())
+}
+
+// Refresh calls RefreshContext with the context.
+//
+// Deprecated: use RefreshContext.
+func (b *Browser) Refresh(ctx context.Context) error {
+	return b.RefreshContext(ctx)
+}
+
+// Title calls TitleContext with the context.
+//
+// Deprecated: use TitleContext.
+func (b *Browser) Title(ctx context.Context) (title string, err error) {
+	return b.TitleContext(ctx)
+}
+
+// GetTimeout calls GetTimeoutContext with the default context.
+func (b *Browser) GetTimeout() (w3cproto.Timeout, error) {
+	return b.GetTimeoutContext(b.context())
+}
+
+// SetImplicitTimeout calls SetImplicitTimeoutContext with the default context.
+func (b *Browser) SetImplicitTimeout(d time.Duration) error {
+	return b.SetImplicitTimeoutContext(b.context(), d)
+}
+
+// SetPageLoadTimeout calls SetPageLoadTimeoutContext with the default context.
+func (b *Browser) SetPageLoadTimeout(d time.Duration) error {
+	return b.SetPageLoadTimeoutContext(b.context(), d)
+}
+
+// SetScriptTimeout calls SetScriptTimeoutContext with the default context.
+func (b *Browser) SetScriptTimeout(d time.Duration) error {
+	return b.SetScriptTimeoutContext(b.context(), d)
+}
+
+// Status calls StatusContext with the default context.
+func (b *Browser) Status() (w3cproto.Status, error) {
+	return b.StatusContext(b.context())
+}
+
+// FindElement calls FindElementContext with the default context.
+func (b *Browser) FindElement(loc Locator) (we WebElement, err error) {
+	return b.FindElementContext(b.context(), loc)
+}
+
+// FindElements calls FindElementsContext with the default context.
+func (b *Browser) FindElements(loc Locator) ([]WebElement, error) {
+	return b.FindElementsContext(b.context(), loc)
+}
+
+// ActiveElement calls ActiveElementContext with the default context.
+func (b *Browser) ActiveElement() (we WebElement, err error) {
+	return b.ActiveElementContext(b.context())
+}
+
+// Windows calls WindowsContext with the default context.
+func (b *Browser) Windows() (ids []w3cproto.WindowHandle, err error) {
+	return b.WindowsContext(b.context())
+}
+
+// ActiveWindow calls ActiveWindowContext with the default context.
+func (b *Browser) ActiveWindow() (id w3cproto.WindowHandle, err error) {
+	return b.ActiveWindowContext(b.context())
+}
+
+// CloseActiveWindow calls CloseActiveWindowContext with the default context.
+func (b *Browser) CloseActiveWindow() error {
+	return b.CloseActiveWindowContext(b.context())
+}
+
+// OpenTab calls OpenTabContext with the default context.
+func (b *Browser) OpenTab() (id w3cproto.WindowHandle, err error) {
+	return b.OpenTabContext(b.context())
+}
+
+// OpenWindow calls OpenWindowContext with the default context.
+func (b *Browser) OpenWindow() (id w3cproto.WindowHandle, err error) {
+	return b.OpenWindowContext(b.context())
+}
+
+// SwitchTo calls SwitchToContext with the default context.
+func (b *Browser) SwitchTo(id w3cproto.WindowHandle) error {
+	return b.SwitchToContext(b.context(), id)
+}
+
+// SwitchToFrame calls SwitchToFrameContext with the default context.
+func (b *Browser) SwitchToFrame(id w3cproto.FrameHandle) error {
+	return b.SwitchToFrameContext(b.context(), id)
+}
+
+// SwitchToParentFrame calls SwitchToParentFrameContext with the default context.
+func (b *Browser) SwitchToParentFrame() error {
+	return b.SwitchToParentFrameContext(b.context())
+}
+
+// ResizeWindow calls ResizeWindowContext with the default context.
+func (b *Browser) ResizeWindow(r w3cproto.Rect) (winRect w3cproto.Rect, err error) {
+	return b.ResizeWindowContext(b.context(), r)
+}
+
+// MoveTo calls MoveToContext with the default context.
+func (b *Browser) MoveTo(x, y int) error {
+	return b.MoveToContext(b.context(), x, y)
+}
+
+// ResizeTo calls ResizeToContext with the default context.
+func (b *Browser) ResizeTo(width, height int) error {
+	return b.ResizeToContext(b.context(), width, height)
+}
+
+// ScreenSize calls ScreenSizeContext with the default context.
+func (b *Browser) ScreenSize() (width int, height int, err error) {
+	return b.ScreenSizeContext(b.context())
+}
+
+// ScreenPosition calls ScreenPositionContext with the default context.
+func (b *Browser) ScreenPosition() (x int, y int, err error) {
+	return b.ScreenPositionContext(b.context())
+}
+
+// Maximize calls MaximizeContext with the default context.
+func (b *Browser) Maximize() error {
+	return b.MaximizeContext(b.context())
+}
+
+// Minimize calls MinimizeContext with the default context.
+func (b *Browser) Minimize() error {
+	return b.MinimizeContext(b.context())
+}
+
+// Fullscreen calls FullscreenContext with the default context.
+func (b *Browser) Fullscreen() error {
+	return b.FullscreenContext(b.context())
+}
+
+// AlertText calls AlertTextContext with the default context.
+func (b *Browser) AlertText() (string, error) {
+	return b.AlertTextContext(b.context())
+}
+
+// AcceptAlert calls AcceptAlertContext with the default context.
+func (b *Browser) AcceptAlert() error {
+	return b.AcceptAlertContext(b.context())
+}
+
+// DismissAlert calls DismissAlertContext with the default context.
+func (b *Browser) DismissAlert() error {
+	return b.DismissAlertContext(b.context())
+}
+
+// SetAlertText calls SetAlertTextContext with the default context.
+func (b *Browser) SetAlertText(text string) error {
+	return b.SetAlertTextContext(b.context(), text)
+}
+
+// PerformActions calls PerformActionsContext with the default context.
+func (b *Browser) PerformActions(sources ...w3cproto.InputSource) error {
+	return b.PerformActionsContext(b.context(), sources...)
+}
+
+// ReleaseActions calls ReleaseActionsContext with the default context.
+func (b *Browser) ReleaseActions() error {
+	return b.ReleaseActionsContext(b.context())
+}
+
+// ScreenshotJPG calls ScreenshotJPGContext with the default context.
+func (b *Browser) ScreenshotJPG() (image.Image, error) {
+	return b.ScreenshotJPGContext(b.context())
+}
+
+// ScreenshotPNG calls ScreenshotPNGContext with the default context.
+func (b *Browser) ScreenshotPNG() (image.Image, error) {
+	return b.ScreenshotPNGContext(b.context())
+}
+
+// Screenshot calls ScreenshotContext with the default context.
+func (b *Browser) Screenshot() (io.Reader, error) {
+	return b.ScreenshotContext(b.context())
+}
+
+// ElementScreenshot calls ElementScreenshotContext with the default context.
+func (b *Browser) ElementScreenshot(elementID string) (io.Reader, error) {
+	return b.ElementScreenshotContext(b.context(), elementID)
+}
+
+// PrintPDF calls PrintPDFContext with the default context.
+func (b *Browser) PrintPDF(opts *w3cproto.PrintOptions) (io.Reader, error) {
+	return b.PrintPDFContext(b.context(), opts)
+}
+
+// Close calls CloseContext with the default context.
+func (b *Browser) Close() error {
+	return b.CloseContext(b.context())
+}
+
+// FindElementByID calls FindElementByIDContext with the default context.
+func (b *Browser) FindElementByID(id string) (we WebElement, err error) {
+	return b.FindElementByIDContext(b.context(), id)
+}
+
+// FindElementByXPATH calls FindElementByXPATHContext with the default context.
+func (b *Browser) FindElementByXPATH(xpath string) (we WebElement, err error) {
+	return b.FindElementByXPATHContext(b.context(), xpath)
+}
+
+// FindElementByLinkText calls FindElementByLinkTextContext with the default context.
+func (b *Browser) FindElementByLinkText(text string) (we WebElement, err error) {
+	return b.FindElementByLinkTextContext(b.context(), text)
+}
+
+// CloseWindow calls CloseWindowContext with the default context.
+func (b *Browser) CloseWindow(id w3cproto.WindowHandle) error {
+	return b.CloseWindowContext(b.context(), id)
+}
+
+// MoveToElement calls MoveToElementContext with the default context.
+func (b *Browser) MoveToElement(we WebElement) error {
+	return b.MoveToElementContext(b.context(), we)
+}
+
+// DoubleClick calls DoubleClickContext with the default context.
+func (b *Browser) DoubleClick(we WebElement) error {
+	return b.DoubleClickContext(b.context(), we)
+}
+
+// ContextClick calls ContextClickContext with the default context.
+func (b *Browser) ContextClick(we WebElement) error {
+	return b.ContextClickContext(b.context(), we)
+}
+
+// ClickWithKeys calls ClickWithKeysContext with the default context.
+func (b *Browser) ClickWithKeys(we WebElement, keys ...w3cproto.Key) error {
+	return b.ClickWithKeysContext(b.context(), we, keys...)
+}
+
+// DragAndDrop calls DragAndDropContext with the default context.
+func (b *Browser) DragAndDrop(source WebElement, target WebElement) error {
+	return b.DragAndDropContext(b.context(), source, target)
+}
+
+// DragAndDropBy calls DragAndDropByContext with the default context.
+func (b *Browser) DragAndDropBy(source WebElement, x, y int) error {
+	return b.DragAndDropByContext(b.context(), source, x, y)
+}
+
+// ScrollBy calls ScrollByContext with the default context.
+func (b *Browser) ScrollBy(deltaX, deltaY int) error {
+	return b.ScrollByContext(b.context(), deltaX, deltaY)
+}
+
+// SavePDF calls SavePDFContext with the default context.
+func (b *Browser) SavePDF(filename string, opts *w3cproto.PrintOptions) error {
+	return b.SavePDFContext(b.context(), filename, opts)
+}
+
+// FindElement calls FindElementContext with the default context.
+func (w WebElement) FindElement(loc Locator) (we WebElement, err error) {
+	return w.FindElementContext(w.ctx, loc)
+}
+
+// FindElements calls FindElementsContext with the default context.
+func (w WebElement) FindElements(loc Locator) ([]WebElement, error) {
+	return w.FindElementsContext(w.ctx, loc)
+}
+
+// Click calls ClickContext with the default context.
+func (w WebElement) Click() error {
+	return w.ClickContext(w.ctx)
+}
+
+// Clear calls ClearContext with the default context.
+func (w WebElement) Clear() error {
+	return w.ClearContext(w.ctx)
+}
+
+// Text calls TextContext with the default context.
+func (w WebElement) Text() (string, error) {
+	return w.TextContext(w.ctx)
+}
+
+// TagName calls TagNameContext with the default context.
+func (w WebElement) TagName() (string, error) {
+	return w.TagNameContext(w.ctx)
+}
+
+// IsEnabled calls IsEnabledContext with the default context.
+func (w WebElement) IsEnabled() (bool, error) {
+	return w.IsEnabledContext(w.ctx)
+}
+
+// IsSelected calls IsSelectedContext with the default context.
+func (w WebElement) IsSelected() (bool, error) {
+	return w.IsSelectedContext(w.ctx)
+}
+
+// IsDisplayed calls IsDisplayedContext with the default context.
+func (w WebElement) IsDisplayed() (flag bool, err error) {
+	return w.IsDisplayedContext(w.ctx)
+}
+
+// Rect calls RectContext with the default context.
+func (w WebElement) Rect() (w3cproto.Rect, error) {
+	return w.RectContext(w.ctx)
+}
+
+// Attr calls AttrContext with the default context.
+func (w WebElement) Attr(name string) (string, error) {
+	return w.AttrContext(w.ctx, name)
+}
+
+// Property calls PropertyContext with the default context.
+func (w WebElement) Property(name string) (interface{}, error) {
+	return w.PropertyContext(w.ctx, name)
+}
+
+// StringProperty calls StringPropertyContext with the default context.
+func (w WebElement) StringProperty(name string) (string, error) {
+	return w.StringPropertyContext(w.ctx, name)
+}
+
+// BoolProperty calls BoolPropertyContext with the default context.
+func (w WebElement) BoolProperty(name string) (bool, error) {
+	return w.BoolPropertyContext(w.ctx, name)
+}
+
+// NumberProperty calls NumberPropertyContext with the default context.
+func (w WebElement) NumberProperty(name string) (float64, error) {
+	return w.NumberPropertyContext(w.ctx, name)
+}
+
+// CSSValue calls CSSValueContext with the default context.
+func (w WebElement) CSSValue(name string) (string, error) {
+	return w.CSSValueContext(w.ctx, name)
+}
+
+// Screenshot calls ScreenshotContext with the default context.
+func (w WebElement) Screenshot() (io.Reader, error) {
+	return w.ScreenshotContext(w.ctx)
+}
+
+// ShadowRoot calls ShadowRootContext with the default context.
+func (w WebElement) ShadowRoot() (sr ShadowRoot, err error) {
+	return w.ShadowRootContext(w.ctx)
+}
+
+// SendKeys calls SendKeysContext with the default context.
+func (w WebElement) SendKeys(keys ...w3cproto.Key) error {
+	return w.SendKeysContext(w.ctx, keys...)
+}
+
+// ScreenshotPNG calls ScreenshotPNGContext with the default context.
+func (w WebElement) ScreenshotPNG() (image.Image, error) {
+	return w.ScreenshotPNGContext(w.ctx)
+}
+
+// FindElement calls FindElementContext with the default context.
+func (s ShadowRoot) FindElement(loc Locator) (we WebElement, err error) {
+	return s.FindElementContext(s.ctx, loc)
+}
+
+// FindElements calls FindElementsContext with the default context.
+func (s ShadowRoot) FindElements(loc Locator) ([]WebElement, error) {
+	return s.FindElementsContext(s.ctx, loc)
+}
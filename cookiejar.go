@@ -0,0 +1,180 @@
+package webdriver
+
+import (
+	"context"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/mediabuyerbot/go-webdriver/pkg/w3cproto"
+)
+
+// CookieJar is the http.CookieJar sharing the cookies with the browser, e.g. to mix the browser
+// steps with the plain HTTP calls against the same site. Pull copies the cookies of the browser
+// to the jar, Push copies the cookies set by the HTTP responses to the browser.
+//
+//	jar, err := webdriver.NewCookieJar(browser, nil)
+//	client := &http.Client{Jar: jar}
+//	err = jar.Pull(ctx)
+//	resp, err := client.Get("https://example.com/api/profile")
+//	err = jar.Push(ctx)
+type CookieJar struct {
+	browser *Browser
+	jar     *cookiejar.Jar
+
+	lock    sync.Mutex
+	pending []jarCookie
+}
+
+// jarCookie is the cookie set by the HTTP response and not pushed to the browser yet.
+type jarCookie struct {
+	host   string
+	cookie *http.Cookie
+}
+
+// NewCookieJar creates the cookie jar of the browser. The options are passed to cookiejar.New.
+func NewCookieJar(b *Browser, o *cookiejar.Options) (*CookieJar, error) {
+	jar, err := cookiejar.New(o)
+	if err != nil {
+		return nil, err
+	}
+	return &CookieJar{browser: b, jar: jar}, nil
+}
+
+// SetCookies implements the http.CookieJar interface. The cookies are pushed to the browser by Push.
+func (j *CookieJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	j.jar.SetCookies(u, cookies)
+
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	for _, c := range cookies {
+		pc := *c
+		if len(pc.Path) == 0 || pc.Path[0] != '/' {
+			pc.Path = defaultCookiePath(u)
+		}
+		p := jarCookie{host: u.Hostname(), cookie: &pc}
+		if i := j.indexOf(p); i >= 0 {
+			j.pending = append(j.pending[:i:i], j.pending[i+1:]...)
+		}
+		j.pending = append(j.pending, p)
+	}
+}
+
+// Cookies implements the http.CookieJar interface. The cookies are read from the jar only,
+// the cookies of the browser are never pulled, call Pull or Sync to copy them to the jar.
+func (j *CookieJar) Cookies(u *url.URL) []*http.Cookie {
+	return j.jar.Cookies(u)
+}
+
+// Pull copies the cookies visible to the current page of the browser to the jar.
+func (j *CookieJar) Pull(ctx context.Context) error {
+	cookies, err := j.browser.CookiesContext(ctx)
+	if err != nil {
+		return err
+	}
+	for _, c := range cookies {
+		hc := c.HTTPCookie()
+		u := &url.URL{Scheme: "http", Host: strings.TrimPrefix(hc.Domain, "."), Path: hc.Path}
+		if hc.Secure {
+			u.Scheme = "https"
+		}
+		// the browser reports the domain of the host-only cookie without the leading dot
+		if !strings.HasPrefix(hc.Domain, ".") {
+			hc.Domain = ""
+		}
+		j.jar.SetCookies(u, []*http.Cookie{hc})
+	}
+	return nil
+}
+
+// Push adds the cookies set by the HTTP responses to the browser. The browser accepts the cookies
+// of the current page domain only, so the cookies of the other domains are pushed when the browser
+// visits them. The host-only cookies are pushed if the page host is the host of the response.
+// The cookies failed to push stay pending, unless the responses set them again meanwhile.
+func (j *CookieJar) Push(ctx context.Context) error {
+	rawURL, err := j.browser.CurrentURLContext(ctx)
+	if err != nil {
+		return err
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	host := u.Hostname()
+
+	// the jar is not locked during the commands, the HTTP client keeps using it
+	var push []jarCookie
+	j.lock.Lock()
+	n := 0
+	for _, p := range j.pending {
+		if p.acceptedBy(u.Scheme, host) {
+			push = append(push, p)
+			continue
+		}
+		j.pending[n] = p
+		n++
+	}
+	j.pending = j.pending[:n]
+	j.lock.Unlock()
+
+	for i, p := range push {
+		if err := j.browser.AddCookieContext(ctx, w3cproto.CookieFromHTTP(p.cookie)); err != nil {
+			j.requeue(push[i:])
+			return err
+		}
+	}
+	return nil
+}
+
+// requeue returns the cookies to the pending ones. The cookie set again by the responses is skipped.
+func (j *CookieJar) requeue(cookies []jarCookie) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	for _, p := range cookies {
+		if j.indexOf(p) < 0 {
+			j.pending = append(j.pending, p)
+		}
+	}
+}
+
+// indexOf returns the index of the pending cookie with the same host, name, domain and path, or -1.
+func (j *CookieJar) indexOf(p jarCookie) int {
+	for i, pending := range j.pending {
+		if pending.host == p.host && pending.cookie.Name == p.cookie.Name &&
+			pending.cookie.Domain == p.cookie.Domain && pending.cookie.Path == p.cookie.Path {
+			return i
+		}
+	}
+	return -1
+}
+
+// Sync pushes the cookies to the browser and pulls the cookies of the browser.
+func (j *CookieJar) Sync(ctx context.Context) error {
+	if err := j.Push(ctx); err != nil {
+		return err
+	}
+	return j.Pull(ctx)
+}
+
+// acceptedBy reports whether the page with the scheme and the host accepts the cookie.
+func (p jarCookie) acceptedBy(scheme string, host string) bool {
+	if p.cookie.Secure && scheme != "https" {
+		return false
+	}
+	if len(p.cookie.Domain) == 0 {
+		return host == p.host
+	}
+	domain := strings.ToLower(strings.TrimPrefix(p.cookie.Domain, "."))
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
+
+// defaultCookiePath returns the default path of the cookie set by the response to the URL, RFC 6265 5.1.4.
+func defaultCookiePath(u *url.URL) string {
+	i := strings.LastIndex(u.Path, "/")
+	if i <= 0 {
+		return "/"
+	}
+	return u.Path[:i]
+}
@@ -0,0 +1,123 @@
+package webdriver
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mediabuyerbot/go-webdriver/pkg/w3cproto"
+	"github.com/mediabuyerbot/go-webdriver/pkg/w3ctest"
+)
+
+func TestCookieJar(t *testing.T) {
+	ctx := context.Background()
+	srv := w3ctest.NewServer()
+	defer srv.Close()
+	browser, err := OpenRemoteBrowser(ctx, srv.URL, w3cproto.NewBrowserOptions(nil, nil))
+	assert.Nil(t, err)
+	defer browser.Close()
+	jar, err := NewCookieJar(browser, nil)
+	assert.Nil(t, err)
+
+	// pulls the cookies of the browser
+	assert.Nil(t, browser.NavigateTo("https://www.example.com/"))
+	assert.Nil(t, browser.AddCookie(w3cproto.MakeCookie().SetName("sid").SetValue("1").SetSecure(true)))
+	assert.Nil(t, jar.Pull(ctx))
+	api, _ := url.Parse("https://www.example.com/api")
+	assert.Equal(t, []*http.Cookie{{Name: "sid", Value: "1"}}, jar.Cookies(api))
+	insecure, _ := url.Parse("http://www.example.com/api")
+	assert.Empty(t, jar.Cookies(insecure))
+
+	// pushes the cookies of the responses to the browser visiting the domain
+	jar.SetCookies(api, []*http.Cookie{
+		{Name: "token", Value: "abc", HttpOnly: true},
+		{Name: "lang", Value: "en", Domain: "example.com", Path: "/"},
+	})
+	jar.SetCookies(&url.URL{Scheme: "https", Host: "shop.example.net", Path: "/"}, []*http.Cookie{{Name: "cart", Value: "42"}})
+	assert.Nil(t, jar.Push(ctx))
+	s, ok := srv.Session(browser.UID())
+	assert.True(t, ok)
+	cookies := make(map[string]w3cproto.Cookie)
+	for _, c := range s.Cookies() {
+		cookies[c.Name()] = c
+	}
+	assert.Len(t, cookies, 3)
+	assert.Equal(t, "/", cookies["token"].Path())
+	assert.True(t, cookies["token"].HttpOnly())
+	assert.Equal(t, "example.com", cookies["lang"].Domain())
+
+	assert.Nil(t, browser.NavigateTo("https://shop.example.net/"))
+	assert.Nil(t, jar.Sync(ctx))
+	cookie, err := browser.GetCookie("cart")
+	assert.Nil(t, err)
+	assert.Equal(t, "42", cookie.Value())
+	assert.Len(t, jar.pending, 0)
+}
+
+func TestCookieJar_Push(t *testing.T) {
+	ctx := context.Background()
+	srv := w3ctest.NewServer()
+	defer srv.Close()
+
+	entered, release := make(chan struct{}), make(chan struct{})
+	interceptor := func(next w3cproto.Doer) w3cproto.Doer {
+		return w3cproto.DoerFunc(func(ctx context.Context, method string, path string, p w3cproto.Params) (*w3cproto.Response, error) {
+			if method == http.MethodPost && strings.HasSuffix(path, "/cookie") {
+				switch w3cproto.Cookie(p["cookie"].(w3cproto.Params)).Name() {
+				case "slow":
+					close(entered)
+					<-release
+				case "fail":
+					return nil, w3cproto.ErrInvalidResponse
+				}
+			}
+			return next.Do(ctx, method, path, p)
+		})
+	}
+	browser, err := OpenRemoteBrowser(ctx, srv.URL, w3cproto.NewBrowserOptions(nil, nil), interceptor)
+	assert.Nil(t, err)
+	defer browser.Close()
+	jar, err := NewCookieJar(browser, nil)
+	assert.Nil(t, err)
+	assert.Nil(t, browser.NavigateTo("https://example.com/"))
+	u, _ := url.Parse("https://example.com/")
+
+	// the jar is used by the HTTP client while the cookies are pushed
+	jar.SetCookies(u, []*http.Cookie{{Name: "slow", Value: "1"}})
+	pushed := make(chan error, 1)
+	go func() { pushed <- jar.Push(ctx) }()
+	<-entered
+	set := make(chan struct{})
+	go func() {
+		jar.SetCookies(u, []*http.Cookie{{Name: "next", Value: "2"}})
+		_ = jar.Cookies(u)
+		close(set)
+	}()
+	select {
+	case <-set:
+	case <-time.After(time.Second):
+		t.Fatal("the jar is locked while the cookies are pushed")
+	}
+	close(release)
+	assert.Nil(t, <-pushed)
+	pendingNames := func() (names []string) {
+		for _, p := range jar.pending {
+			names = append(names, p.cookie.Name)
+		}
+		return names
+	}
+	assert.Equal(t, []string{"next"}, pendingNames())
+
+	// the cookies failed to push stay pending
+	jar.SetCookies(u, []*http.Cookie{{Name: "fail", Value: "3"}, {Name: "last", Value: "4"}})
+	assert.Equal(t, w3cproto.ErrInvalidResponse, jar.Push(ctx))
+	assert.Equal(t, []string{"fail", "last"}, pendingNames())
+	cookie, err := browser.GetCookie("next")
+	assert.Nil(t, err)
+	assert.Equal(t, "2", cookie.Value())
+}
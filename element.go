@@ -2,23 +2,187 @@ package webdriver
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
 
 	"github.com/mediabuyerbot/go-webdriver/pkg/w3cproto"
 )
 
-type selector struct {
-	id       string
-	strategy w3cproto.FindElementStrategy
-}
-
 type WebElement struct {
 	elem w3cproto.WebElement
+	sess *Session
 	ctx  context.Context
-	q    selector
+	loc  Locator
+}
+
+func newWebElement(ctx context.Context, sess *Session, elem w3cproto.WebElement, loc Locator) WebElement {
+	return WebElement{
+		elem: elem,
+		sess: sess,
+		ctx:  ctx,
+		loc:  loc,
+	}
+}
+
+func newWebElements(ctx context.Context, sess *Session, elems []w3cproto.WebElement, loc Locator) []WebElement {
+	webElements := make([]WebElement, len(elems))
+	for i, elem := range elems {
+		webElements[i] = newWebElement(ctx, sess, elem, loc)
+	}
+	return webElements
+}
+
+// ID returns the identifier of the element.
+func (w WebElement) ID() string {
+	return w.elem.ID()
+}
+
+// Locator returns the locator the element was found with.
+func (w WebElement) Locator() Locator {
+	return w.loc
+}
+
+// FindElementContext finds a child element.
+func (w WebElement) FindElementContext(ctx context.Context, loc Locator) (we WebElement, err error) {
+	w3cWebElem, err := w.elem.FindOne(ctx, loc.Strategy, loc.Value)
+	if err != nil {
+		return we, err
+	}
+	return newWebElement(w.ctx, w.sess, w3cWebElem, loc), nil
+}
+
+// FindElementsContext finds multiple children elements.
+func (w WebElement) FindElementsContext(ctx context.Context, loc Locator) ([]WebElement, error) {
+	w3cWebElems, err := w.elem.Find(ctx, loc.Strategy, loc.Value)
+	if err != nil {
+		return nil, err
+	}
+	return newWebElements(w.ctx, w.sess, w3cWebElems, loc), nil
+}
+
+// ClickContext clicks on the element.
+func (w WebElement) ClickContext(ctx context.Context) error {
+	return w.elem.Click(ctx)
+}
+
+// ClearContext clears the content of the editable or resettable element.
+func (w WebElement) ClearContext(ctx context.Context) error {
+	return w.elem.Clear(ctx)
+}
+
+// TextContext returns the visible text of the element.
+func (w WebElement) TextContext(ctx context.Context) (string, error) {
+	return w.elem.Text(ctx)
+}
+
+// TagNameContext returns the tag name of the element.
+func (w WebElement) TagNameContext(ctx context.Context) (string, error) {
+	return w.elem.TagName(ctx)
+}
+
+// IsEnabledContext returns true if the element is enabled.
+func (w WebElement) IsEnabledContext(ctx context.Context) (bool, error) {
+	return w.elem.IsEnabled(ctx)
+}
+
+// IsSelectedContext returns true if the checkbox, radio button or option is selected.
+func (w WebElement) IsSelectedContext(ctx context.Context) (bool, error) {
+	return w.elem.IsSelected(ctx)
+}
+
+// IsDisplayedContext returns true if the element is visible to the user.
+// The driver computes the visibility with the Selenium "isShown" atom.
+func (w WebElement) IsDisplayedContext(ctx context.Context) (bool, error) {
+	return w.elem.IsDisplayed(ctx)
+}
+
+// RectContext returns the size and the position of the element.
+func (w WebElement) RectContext(ctx context.Context) (w3cproto.Rect, error) {
+	return w.elem.Rect(ctx)
+}
+
+// AttrContext returns the named attribute of the element.
+func (w WebElement) AttrContext(ctx context.Context, name string) (string, error) {
+	return w.elem.GetAttribute(ctx, name)
+}
+
+// PropertyContext returns the named property of the element decoded from JSON: the string,
+// the bool, the float64 number, nil, the []interface{} or the map[string]interface{} value.
+func (w WebElement) PropertyContext(ctx context.Context, name string) (value interface{}, err error) {
+	data, err := w.elem.GetPropertyValue(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// StringPropertyContext returns the named string property of the element, e.g. "value".
+func (w WebElement) StringPropertyContext(ctx context.Context, name string) (string, error) {
+	return w.elem.GetProperty(ctx, name)
+}
+
+// BoolPropertyContext returns the named boolean property of the element, e.g. "checked".
+func (w WebElement) BoolPropertyContext(ctx context.Context, name string) (flag bool, err error) {
+	err = w.decodeProperty(ctx, name, &flag)
+	return flag, err
+}
+
+// NumberPropertyContext returns the named number property of the element, e.g. "selectedIndex".
+func (w WebElement) NumberPropertyContext(ctx context.Context, name string) (n float64, err error) {
+	err = w.decodeProperty(ctx, name, &n)
+	return n, err
+}
+
+func (w WebElement) decodeProperty(ctx context.Context, name string, v interface{}) error {
+	data, err := w.elem.GetPropertyValue(ctx, name)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("webdriver: property %s: %w", name, err)
+	}
+	return nil
+}
+
+// CSSValueContext returns the computed value of the CSS property of the element.
+func (w WebElement) CSSValueContext(ctx context.Context, name string) (string, error) {
+	return w.elem.GetCSSValue(ctx, name)
+}
+
+// ScreenshotContext takes a screenshot of the visible region of the element.
+func (w WebElement) ScreenshotContext(ctx context.Context) (io.Reader, error) {
+	return w.sess.ScreenCapture().TakeElement(ctx, w.elem.ID())
+}
+
+// ScreenshotPNGContext takes a screenshot of the visible region of the element.
+func (w WebElement) ScreenshotPNGContext(ctx context.Context) (image.Image, error) {
+	reader, err := w.ScreenshotContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return png.Decode(reader)
 }
 
-func (w WebElement) Attr(name string) (string, error) {
-	return w.elem.GetAttribute(w.ctx, name)
+// ShadowRootContext returns the shadow root of the element.
+func (w WebElement) ShadowRootContext(ctx context.Context) (sr ShadowRoot, err error) {
+	root, err := w.elem.ShadowRoot(ctx)
+	if err != nil {
+		return sr, err
+	}
+	return ShadowRoot{
+		root: root,
+		sess: w.sess,
+		ctx:  w.ctx,
+	}, nil
 }
 
 func (w WebElement) PressNullKey() error {
@@ -237,6 +401,6 @@ func (w WebElement) PressMetaKey() error {
 	return w.elem.SendKeys(w.ctx, w3cproto.MetaKey)
 }
 
-func (w WebElement) SendKeys(keys ...w3cproto.Key) error {
-	return w.elem.SendKeys(w.ctx, keys...)
+func (w WebElement) SendKeysContext(ctx context.Context, keys ...w3cproto.Key) error {
+	return w.elem.SendKeys(ctx, keys...)
 }
@@ -0,0 +1,126 @@
+package webdriver
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// chromeDriverAddrEnv is the address of the running chromedriver, e.g. http://127.0.0.1:9515.
+// The tests running in the real browser are skipped if the address is not set.
+const chromeDriverAddrEnv = "WEBGO_CHROMEDRIVER_ADDR"
+
+const transparentGIF = "data:image/gif;base64,R0lGODlhAQABAIAAAP///wAAACH5BAEAAAAALAAAAAABAAEAAAICRAEAOw=="
+
+func TestWebElement_IsDisplayedInChrome(t *testing.T) {
+	addr := os.Getenv(chromeDriverAddrEnv)
+	if len(addr) == 0 {
+		t.Skip(chromeDriverAddrEnv + " is not set")
+	}
+	ctx := context.Background()
+	browser, err := OpenRemoteBrowser(ctx, addr, ChromeOptions().AddArgument("--headless").Build())
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer browser.Close()
+
+	testCases := []struct {
+		name  string
+		page  string
+		shown bool
+	}{
+		{
+			name:  "visible",
+			page:  `<p id="target">text</p>`,
+			shown: true,
+		},
+		{
+			name:  "display none ancestor",
+			page:  `<div style="display:none"><div><p id="target">text</p></div></div>`,
+			shown: false,
+		},
+		{
+			name:  "visibility hidden ancestor",
+			page:  `<div style="visibility:hidden"><p id="target">text</p></div>`,
+			shown: false,
+		},
+		{
+			name:  "visible child of the visibility hidden ancestor",
+			page:  `<div style="visibility:hidden"><p id="target" style="visibility:visible">text</p></div>`,
+			shown: true,
+		},
+		{
+			name:  "transparent ancestor",
+			page:  `<div style="opacity:0"><p id="target">text</p></div>`,
+			shown: false,
+		},
+		{
+			name:  "zero size with the visible child",
+			page:  `<div id="target" style="width:0;height:0"><span style="display:inline-block;width:10px;height:10px">text</span></div>`,
+			shown: true,
+		},
+		{
+			name:  "zero size with the clipped child",
+			page:  `<div id="target" style="width:0;height:0;overflow:hidden"><span>text</span></div>`,
+			shown: false,
+		},
+		{
+			name:  "clipped by the overflow of the ancestor",
+			page:  `<div style="width:50px;height:50px;overflow:hidden"><p id="target" style="position:relative;left:100px">text</p></div>`,
+			shown: false,
+		},
+		{
+			name:  "scrollable overflow of the ancestor",
+			page:  `<div style="width:50px;height:50px;overflow:auto"><p id="target" style="position:relative;left:100px">text</p></div>`,
+			shown: true,
+		},
+		{
+			name:  "hidden input",
+			page:  `<input id="target" type="hidden" value="1">`,
+			shown: false,
+		},
+		{
+			name:  "option of the visible select",
+			page:  `<select><option>first</option><option id="target">second</option></select>`,
+			shown: true,
+		},
+		{
+			name:  "option of the hidden select",
+			page:  `<select style="display:none"><option id="target">first</option></select>`,
+			shown: false,
+		},
+		{
+			name:  "area of the visible image",
+			page:  `<img src="` + transparentGIF + `" width="10" height="10" usemap="#map"><map name="map"><area id="target" shape="rect" coords="0,0,5,5" href="#"></map>`,
+			shown: true,
+		},
+		{
+			name:  "area of the hidden image",
+			page:  `<img src="` + transparentGIF + `" width="10" height="10" usemap="#map" style="display:none"><map name="map"><area id="target" shape="rect" coords="0,0,5,5" href="#"></map>`,
+			shown: false,
+		},
+		{
+			name:  "area of the map without the image",
+			page:  `<map name="map"><area id="target" shape="rect" coords="0,0,5,5" href="#"></map>`,
+			shown: false,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := browser.NavigateToContext(ctx, "data:text/html;charset=utf-8,"+url.PathEscape(tc.page))
+			if !assert.Nil(t, err) {
+				return
+			}
+			elem, err := browser.FindElementContext(ctx, By.ID("target"))
+			if !assert.Nil(t, err) {
+				return
+			}
+			shown, err := elem.IsDisplayedContext(ctx)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.shown, shown)
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package webdriver
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mediabuyerbot/go-webdriver/pkg/w3cproto"
+)
+
+func findTestElement(t *testing.T, browser *Browser, cli *w3cproto.MockDoer) WebElement {
+	cli.EXPECT().Do(browser.ctx, http.MethodPost, "/session/123/element", w3cproto.Params{
+		"using": w3cproto.ByCSSSelector,
+		"value": "#elem",
+	}).Times(1).Return(&w3cproto.Response{
+		Value: []byte(`{"element-6066-11e4-a52e-4f735466cecf":"1"}`),
+	}, nil)
+	we, err := browser.FindElement(By.CSS("#elem"))
+	assert.Nil(t, err)
+	return we
+}
+
+func TestWebElement_Commands(t *testing.T) {
+	browser, cli, done := newTestBrowser(t)
+	defer done()
+
+	ctx := context.Background()
+	we := findTestElement(t, browser, cli)
+	assert.Equal(t, "1", we.ID())
+	assert.Equal(t, By.CSS("#elem"), we.Locator())
+
+	cli.EXPECT().Do(ctx, http.MethodPost, "/session/123/element/1/click", nil).Times(1).Return(
+		&w3cproto.Response{Value: []byte(`null`)}, nil)
+	assert.Nil(t, we.Click())
+
+	cli.EXPECT().Do(ctx, http.MethodPost, "/session/123/element/1/clear", nil).Times(1).Return(
+		nil, w3cproto.ErrInvalidResponse)
+	assert.Equal(t, w3cproto.ErrInvalidResponse, we.Clear())
+
+	cli.EXPECT().Do(ctx, http.MethodGet, "/session/123/element/1/text", nil).Times(1).Return(
+		&w3cproto.Response{Value: []byte(`"hello"`)}, nil)
+	text, err := we.Text()
+	assert.Nil(t, err)
+	assert.Equal(t, "hello", text)
+
+	cli.EXPECT().Do(ctx, http.MethodGet, "/session/123/element/1/name", nil).Times(1).Return(
+		&w3cproto.Response{Value: []byte(`"input"`)}, nil)
+	tag, err := we.TagName()
+	assert.Nil(t, err)
+	assert.Equal(t, "input", tag)
+
+	cli.EXPECT().Do(ctx, http.MethodGet, "/session/123/element/1/enabled", nil).Times(1).Return(
+		&w3cproto.Response{Value: []byte(`true`)}, nil)
+	enabled, err := we.IsEnabled()
+	assert.Nil(t, err)
+	assert.True(t, enabled)
+
+	cli.EXPECT().Do(ctx, http.MethodGet, "/session/123/element/1/selected", nil).Times(1).Return(
+		&w3cproto.Response{Value: []byte(`false`)}, nil)
+	selected, err := we.IsSelected()
+	assert.Nil(t, err)
+	assert.False(t, selected)
+
+	cli.EXPECT().Do(ctx, http.MethodGet, "/session/123/element/1/rect", nil).Times(1).Return(
+		&w3cproto.Response{Value: []byte(`{"x":1,"y":2,"width":3,"height":4}`)}, nil)
+	rect, err := we.Rect()
+	assert.Nil(t, err)
+	assert.Equal(t, w3cproto.Rect{X: 1, Y: 2, Width: 3, Height: 4}, rect)
+
+	cli.EXPECT().Do(ctx, http.MethodGet, "/session/123/element/1/property/value", nil).Times(1).Return(
+		&w3cproto.Response{Value: []byte(`"text"`)}, nil)
+	prop, err := we.Property("value")
+	assert.Nil(t, err)
+	assert.Equal(t, "text", prop)
+
+	// the boolean and the number properties
+	cli.EXPECT().Do(ctx, http.MethodGet, "/session/123/element/1/property/checked", nil).Times(2).Return(
+		&w3cproto.Response{Value: []byte(`true`)}, nil)
+	prop, err = we.Property("checked")
+	assert.Nil(t, err)
+	assert.Equal(t, true, prop)
+	checked, err := we.BoolProperty("checked")
+	assert.Nil(t, err)
+	assert.True(t, checked)
+	cli.EXPECT().Do(ctx, http.MethodGet, "/session/123/element/1/property/selectedIndex", nil).Times(2).Return(
+		&w3cproto.Response{Value: []byte(`2`)}, nil)
+	index, err := we.NumberProperty("selectedIndex")
+	assert.Nil(t, err)
+	assert.Equal(t, float64(2), index)
+	_, err = we.StringProperty("selectedIndex")
+	assert.Error(t, err)
+
+	cli.EXPECT().Do(ctx, http.MethodGet, "/session/123/element/1/css/color", nil).Times(1).Return(
+		&w3cproto.Response{Value: []byte(`"rgba(0, 0, 0, 1)"`)}, nil)
+	color, err := we.CSSValue("color")
+	assert.Nil(t, err)
+	assert.Equal(t, "rgba(0, 0, 0, 1)", color)
+
+	cli.EXPECT().Do(ctx, http.MethodGet, "/session/123/element/1/screenshot", nil).Times(1).Return(
+		&w3cproto.Response{Value: []byte(`"aGVsbG8="`)}, nil)
+	reader, err := we.Screenshot()
+	assert.Nil(t, err)
+	data, err := ioutil.ReadAll(reader)
+	assert.Nil(t, err)
+	assert.Equal(t, "hello", string(data))
+}
+
+func TestWebElement_IsDisplayed(t *testing.T) {
+	browser, cli, done := newTestBrowser(t)
+	defer done()
+
+	ctx := context.Background()
+	we := findTestElement(t, browser, cli)
+
+	cli.EXPECT().Do(ctx, http.MethodGet, "/session/123/element/1/displayed", nil).Times(1).Return(
+		&w3cproto.Response{Value: []byte(`true`)}, nil)
+	displayed, err := we.IsDisplayed()
+	assert.Nil(t, err)
+	assert.True(t, displayed)
+
+	cli.EXPECT().Do(ctx, http.MethodGet, "/session/123/element/1/displayed", nil).Times(1).Return(
+		nil, w3cproto.ErrStaleElementReference)
+	_, err = we.IsDisplayed()
+	assert.Equal(t, w3cproto.ErrStaleElementReference, err)
+}
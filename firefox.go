@@ -1 +1,207 @@
 package webdriver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"runtime"
+	"time"
+
+	bin "github.com/mediabuyerbot/go-webdriver/third_party/drivers"
+
+	"github.com/mediabuyerbot/go-webdriver/pkg/discovery"
+	"github.com/mediabuyerbot/go-webdriver/pkg/download"
+	"github.com/mediabuyerbot/go-webdriver/pkg/geckodriver"
+)
+
+const (
+	EnvGeckoDriverPath = "GECKODRIVER_PATH"
+)
+
+var geckoDriver = discovery.Driver{
+	Name:     discovery.GeckoDriver.Name,
+	Env:      EnvGeckoDriverPath,
+	Fallback: bin.GeckoDriver64,
+}
+
+// FirefoxLauncherOptions configures the geckodriver started by LaunchFirefox.
+type FirefoxLauncherOptions struct {
+	// Context owns the lifetime of the driver and the browser. The driver is shut down
+	// when the context is done. context.Background() is used if nil.
+	Context context.Context
+
+	// DriverPath is the path to the geckodriver binary. If empty the driver is looked up
+	// in the GECKODRIVER_PATH environment variable, the PATH directories, the per-user
+	// cache directory and the bundled binaries. If the driver is not found, the release
+	// compatible with the browser is downloaded into the cache directory.
+	DriverPath string
+
+	// DisableDownload disables the download of the driver.
+	DisableDownload bool
+
+	// Downloader downloads the driver. A manager with the default settings is used if nil.
+	Downloader *download.Manager
+
+	// DriverSource resolves the driver releases, e.g. from a mirror.
+	// download.GeckoDriverSource with the default URLs is used if nil.
+	DriverSource download.Source
+
+	// SkipVersionCheck disables the check that the driver supports the browser version.
+	SkipVersionCheck bool
+
+	// Port is the port the driver listens on. A free port is picked if zero.
+	Port int
+
+	LogLevel geckodriver.LogLevel
+
+	// Output receives the stderr of the driver. The output is discarded if nil.
+	Output io.Writer
+
+	// Env is the list of the environment variables in the form "key=value"
+	// added to the environment of the driver.
+	Env []string
+
+	// StartTimeout is the time the driver has to become ready.
+	StartTimeout time.Duration
+}
+
+// DefaultFirefoxLauncherOptions returns the options used by Firefox.
+func DefaultFirefoxLauncherOptions() *FirefoxLauncherOptions {
+	return &FirefoxLauncherOptions{
+		Context:  context.Background(),
+		LogLevel: geckodriver.Error,
+		Output:   log.Writer(),
+	}
+}
+
+// Firefox starts the geckodriver with the default launcher options and creates a new session.
+func Firefox(opts *FirefoxOptionsBuilder) (*Browser, error) {
+	return LaunchFirefox(DefaultFirefoxLauncherOptions(), opts)
+}
+
+// LaunchFirefox starts the geckodriver configured by the launcher options and creates a new session.
+func LaunchFirefox(lo *FirefoxLauncherOptions, opts *FirefoxOptionsBuilder) (*Browser, error) {
+	if lo == nil {
+		lo = DefaultFirefoxLauncherOptions()
+	}
+	if opts == nil {
+		opts = FirefoxOptions()
+	}
+	ctx := lo.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	port := lo.Port
+	if port == 0 {
+		p, err := freePort()
+		if err != nil {
+			return nil, err
+		}
+		port = p
+	}
+	driverPath, err := geckoDriver.Find(lo.DriverPath)
+	if errors.Is(err, discovery.ErrDriverNotFound) && len(lo.DriverPath) == 0 && !lo.DisableDownload {
+		driverPath, err = downloadGeckoDriver(ctx, lo, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !lo.SkipVersionCheck {
+		if err := checkFirefox(ctx, driverPath, opts); err != nil {
+			return nil, err
+		}
+	}
+
+	done := make(chan error)
+	driverOpts := []geckodriver.Option{
+		geckodriver.WithPort(port),
+		geckodriver.WithStartTimeout(lo.StartTimeout),
+		geckodriver.WithRunHook(func(pid int) {
+			done <- nil
+		}),
+	}
+	if lo.Output != nil {
+		driverOpts = append(driverOpts, geckodriver.WithStderr(lo.Output))
+	}
+	if len(lo.LogLevel) > 0 {
+		driverOpts = append(driverOpts, geckodriver.WithLogLevel(lo.LogLevel))
+	}
+	if len(lo.Env) > 0 {
+		driverOpts = append(driverOpts, geckodriver.WithEnv(lo.Env...))
+	}
+	if j := driverJanitor(); j != nil {
+		driverOpts = append(driverOpts, geckodriver.WithJanitor(j))
+	}
+	driver, err := geckodriver.New(driverPath, driverOpts...)
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		if err := driver.Run(ctx); err != nil {
+			select {
+			case done <- err:
+				log.Printf("[ERROR] geckodriver failed to start\n%v\n", err)
+			default:
+				log.Printf("[ERROR] geckodriver \n%v\n", err)
+			}
+		}
+		close(done)
+	}()
+	err = <-done
+	if err != nil {
+		return nil, err
+	}
+
+	addr := fmt.Sprintf("http://localhost:%d", port)
+	sess, err := NewSession(ctx, addr, opts.Build())
+	if err != nil {
+		_ = driver.Stop(ctx)
+		return nil, err
+	}
+	return newBrowser(ctx, sess, driver), nil
+}
+
+// checkFirefox checks that the driver supports the browser binary of the options.
+// The check is skipped if the browser binary is not found.
+func checkFirefox(ctx context.Context, driverPath string, opts *FirefoxOptionsBuilder) error {
+	if runtime.GOOS == "windows" {
+		// firefox.exe does not print the version
+		return nil
+	}
+	browserPath, ok := firefoxBinary(opts)
+	if !ok {
+		return nil
+	}
+	return discovery.CheckFirefox(ctx, driverPath, browserPath)
+}
+
+// downloadGeckoDriver downloads the driver compatible with the browser binary of the options.
+func downloadGeckoDriver(ctx context.Context, lo *FirefoxLauncherOptions, opts *FirefoxOptionsBuilder) (string, error) {
+	browserPath, ok := firefoxBinary(opts)
+	if !ok {
+		return "", fmt.Errorf("%w: geckodriver (Firefox is not found to download the compatible driver)",
+			discovery.ErrDriverNotFound)
+	}
+	version, err := discovery.BinaryVersion(ctx, browserPath)
+	if err != nil {
+		return "", err
+	}
+	manager := lo.Downloader
+	if manager == nil {
+		manager = &download.Manager{}
+	}
+	var src download.Source = download.GeckoDriverSource{}
+	if lo.DriverSource != nil {
+		src = lo.DriverSource
+	}
+	return manager.Install(ctx, src, version)
+}
+
+func firefoxBinary(opts *FirefoxOptionsBuilder) (string, bool) {
+	if path := opts.firefoxCapabilities.GetString(FirefoxCapabilityBinaryName); len(path) > 0 {
+		return path, true
+	}
+	return discovery.FirefoxBinary()
+}
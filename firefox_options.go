@@ -1,8 +1,189 @@
 package webdriver
 
+import (
+	"github.com/mediabuyerbot/go-webdriver/pkg/w3cproto"
+)
+
+const (
+	// Absolute path to the custom Firefox binary to use. On macOS you may either give
+	// the path to the application bundle, i.e. /Applications/Firefox.app, or the absolute
+	// path to the executable binary inside this bundle.
+	FirefoxCapabilityBinaryName = "binary"
+
+	// Command line arguments to pass to the Firefox binary. These must include
+	// the leading dash (-) where required, e.g. ["-headless"].
+	FirefoxCapabilityArgsName = "args"
+
+	// Base64-encoded ZIP of a profile directory to use for the Firefox instance.
+	FirefoxCapabilityProfileName = "profile"
+
+	// To increase the logging verbosity of geckodriver and Firefox, you may pass a log
+	// object that may look like {"log": {"level": "trace"}} to include all trace-level
+	// logs and above.
+	FirefoxCapabilityLogName = "log"
+
+	// Map of preference name to preference value, which can be a string, a boolean or an integer.
+	FirefoxCapabilityPreferencesName = "prefs"
+
+	// Map of environment variable name to environment variable value, both of which must be strings,
+	// that will be added to the environment of the Firefox process.
+	FirefoxCapabilityEnvName = "env"
+
+	FirefoxOptionsKey = "moz:firefoxOptions"
+)
+
+const firefoxLogLevelName = "level"
+
+// List of the Firefox log levels.
+const (
+	FirefoxLogTrace  = "trace"
+	FirefoxLogDebug  = "debug"
+	FirefoxLogConfig = "config"
+	FirefoxLogInfo   = "info"
+	FirefoxLogWarn   = "warn"
+	FirefoxLogError  = "error"
+	FirefoxLogFatal  = "fatal"
+)
+
 type FirefoxOptionsBuilder struct {
+	//W3C Capabilities
+	capabilities w3cproto.Capabilities
+
+	// Firefox options
+	firefoxCapabilities w3cproto.Capabilities
+	args                []string
+	pref                w3cproto.Capabilities
+	env                 w3cproto.Capabilities
+
+	firstMatch []w3cproto.Capabilities
 }
 
 func FirefoxOptions() *FirefoxOptionsBuilder {
-	return &FirefoxOptionsBuilder{}
+	return &FirefoxOptionsBuilder{
+		capabilities: w3cproto.MakeCapabilities(),
+
+		firefoxCapabilities: w3cproto.MakeCapabilities(),
+		args:                make([]string, 0),
+		pref:                w3cproto.MakeCapabilities(),
+		env:                 w3cproto.MakeCapabilities(),
+
+		firstMatch: make([]w3cproto.Capabilities, 0),
+	}
+}
+
+func (b *FirefoxOptionsBuilder) SetBrowserName(name string) *FirefoxOptionsBuilder {
+	_ = w3cproto.SetBrowserName(b.capabilities, name)
+	return b
+}
+
+func (b *FirefoxOptionsBuilder) SetBrowserVersion(version string) *FirefoxOptionsBuilder {
+	_ = w3cproto.SetBrowserVersion(b.capabilities, version)
+	return b
+}
+
+func (b *FirefoxOptionsBuilder) SetPlatformName(platform string) *FirefoxOptionsBuilder {
+	_ = w3cproto.SetPlatformName(b.capabilities, w3cproto.Platform(platform))
+	return b
+}
+
+func (b *FirefoxOptionsBuilder) SetAcceptInsecureCerts(flag bool) *FirefoxOptionsBuilder {
+	_ = w3cproto.SetAcceptInsecureCerts(b.capabilities, flag)
+	return b
+}
+
+func (b *FirefoxOptionsBuilder) SetPageLoadStrategy(strategy string) *FirefoxOptionsBuilder {
+	_ = w3cproto.SetPageLoadStrategy(b.capabilities, strategy)
+	return b
+}
+
+func (b *FirefoxOptionsBuilder) SetWindowRect(flag bool) *FirefoxOptionsBuilder {
+	_ = w3cproto.SetWindowRect(b.capabilities, flag)
+	return b
+}
+
+func (b *FirefoxOptionsBuilder) SetProxy(proxy *w3cproto.Proxy) *FirefoxOptionsBuilder {
+	_ = w3cproto.SetProxy(b.capabilities, proxy)
+	return b
+}
+
+func (b *FirefoxOptionsBuilder) SetUnhandledPromptBehavior(prompt string) *FirefoxOptionsBuilder {
+	_ = w3cproto.SetUnhandledPromptBehavior(b.capabilities, prompt)
+	return b
+}
+
+func (b *FirefoxOptionsBuilder) SetTimeout(timeout w3cproto.Timeout) *FirefoxOptionsBuilder {
+	_ = w3cproto.SetTimeout(b.capabilities, timeout)
+	return b
+}
+
+func (b *FirefoxOptionsBuilder) SetBinary(binPath string) *FirefoxOptionsBuilder {
+	b.firefoxCapabilities.Set(FirefoxCapabilityBinaryName, binPath)
+	return b
+}
+
+func (b *FirefoxOptionsBuilder) SetLogLevel(level string) *FirefoxOptionsBuilder {
+	b.firefoxCapabilities.Set(FirefoxCapabilityLogName, w3cproto.Capabilities{
+		firefoxLogLevelName: level,
+	})
+	return b
+}
+
+// SetProfile sets the base64 encoded zipped profile directory.
+func (b *FirefoxOptionsBuilder) SetProfile(base64 string) error {
+	if ok := IsBase64(base64); !ok {
+		return ErrBase64Format
+	}
+	b.firefoxCapabilities.Set(FirefoxCapabilityProfileName, base64)
+	return nil
+}
+
+// SetFirefoxProfile encodes the profile and sets it as the profile of the Firefox instance.
+func (b *FirefoxOptionsBuilder) SetFirefoxProfile(profile *FirefoxProfile) error {
+	b64, err := profile.Encode()
+	if err != nil {
+		return err
+	}
+	return b.SetProfile(b64)
+}
+
+func (b *FirefoxOptionsBuilder) SetPref(key string, value interface{}) *FirefoxOptionsBuilder {
+	b.pref.Set(key, value)
+	return b
+}
+
+func (b *FirefoxOptionsBuilder) SetEnv(key string, value string) *FirefoxOptionsBuilder {
+	if len(key) > 0 {
+		b.env.Set(key, value)
+	}
+	return b
+}
+
+func (b *FirefoxOptionsBuilder) AddArgument(arg ...string) *FirefoxOptionsBuilder {
+	b.args = append(b.args, arg...)
+	return b
+}
+
+func (b *FirefoxOptionsBuilder) AddFirstMatch(key string, value interface{}) *FirefoxOptionsBuilder {
+	if len(key) > 0 {
+		cap := w3cproto.MakeCapabilities()
+		cap.Set(key, value)
+		b.firstMatch = append(b.firstMatch, cap)
+	}
+	return b
+}
+
+func (b *FirefoxOptionsBuilder) Build() w3cproto.BrowserOptions {
+	if len(b.args) > 0 {
+		b.firefoxCapabilities[FirefoxCapabilityArgsName] = b.args
+	}
+	if len(b.pref) > 0 {
+		b.firefoxCapabilities[FirefoxCapabilityPreferencesName] = b.pref
+	}
+	if len(b.env) > 0 {
+		b.firefoxCapabilities[FirefoxCapabilityEnvName] = b.env
+	}
+
+	b.capabilities.Set(FirefoxOptionsKey, b.firefoxCapabilities)
+
+	return w3cproto.NewBrowserOptions(b.capabilities, b.firstMatch)
 }
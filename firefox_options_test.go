@@ -0,0 +1,63 @@
+package webdriver
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mediabuyerbot/go-webdriver/pkg/w3cproto"
+)
+
+func TestFirefoxOptions(t *testing.T) {
+	builder := FirefoxOptions()
+
+	profile := base64.StdEncoding.EncodeToString([]byte(`profile`))
+
+	assert.NotNil(t, builder.SetBrowserName("firefox"))
+	assert.NotNil(t, builder.SetBrowserVersion("89"))
+	assert.NotNil(t, builder.SetPlatformName("linux"))
+	assert.NotNil(t, builder.SetAcceptInsecureCerts(true))
+	assert.NotNil(t, builder.SetPageLoadStrategy("eager"))
+	assert.NotNil(t, builder.SetWindowRect(true))
+	assert.NotNil(t, builder.SetProxy(&w3cproto.Proxy{SocksPort: 8090}))
+	assert.NotNil(t, builder.SetUnhandledPromptBehavior("dismiss"))
+	assert.NotNil(t, builder.SetTimeout(w3cproto.Timeout{Script: 9000}))
+	assert.NotNil(t, builder.SetBinary("/path/to/firefox"))
+	assert.NotNil(t, builder.SetLogLevel(FirefoxLogTrace))
+	assert.NotNil(t, builder.SetPref("dom.ipc.processCount", 8))
+	assert.NotNil(t, builder.SetEnv("MOZ_HEADLESS", "1"))
+	assert.NotNil(t, builder.SetEnv("", "ignored"))
+	assert.NotNil(t, builder.AddArgument("-headless", "-safe-mode"))
+	assert.NotNil(t, builder.AddFirstMatch("browserName", "firefox"))
+	assert.Nil(t, builder.SetProfile(profile))
+
+	browserOptions := builder.Build()
+	assert.NotNil(t, browserOptions)
+
+	// always match
+	alwaysMatch := browserOptions.AlwaysMatch()
+	assert.Equal(t, "firefox", alwaysMatch.GetString(w3cproto.CapabilityBrowserName))
+	assert.Equal(t, "89", alwaysMatch.GetString(w3cproto.CapabilityBrowserVersion))
+	assert.Equal(t, "linux", alwaysMatch.GetString(w3cproto.CapabilityPlatformName))
+	assert.True(t, alwaysMatch.GetBool(w3cproto.CapabilityAcceptInsecureCerts))
+	assert.Equal(t, "eager", alwaysMatch.GetString(w3cproto.CapabilityPageLoadStrategy))
+	assert.True(t, alwaysMatch.GetBool(w3cproto.CapabilitySetWindowRect))
+	assert.Equal(t, 8090, alwaysMatch.Section("proxy").GetInt("socksProxyPort"))
+	assert.Equal(t, "dismiss", alwaysMatch.GetString(w3cproto.CapabilityUnhandledPromptBehavior))
+	assert.Equal(t, uint(9000), alwaysMatch.Section(w3cproto.CapabilityTimeouts).GetUint("script"))
+
+	firefoxOpts := alwaysMatch.Section(FirefoxOptionsKey)
+	assert.Equal(t, "/path/to/firefox", firefoxOpts.GetString(FirefoxCapabilityBinaryName))
+	assert.Equal(t, profile, firefoxOpts.GetString(FirefoxCapabilityProfileName))
+	assert.Equal(t, FirefoxLogTrace, firefoxOpts.Section(FirefoxCapabilityLogName).GetString(firefoxLogLevelName))
+	assert.Equal(t, 8, firefoxOpts.Section(FirefoxCapabilityPreferencesName).GetInt("dom.ipc.processCount"))
+	assert.Equal(t, "1", firefoxOpts.Section(FirefoxCapabilityEnvName).GetString("MOZ_HEADLESS"))
+	assert.Len(t, firefoxOpts.Section(FirefoxCapabilityEnvName), 1)
+	assert.Len(t, firefoxOpts.GetStringSlice(FirefoxCapabilityArgsName), 2)
+	assert.Len(t, browserOptions.FirstMatch(), 1)
+
+	// bad profile
+	err := builder.SetProfile("--")
+	assert.Error(t, err)
+}
@@ -0,0 +1,305 @@
+package webdriver
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	firefoxUserPrefsFile     = "user.js"
+	firefoxExtensionsDir     = "extensions"
+	firefoxExtensionManifest = "manifest.json"
+	xpiExt                   = ".xpi"
+)
+
+var (
+	ErrUnsupportedCertificateFile = errors.New("webdriver: unsupported firefox certificate file")
+	ErrExtensionID                = errors.New("webdriver: firefox extension id not found")
+)
+
+// firefoxCertificateFiles is a list of the files in which Firefox keeps the certificates
+// and the certificate exceptions of the profile.
+var firefoxCertificateFiles = map[string]struct{}{
+	"cert9.db":          {},
+	"cert8.db":          {},
+	"key4.db":           {},
+	"key3.db":           {},
+	"pkcs11.txt":        {},
+	"cert_override.txt": {},
+}
+
+// firefoxProfileSkipFiles is a list of the files that are not copied from the template profile.
+var firefoxProfileSkipFiles = map[string]struct{}{
+	"lock":        {},
+	".parentlock": {},
+	"parent.lock": {},
+}
+
+// FirefoxProfile builds the zipped profile directory for the moz:firefoxOptions capability.
+//
+//	profile := webdriver.NewFirefoxProfile("")
+//	profile.SetPref("browser.startup.homepage", "https://example.com")
+//	if err := profile.AddExtension("/path/to/extension.xpi"); err != nil {
+//		return err
+//	}
+//	b64, err := profile.Encode()
+type FirefoxProfile struct {
+	templateDir  string
+	prefs        map[string]interface{}
+	extensions   []string
+	certificates []string
+}
+
+// NewFirefoxProfile returns a new instance of FirefoxProfile. If the templateDir is not empty
+// the existing profile directory is cloned into the new profile.
+func NewFirefoxProfile(templateDir string) *FirefoxProfile {
+	return &FirefoxProfile{
+		templateDir:  templateDir,
+		prefs:        make(map[string]interface{}),
+		extensions:   make([]string, 0),
+		certificates: make([]string, 0),
+	}
+}
+
+// SetPref sets the preference written to the user.js file. The value can be a string, a boolean or a number.
+func (p *FirefoxProfile) SetPref(key string, value interface{}) *FirefoxProfile {
+	if len(key) > 0 {
+		p.prefs[key] = value
+	}
+	return p
+}
+
+// AddExtension adds the extension .xpi file installed into the profile.
+func (p *FirefoxProfile) AddExtension(xpiPath string) error {
+	if !strings.EqualFold(filepath.Ext(xpiPath), xpiExt) {
+		return fmt.Errorf("webdriver: %s is not a .xpi file", xpiPath)
+	}
+	if _, err := os.Stat(xpiPath); err != nil {
+		return err
+	}
+	p.extensions = append(p.extensions, xpiPath)
+	return nil
+}
+
+// AddCertificate adds the certificate database (cert9.db, key4.db, pkcs11.txt)
+// or the certificate exceptions file (cert_override.txt) copied into the profile.
+func (p *FirefoxProfile) AddCertificate(certPath string) error {
+	if _, ok := firefoxCertificateFiles[filepath.Base(certPath)]; !ok {
+		return ErrUnsupportedCertificateFile
+	}
+	if _, err := os.Stat(certPath); err != nil {
+		return err
+	}
+	p.certificates = append(p.certificates, certPath)
+	return nil
+}
+
+// Encode returns the base64 encoded zipped profile.
+func (p *FirefoxProfile) Encode() (string, error) {
+	buf := new(bytes.Buffer)
+	if err := p.Zip(buf); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// Zip writes the zipped profile to the writer. The added certificates and extensions
+// override the files of the template profile with the same names.
+func (p *FirefoxProfile) Zip(w io.Writer) error {
+	names, files, err := p.addedFiles()
+	if err != nil {
+		return err
+	}
+
+	zw := zip.NewWriter(w)
+
+	var userPrefs []byte
+	if len(p.templateDir) > 0 {
+		data, err := p.cloneTemplate(zw, files)
+		if err != nil {
+			return err
+		}
+		userPrefs = data
+	}
+
+	for _, name := range names {
+		if err := zipFile(zw, name, files[name]); err != nil {
+			return err
+		}
+	}
+
+	userPrefs = append(userPrefs, p.userPrefs()...)
+	if len(userPrefs) > 0 {
+		fw, err := zw.Create(firefoxUserPrefsFile)
+		if err != nil {
+			return err
+		}
+		if _, err := fw.Write(userPrefs); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// addedFiles returns the archive names of the added certificates and extensions in the order
+// they are added and the paths of the files by the names. The file added later overrides
+// the earlier one with the same name, e.g. the same extension added twice.
+func (p *FirefoxProfile) addedFiles() (names []string, files map[string]string, err error) {
+	files = make(map[string]string)
+	add := func(name string, path string) {
+		if _, ok := files[name]; !ok {
+			names = append(names, name)
+		}
+		files[name] = path
+	}
+	for _, certPath := range p.certificates {
+		add(filepath.Base(certPath), certPath)
+	}
+	for _, xpiPath := range p.extensions {
+		id, err := firefoxExtensionID(xpiPath)
+		if err != nil {
+			return nil, nil, err
+		}
+		add(firefoxExtensionsDir+"/"+id+xpiExt, xpiPath)
+	}
+	return names, files, nil
+}
+
+// cloneTemplate copies the template profile into the archive and returns the content
+// of its user.js file that is merged with the preferences of the profile. The files
+// with the names of the added files are skipped.
+func (p *FirefoxProfile) cloneTemplate(zw *zip.Writer, added map[string]string) (userPrefs []byte, err error) {
+	err = filepath.Walk(p.templateDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		if _, ok := firefoxProfileSkipFiles[info.Name()]; ok {
+			return nil
+		}
+		rel, err := filepath.Rel(p.templateDir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel == firefoxUserPrefsFile {
+			data, err := ioutil.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			userPrefs = data
+			if len(data) > 0 && data[len(data)-1] != '\n' {
+				userPrefs = append(userPrefs, '\n')
+			}
+			return nil
+		}
+		if _, ok := added[rel]; ok {
+			return nil
+		}
+		return zipFile(zw, rel, path)
+	})
+	return userPrefs, err
+}
+
+func (p *FirefoxProfile) userPrefs() []byte {
+	keys := make([]string, 0, len(p.prefs))
+	for key := range p.prefs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	buf := new(bytes.Buffer)
+	for _, key := range keys {
+		name, _ := json.Marshal(key)
+		value, err := json.Marshal(p.prefs[key])
+		if err != nil {
+			value, _ = json.Marshal(fmt.Sprint(p.prefs[key]))
+		}
+		fmt.Fprintf(buf, "user_pref(%s, %s);\n", name, value)
+	}
+	return buf.Bytes()
+}
+
+// LoadFirefoxProfile returns the base64 encoded zipped copy of the existing profile directory.
+func LoadFirefoxProfile(profileDir string) (base64 string, err error) {
+	info, err := os.Stat(profileDir)
+	if err != nil {
+		return base64, err
+	}
+	if !info.IsDir() {
+		return base64, fmt.Errorf("webdriver: %s is not a directory", profileDir)
+	}
+	return NewFirefoxProfile(profileDir).Encode()
+}
+
+// firefoxExtensionID reads the add-on ID from the manifest of the extension.
+// Firefox only loads the extensions from the profile whose file name is the add-on ID.
+func firefoxExtensionID(xpiPath string) (string, error) {
+	zr, err := zip.OpenReader(xpiPath)
+	if err != nil {
+		return "", err
+	}
+	defer zr.Close()
+
+	for _, file := range zr.File {
+		if file.Name != firefoxExtensionManifest {
+			continue
+		}
+		rc, err := file.Open()
+		if err != nil {
+			return "", err
+		}
+		defer rc.Close()
+
+		var manifest struct {
+			BrowserSpecificSettings struct {
+				Gecko struct {
+					ID string `json:"id"`
+				} `json:"gecko"`
+			} `json:"browser_specific_settings"`
+			Applications struct {
+				Gecko struct {
+					ID string `json:"id"`
+				} `json:"gecko"`
+			} `json:"applications"`
+		}
+		if err := json.NewDecoder(rc).Decode(&manifest); err != nil {
+			return "", err
+		}
+		if id := manifest.BrowserSpecificSettings.Gecko.ID; len(id) > 0 {
+			return id, nil
+		}
+		if id := manifest.Applications.Gecko.ID; len(id) > 0 {
+			return id, nil
+		}
+		break
+	}
+	return "", ErrExtensionID
+}
+
+func zipFile(zw *zip.Writer, name string, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	fw, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(fw, f)
+	return err
+}
@@ -0,0 +1,150 @@
+package webdriver
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeTestXPI(t *testing.T, path string, manifest string) {
+	buf := new(bytes.Buffer)
+	zw := zip.NewWriter(buf)
+	fw, err := zw.Create("manifest.json")
+	assert.Nil(t, err)
+	_, err = fw.Write([]byte(manifest))
+	assert.Nil(t, err)
+	assert.Nil(t, zw.Close())
+	assert.Nil(t, ioutil.WriteFile(path, buf.Bytes(), 0644))
+}
+
+func readTestProfile(t *testing.T, b64 string) map[string]string {
+	data, err := base64.StdEncoding.DecodeString(b64)
+	assert.Nil(t, err)
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	assert.Nil(t, err)
+	files := make(map[string]string)
+	for _, file := range zr.File {
+		rc, err := file.Open()
+		assert.Nil(t, err)
+		content, err := ioutil.ReadAll(rc)
+		assert.Nil(t, err)
+		_ = rc.Close()
+		files[file.Name] = string(content)
+	}
+	return files
+}
+
+func TestFirefoxProfile(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "firefox-profile")
+	assert.Nil(t, err)
+	defer os.RemoveAll(tmp)
+
+	// template profile
+	template := filepath.Join(tmp, "template")
+	assert.Nil(t, os.MkdirAll(filepath.Join(template, "storage"), os.ModePerm))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(template, "user.js"), []byte(`user_pref("a", 1);`), 0644))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(template, "storage", "data"), []byte(`data`), 0644))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(template, "lock"), []byte(``), 0644))
+
+	// certificates
+	certPath := filepath.Join(tmp, "cert_override.txt")
+	assert.Nil(t, ioutil.WriteFile(certPath, []byte(`override`), 0644))
+
+	// extensions
+	xpiPath := filepath.Join(tmp, "ext.xpi")
+	writeTestXPI(t, xpiPath, `{"browser_specific_settings":{"gecko":{"id":"ext@example.com"}}}`)
+	legacyPath := filepath.Join(tmp, "legacy.xpi")
+	writeTestXPI(t, legacyPath, `{"applications":{"gecko":{"id":"legacy@example.com"}}}`)
+
+	profile := NewFirefoxProfile(template).
+		SetPref("browser.startup.homepage", "https://example.com").
+		SetPref("dom.disable_open_during_load", false).
+		SetPref("", "ignored")
+	assert.Nil(t, profile.AddCertificate(certPath))
+	assert.Nil(t, profile.AddExtension(xpiPath))
+	assert.Nil(t, profile.AddExtension(legacyPath))
+
+	b64, err := profile.Encode()
+	assert.Nil(t, err)
+	files := readTestProfile(t, b64)
+	assert.Len(t, files, 5)
+	assert.Equal(t, "data", files["storage/data"])
+	assert.Equal(t, "override", files["cert_override.txt"])
+	assert.Contains(t, files, "extensions/ext@example.com.xpi")
+	assert.Contains(t, files, "extensions/legacy@example.com.xpi")
+	assert.Equal(t, `user_pref("a", 1);
+user_pref("browser.startup.homepage", "https://example.com");
+user_pref("dom.disable_open_during_load", false);
+`, files["user.js"])
+
+	// firefox options
+	opts := FirefoxOptions()
+	assert.Nil(t, opts.SetFirefoxProfile(profile))
+	assert.True(t, IsBase64(opts.Build().AlwaysMatch().Section(FirefoxOptionsKey).GetString(FirefoxCapabilityProfileName)))
+
+	// load existing profile
+	b64, err = LoadFirefoxProfile(template)
+	assert.Nil(t, err)
+	files = readTestProfile(t, b64)
+	assert.Len(t, files, 2)
+	assert.Equal(t, `user_pref("a", 1);`+"\n", files["user.js"])
+	_, err = LoadFirefoxProfile(certPath)
+	assert.Error(t, err)
+
+	// bad files
+	assert.Equal(t, ErrUnsupportedCertificateFile, profile.AddCertificate(filepath.Join(tmp, "cert.pem")))
+	assert.Error(t, profile.AddCertificate(filepath.Join(tmp, "cert9.db")))
+	assert.Error(t, profile.AddExtension(filepath.Join(tmp, "ext.zip")))
+	assert.Error(t, profile.AddExtension(filepath.Join(tmp, "missing.xpi")))
+
+	noIDPath := filepath.Join(tmp, "noid.xpi")
+	writeTestXPI(t, noIDPath, `{"name":"ext"}`)
+	profile = NewFirefoxProfile("")
+	assert.Nil(t, profile.AddExtension(noIDPath))
+	_, err = profile.Encode()
+	assert.Equal(t, ErrExtensionID, err)
+}
+
+func TestFirefoxProfile_OverrideTemplate(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "firefox-profile")
+	assert.Nil(t, err)
+	defer os.RemoveAll(tmp)
+
+	// the template has the certificate and the extension
+	template := filepath.Join(tmp, "template")
+	assert.Nil(t, os.MkdirAll(filepath.Join(template, "extensions"), os.ModePerm))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(template, "cert_override.txt"), []byte(`template`), 0644))
+	writeTestXPI(t, filepath.Join(template, "extensions", "ext@example.com.xpi"), `{"name":"template"}`)
+
+	certPath := filepath.Join(tmp, "cert_override.txt")
+	assert.Nil(t, ioutil.WriteFile(certPath, []byte(`added`), 0644))
+	xpiPath := filepath.Join(tmp, "ext.xpi")
+	writeTestXPI(t, xpiPath, `{"browser_specific_settings":{"gecko":{"id":"ext@example.com"}}}`)
+
+	profile := NewFirefoxProfile(template)
+	assert.Nil(t, profile.AddCertificate(certPath))
+	assert.Nil(t, profile.AddExtension(xpiPath))
+	assert.Nil(t, profile.AddExtension(xpiPath))
+
+	buf := new(bytes.Buffer)
+	assert.Nil(t, profile.Zip(buf))
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.Nil(t, err)
+	var names []string
+	for _, file := range zr.File {
+		names = append(names, file.Name)
+	}
+	assert.ElementsMatch(t, []string{"cert_override.txt", "extensions/ext@example.com.xpi"}, names)
+
+	files := readTestProfile(t, base64.StdEncoding.EncodeToString(buf.Bytes()))
+	assert.Equal(t, "added", files["cert_override.txt"])
+	xpi, err := ioutil.ReadFile(xpiPath)
+	assert.Nil(t, err)
+	assert.Equal(t, string(xpi), files["extensions/ext@example.com.xpi"])
+}
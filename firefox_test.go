@@ -0,0 +1,94 @@
+package webdriver
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mediabuyerbot/go-webdriver/pkg/discovery"
+	"github.com/mediabuyerbot/go-webdriver/pkg/download"
+	"github.com/mediabuyerbot/go-webdriver/pkg/geckodriver"
+)
+
+func TestDefaultFirefoxLauncherOptions(t *testing.T) {
+	lo := DefaultFirefoxLauncherOptions()
+	assert.Equal(t, context.Background(), lo.Context)
+	assert.Equal(t, geckodriver.Error, lo.LogLevel)
+	assert.NotNil(t, lo.Output)
+	assert.Empty(t, lo.DriverPath)
+	assert.Nil(t, lo.Downloader)
+	assert.Nil(t, lo.DriverSource)
+	assert.Zero(t, lo.Port)
+}
+
+func TestLaunchFirefox(t *testing.T) {
+	// returns error if the driver is not found
+	browser, err := LaunchFirefox(&FirefoxLauncherOptions{
+		DriverPath: "/path/to/missing/geckodriver",
+	}, nil)
+	assert.Error(t, err)
+	assert.Nil(t, browser)
+}
+
+func TestDownloadGeckoDriver(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("firefox.exe does not print the version")
+	}
+	tmp, err := ioutil.TempDir("", "webdriver")
+	assert.Nil(t, err)
+	defer os.RemoveAll(tmp)
+
+	firefoxPath := filepath.Join(tmp, "firefox")
+	assert.Nil(t, ioutil.WriteFile(firefoxPath, []byte("#!/bin/sh\necho 'Mozilla Firefox 128.0'\n"), 0755))
+
+	buf := new(bytes.Buffer)
+	gz := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gz)
+	content := "#!/bin/sh\necho 'geckodriver 0.36.0'\n"
+	assert.Nil(t, tw.WriteHeader(&tar.Header{Name: "geckodriver", Mode: 0755, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+	_, err = tw.Write([]byte(content))
+	assert.Nil(t, err)
+	assert.Nil(t, tw.Close())
+	assert.Nil(t, gz.Close())
+	archive := buf.Bytes()
+	sum := sha256.Sum256(archive)
+
+	// the mirror of the GitHub releases
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/v0.36.0/") && strings.HasSuffix(r.URL.Path, ".tar.gz"):
+			_, _ = w.Write(archive)
+		case strings.HasPrefix(r.URL.Path, "/v0.36.0/") && strings.HasSuffix(r.URL.Path, ".tar.gz.sha256"):
+			_, _ = w.Write([]byte(hex.EncodeToString(sum[:])))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	// the downloader and the driver source of the launcher options are used
+	lo := DefaultFirefoxLauncherOptions()
+	lo.Downloader = &download.Manager{CacheDir: filepath.Join(tmp, "cache")}
+	lo.DriverSource = download.GeckoDriverSource{BaseURL: srv.URL}
+	path, err := downloadGeckoDriver(context.Background(), lo, FirefoxOptions().SetBinary(firefoxPath))
+	if errors.Is(err, download.ErrUnsupportedPlatform) {
+		t.Skip(err)
+	}
+	assert.Nil(t, err)
+	assert.Equal(t, discovery.GeckoDriver.CachePath(lo.Downloader.CacheDir, "0.36.0"), path)
+	assert.Nil(t, discovery.CheckFirefox(context.Background(), path, firefoxPath))
+}
@@ -0,0 +1,48 @@
+package webdriver
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/mediabuyerbot/go-webdriver/pkg/proctree"
+)
+
+var (
+	janitorOnce sync.Once
+	janitor     *proctree.Janitor
+)
+
+// driverJanitor returns the janitor tracking the drivers started by the Chrome and Firefox launchers.
+// Returns nil if the PID file directory can not be created.
+func driverJanitor() *proctree.Janitor {
+	janitorOnce.Do(func() {
+		j, err := proctree.NewJanitor(proctree.DefaultDir())
+		if err != nil {
+			log.Printf("[WARN] janitor is disabled\n%v\n", err)
+			return
+		}
+		janitor = j
+	})
+	return janitor
+}
+
+// SweepOrphans kills the drivers and the browsers started by the Chrome and Firefox launchers
+// of the processes that are gone, e.g. crashed without closing the browser. Call it at startup.
+func SweepOrphans() ([]proctree.Record, error) {
+	j := driverJanitor()
+	if j == nil {
+		return nil, nil
+	}
+	return j.Sweep()
+}
+
+// RunJanitor sweeps the orphaned drivers and browsers on the interval until the context is done.
+func RunJanitor(ctx context.Context, interval time.Duration) {
+	j := driverJanitor()
+	if j == nil {
+		return
+	}
+	j.Run(ctx, interval)
+}
@@ -0,0 +1,72 @@
+package webdriver
+
+import (
+	"strings"
+
+	"github.com/mediabuyerbot/go-webdriver/pkg/w3cproto"
+)
+
+// Locator represents a strategy and a value to find elements with.
+type Locator struct {
+	Strategy w3cproto.FindElementStrategy
+	Value    string
+}
+
+func (l Locator) String() string {
+	return string(l.Strategy) + "=" + l.Value
+}
+
+type locators struct{}
+
+// By builds the locators, e.g. By.CSS("form > input"), By.XPath("//a[@href]").
+// The W3C protocol only supports the css selector, link text, partial link text,
+// tag name and xpath strategies, so the ID, Name and ClassName locators are
+// translated to the CSS selectors.
+var By locators
+
+// ID returns a locator of the element whose ID attribute matches the id.
+func (locators) ID(id string) Locator {
+	return Locator{Strategy: w3cproto.ByCSSSelector, Value: `[id="` + escapeCSSString(id) + `"]`}
+}
+
+// CSS returns a locator of the element matching the CSS selector.
+func (locators) CSS(selector string) Locator {
+	return Locator{Strategy: w3cproto.ByCSSSelector, Value: selector}
+}
+
+// XPath returns a locator of the element matching the XPath expression.
+func (locators) XPath(xpath string) Locator {
+	return Locator{Strategy: w3cproto.ByXPATH, Value: xpath}
+}
+
+// LinkText returns a locator of the anchor element whose visible text matches the text.
+func (locators) LinkText(text string) Locator {
+	return Locator{Strategy: w3cproto.ByLinkText, Value: text}
+}
+
+// PartialLinkText returns a locator of the anchor element whose visible text partially matches the text.
+func (locators) PartialLinkText(text string) Locator {
+	return Locator{Strategy: w3cproto.ByPartialLinkText, Value: text}
+}
+
+// Name returns a locator of the element whose NAME attribute matches the name.
+func (locators) Name(name string) Locator {
+	return Locator{Strategy: w3cproto.ByCSSSelector, Value: `[name="` + escapeCSSString(name) + `"]`}
+}
+
+// TagName returns a locator of the element whose tag name matches the name.
+func (locators) TagName(name string) Locator {
+	return Locator{Strategy: w3cproto.ByTagName, Value: name}
+}
+
+// ClassName returns a locator of the element whose class name contains the name.
+// Compound class names are not permitted.
+func (locators) ClassName(name string) Locator {
+	return Locator{Strategy: w3cproto.ByCSSSelector, Value: `[class~="` + escapeCSSString(name) + `"]`}
+}
+
+var cssStringReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\a `)
+
+func escapeCSSString(s string) string {
+	return cssStringReplacer.Replace(s)
+}
@@ -0,0 +1,72 @@
+package webdriver
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mediabuyerbot/go-webdriver/pkg/w3cproto"
+)
+
+func TestLocators(t *testing.T) {
+	tests := []struct {
+		have Locator
+		want Locator
+	}{
+		{By.ID("main"), Locator{w3cproto.ByCSSSelector, `[id="main"]`}},
+		{By.ID(`a"b\c`), Locator{w3cproto.ByCSSSelector, `[id="a\"b\\c"]`}},
+		{By.CSS("form > input"), Locator{w3cproto.ByCSSSelector, "form > input"}},
+		{By.XPath("//a"), Locator{w3cproto.ByXPATH, "//a"}},
+		{By.LinkText("Home"), Locator{w3cproto.ByLinkText, "Home"}},
+		{By.PartialLinkText("Ho"), Locator{w3cproto.ByPartialLinkText, "Ho"}},
+		{By.Name("q"), Locator{w3cproto.ByCSSSelector, `[name="q"]`}},
+		{By.TagName("div"), Locator{w3cproto.ByTagName, "div"}},
+		{By.ClassName("btn"), Locator{w3cproto.ByCSSSelector, `[class~="btn"]`}},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, tt.have)
+	}
+	assert.Equal(t, "xpath=//a", By.XPath("//a").String())
+}
+
+func TestBrowser_FindElements(t *testing.T) {
+	browser, cli, done := newTestBrowser(t)
+	defer done()
+
+	ctx := context.Background()
+
+	cli.EXPECT().Do(ctx, http.MethodPost, "/session/123/elements", w3cproto.Params{
+		"using": w3cproto.ByTagName,
+		"value": "li",
+	}).Times(1).Return(&w3cproto.Response{
+		Value: []byte(`[{"element-6066-11e4-a52e-4f735466cecf":"1"},{"element-6066-11e4-a52e-4f735466cecf":"2"}]`),
+	}, nil)
+	elems, err := browser.FindElements(By.TagName("li"))
+	assert.Nil(t, err)
+	assert.Len(t, elems, 2)
+	assert.Equal(t, By.TagName("li"), elems[1].loc)
+
+	cli.EXPECT().Do(ctx, http.MethodPost, "/session/123/element/2/element", w3cproto.Params{
+		"using": w3cproto.ByCSSSelector,
+		"value": "a",
+	}).Times(1).Return(&w3cproto.Response{
+		Value: []byte(`{"element-6066-11e4-a52e-4f735466cecf":"3"}`),
+	}, nil)
+	child, err := elems[1].FindElement(By.CSS("a"))
+	assert.Nil(t, err)
+	assert.Equal(t, "3", child.elem.ID())
+
+	cli.EXPECT().Do(ctx, http.MethodGet, "/session/123/element/active", nil).Times(1).Return(&w3cproto.Response{
+		Value: []byte(`{"element-6066-11e4-a52e-4f735466cecf":"4"}`),
+	}, nil)
+	active, err := browser.ActiveElement()
+	assert.Nil(t, err)
+	assert.Equal(t, "4", active.elem.ID())
+
+	cli.EXPECT().Do(ctx, http.MethodPost, "/session/123/element", gomock.Any()).Times(1).Return(nil, w3cproto.ErrNoSuchElement)
+	_, err = browser.FindElement(By.ID("missing"))
+	assert.Equal(t, w3cproto.ErrNoSuchElement, err)
+}
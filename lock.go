@@ -0,0 +1,69 @@
+package webdriver
+
+import (
+	"context"
+
+	"github.com/mediabuyerbot/go-webdriver/pkg/w3cproto"
+)
+
+type lockKey struct{}
+
+// commandLock serializes the commands of the session, so that the commands of the goroutines
+// sharing the browser are not interleaved with the compound operations, e.g. CloseWindow
+// switching to the window and closing it. The lock is reentrant through the context.
+type commandLock struct {
+	sem chan struct{}
+}
+
+func newCommandLock() *commandLock {
+	return &commandLock{sem: make(chan struct{}, 1)}
+}
+
+// acquire waits for the lock until the context is done. The returned context holds the lock,
+// so the commands sent with it do not wait. The release function must be called once.
+func (l *commandLock) acquire(ctx context.Context) (context.Context, func(), error) {
+	if l.heldBy(ctx) {
+		return ctx, func() {}, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return ctx, nil, err
+	}
+	select {
+	case l.sem <- struct{}{}:
+		return context.WithValue(ctx, lockKey{}, l), l.release, nil
+	case <-ctx.Done():
+		return ctx, nil, ctx.Err()
+	}
+}
+
+func (l *commandLock) heldBy(ctx context.Context) bool {
+	held, _ := ctx.Value(lockKey{}).(*commandLock)
+	return held == l
+}
+
+func (l *commandLock) release() {
+	<-l.sem
+}
+
+// lockedDoer sends each command holding the lock.
+type lockedDoer struct {
+	doer w3cproto.Doer
+	lock *commandLock
+}
+
+func (d *lockedDoer) Do(ctx context.Context, method string, path string, p w3cproto.Params) (*w3cproto.Response, error) {
+	if d.lock.heldBy(ctx) {
+		return d.doer.Do(ctx, method, path, p)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	select {
+	case d.lock.sem <- struct{}{}:
+		defer d.lock.release()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	// the context is passed as is, the commands sent by the doer do not go through the lock again
+	return d.doer.Do(ctx, method, path, p)
+}
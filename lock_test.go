@@ -0,0 +1,70 @@
+package webdriver
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mediabuyerbot/go-webdriver/pkg/w3cproto"
+	"github.com/mediabuyerbot/go-webdriver/pkg/w3ctest"
+)
+
+func TestCommandLock(t *testing.T) {
+	lock := newCommandLock()
+	ctx, release, err := lock.acquire(context.Background())
+	assert.Nil(t, err)
+
+	// the lock is reentrant through the context
+	_, releaseAgain, err := lock.acquire(ctx)
+	assert.Nil(t, err)
+	releaseAgain()
+
+	// returns error if the lock is not acquired before the deadline
+	timeoutCtx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, _, err = lock.acquire(timeoutCtx)
+	assert.Equal(t, context.DeadlineExceeded, err)
+	doer := &lockedDoer{doer: w3cproto.DoerFunc(func(context.Context, string, string, w3cproto.Params) (*w3cproto.Response, error) {
+		return &w3cproto.Response{}, nil
+	}), lock: lock}
+	_, err = doer.Do(timeoutCtx, "GET", "/status", nil)
+	assert.Equal(t, context.DeadlineExceeded, err)
+	_, err = doer.Do(ctx, "GET", "/status", nil)
+	assert.Nil(t, err)
+
+	release()
+	_, release, err = lock.acquire(context.Background())
+	assert.Nil(t, err)
+	release()
+}
+
+func TestBrowser_Concurrent(t *testing.T) {
+	srv := w3ctest.NewServer()
+	defer srv.Close()
+	browser, err := OpenRemoteBrowser(context.Background(), srv.URL, w3cproto.NewBrowserOptions(nil, nil))
+	assert.Nil(t, err)
+	defer browser.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ctx := context.Background()
+			assert.Nil(t, browser.NavigateToContext(ctx, fmt.Sprintf("https://example.com/%d", i)))
+			assert.Nil(t, browser.ResizeToContext(ctx, 800+i, 600+i))
+			_, err := browser.CurrentURLContext(ctx)
+			assert.Nil(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	// the window has the size set by one of the goroutines
+	width, height, err := browser.ScreenSize()
+	assert.Nil(t, err)
+	assert.Equal(t, 200, width-height)
+}
@@ -0,0 +1,214 @@
+// Package cassette records the WebDriver commands and their responses to a JSONL cassette
+// and replays them back, so that the browser flows can be tested without a driver.
+//
+// The session ids, the element ids and the shadow root ids are normalized to the stable
+// placeholders (session-1, element-1, shadow-1, ...) assigned in the order of appearance,
+// so that the cassettes do not change when the flow is recorded again.
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mediabuyerbot/go-webdriver/pkg/w3cproto"
+)
+
+// Interaction is the command and its outcome recorded in the cassette.
+type Interaction struct {
+	Method   string             `json:"method"`
+	Path     string             `json:"path"`
+	Params   json.RawMessage    `json:"params,omitempty"`
+	Response *w3cproto.Response `json:"response,omitempty"`
+	Error    *Error             `json:"error,omitempty"`
+}
+
+// Error is the recorded error of the command.
+type Error struct {
+	// Protocol is the WebDriver protocol error.
+	Protocol *w3cproto.Error `json:"protocol,omitempty"`
+
+	// Status is the HTTP status of the protocol error response.
+	Status int `json:"status,omitempty"`
+
+	// HTTP is the HTTP error status with a non-JSON body.
+	HTTP *w3cproto.HTTPError `json:"http,omitempty"`
+
+	// Message is the message of any other error, e.g. the connection error.
+	Message string `json:"message,omitempty"`
+}
+
+func newError(err error) *Error {
+	var (
+		protoErr *w3cproto.Error
+		httpErr  *w3cproto.HTTPError
+	)
+	switch {
+	case errors.As(err, &protoErr):
+		return &Error{Protocol: protoErr, Status: protoErr.StatusCode}
+	case errors.As(err, &httpErr):
+		return &Error{HTTP: httpErr}
+	default:
+		return &Error{Message: err.Error()}
+	}
+}
+
+// Err returns the error replayed to the client.
+func (e *Error) Err() error {
+	switch {
+	case e.Protocol != nil:
+		value, err := json.Marshal(e.Protocol)
+		if err != nil {
+			return err
+		}
+		return w3cproto.ParseError(e.Status, &w3cproto.Response{Value: value})
+	case e.HTTP != nil:
+		httpErr := *e.HTTP
+		return &httpErr
+	default:
+		return errors.New(e.Message)
+	}
+}
+
+const (
+	sessionKind = "session"
+	elementKind = "element"
+	shadowKind  = "shadow"
+)
+
+// normalizer replaces the ids with the placeholders assigned in the order of appearance.
+type normalizer struct {
+	ids    map[string]map[string]string
+	counts map[string]int
+}
+
+func newNormalizer() *normalizer {
+	return &normalizer{
+		ids:    make(map[string]map[string]string),
+		counts: make(map[string]int),
+	}
+}
+
+func (n *normalizer) id(kind string, id string) string {
+	if len(id) == 0 {
+		return id
+	}
+	ids, ok := n.ids[kind]
+	if !ok {
+		ids = make(map[string]string)
+		n.ids[kind] = ids
+	}
+	placeholder, ok := ids[id]
+	if !ok {
+		n.counts[kind]++
+		placeholder = fmt.Sprintf("%s-%d", kind, n.counts[kind])
+		ids[id] = placeholder
+	}
+	return placeholder
+}
+
+// path normalizes the ids in the segments following the session, element and shadow segments:
+//
+//	/session/{session id}/element/{element id}/shadow
+//	/session/{session id}/shadow/{shadow id}/element
+func (n *normalizer) path(path string) string {
+	segments := strings.Split(path, "/")
+	for i := 1; i < len(segments); i++ {
+		kind, id := segments[i-1], segments[i]
+		switch {
+		case kind == sessionKind:
+			segments[i] = n.id(sessionKind, id)
+		case kind == elementKind && id != "active":
+			segments[i] = n.id(elementKind, id)
+		case kind == shadowKind:
+			segments[i] = n.id(shadowKind, id)
+		default:
+			continue
+		}
+		// the id is not a kind segment itself
+		i++
+	}
+	return strings.Join(segments, "/")
+}
+
+// json normalizes the ids in the JSON value.
+func (n *normalizer) json(data []byte) (json.RawMessage, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(n.value(v))
+}
+
+func (n *normalizer) value(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		// the placeholders are assigned in the same order on every run
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			value := v[key]
+			s, isString := value.(string)
+			switch {
+			case isString && key == "sessionId":
+				v[key] = n.id(sessionKind, s)
+			case isString && key == w3cproto.WebElementIdentifier:
+				v[key] = n.id(elementKind, s)
+			case isString && key == w3cproto.ShadowRootIdentifier:
+				v[key] = n.id(shadowKind, s)
+			default:
+				v[key] = n.value(value)
+			}
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = n.value(value)
+		}
+	}
+	return v
+}
+
+// request returns the normalized command.
+func (n *normalizer) request(method string, path string, p w3cproto.Params) (*Interaction, error) {
+	in := &Interaction{
+		Method: method,
+		Path:   n.path(path),
+	}
+	if p != nil {
+		data, err := json.Marshal(p)
+		if err != nil {
+			return nil, err
+		}
+		if in.Params, err = n.json(data); err != nil {
+			return nil, err
+		}
+	}
+	return in, nil
+}
+
+// response returns the normalized copy of the response.
+func (n *normalizer) response(resp *w3cproto.Response) (*w3cproto.Response, error) {
+	if resp == nil {
+		return nil, nil
+	}
+	value, err := n.json(resp.Value)
+	if err != nil {
+		return nil, err
+	}
+	return &w3cproto.Response{
+		SessionID: n.id(sessionKind, resp.SessionID),
+		Status:    resp.Status,
+		Value:     value,
+	}, nil
+}
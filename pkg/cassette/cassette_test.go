@@ -0,0 +1,154 @@
+package cassette
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mediabuyerbot/go-webdriver/pkg/w3cproto"
+)
+
+// newRemoteEnd returns the doer emulating the remote end that generates the ids from the seed.
+func newRemoteEnd(seed string) w3cproto.Doer {
+	sessID := "sess-" + seed
+	elemID := "elem-" + seed
+	return w3cproto.DoerFunc(func(ctx context.Context, method string, path string, p w3cproto.Params) (*w3cproto.Response, error) {
+		value := func(v interface{}) (*w3cproto.Response, error) {
+			data, err := json.Marshal(v)
+			return &w3cproto.Response{Value: data}, err
+		}
+		session := "/session/" + sessID
+		switch {
+		case method == http.MethodPost && path == "/session":
+			return value(map[string]interface{}{"sessionId": sessID, "capabilities": map[string]interface{}{"browserName": "fake"}})
+		case method == http.MethodPost && path == session+"/url":
+			return value(nil)
+		case method == http.MethodPost && path == session+"/element" && p["value"] == "#login":
+			return value(map[string]string{w3cproto.WebElementIdentifier: elemID})
+		case method == http.MethodPost && path == session+"/element":
+			return nil, &w3cproto.Error{Code: "no such element", Message: "no such element: " + fmt.Sprint(p["value"])}
+		case method == http.MethodGet && path == session+"/element/"+elemID+"/text":
+			return value("Log in")
+		case method == http.MethodPost && path == session+"/element/"+elemID+"/click":
+			return value(nil)
+		case method == http.MethodGet && path == session+"/title":
+			return nil, &w3cproto.HTTPError{StatusCode: http.StatusBadGateway, Body: "Bad Gateway"}
+		}
+		return nil, errors.New("unexpected command " + method + " " + path)
+	})
+}
+
+// runFlow runs the flow and returns its outcome.
+func runFlow(t *testing.T, doer w3cproto.Doer) []string {
+	ctx := context.Background()
+	var out []string
+	sess, err := w3cproto.NewSession(ctx, doer, nil)
+	assert.Nil(t, err)
+	assert.Nil(t, w3cproto.NewNavigation(doer, sess.ID()).NavigateTo(ctx, "https://example.com"))
+	elements := w3cproto.NewElements(doer, sess.ID())
+	elem, err := elements.FindOne(ctx, w3cproto.ByCSSSelector, "#login")
+	assert.Nil(t, err)
+	text, err := elem.Text(ctx)
+	assert.Nil(t, err)
+	out = append(out, text)
+	assert.Nil(t, elem.Click(ctx))
+	_, err = elements.FindOne(ctx, w3cproto.ByCSSSelector, "#logout")
+	assert.True(t, errors.Is(err, w3cproto.ErrNoSuchElement))
+	out = append(out, err.Error())
+	_, err = w3cproto.NewNavigation(doer, sess.ID()).GetTitle(ctx)
+	var httpErr *w3cproto.HTTPError
+	assert.True(t, errors.As(err, &httpErr))
+	out = append(out, err.Error())
+	return out
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	var cassette bytes.Buffer
+	want := runFlow(t, w3cproto.Chain(newRemoteEnd("a1b2"), Record(&cassette)))
+	assert.Equal(t, "Log in", want[0])
+
+	// the ids are normalized
+	lines := strings.Split(strings.TrimSpace(cassette.String()), "\n")
+	assert.Len(t, lines, 7)
+	assert.NotContains(t, cassette.String(), "a1b2")
+	assert.Contains(t, lines[0], `"sessionId":"session-1"`)
+	assert.Contains(t, lines[1], `"path":"/session/session-1/url"`)
+	assert.Contains(t, lines[2], `"element-6066-11e4-a52e-4f735466cecf":"element-1"`)
+	assert.Contains(t, lines[3], `"path":"/session/session-1/element/element-1/text"`)
+
+	// the recording is stable
+	var again bytes.Buffer
+	runFlow(t, w3cproto.Chain(newRemoteEnd("c3d4"), Record(&again)))
+	assert.Equal(t, cassette.String(), again.String())
+
+	// returns the recorded outcomes
+	player, err := NewPlayer(strings.NewReader(cassette.String()))
+	assert.Nil(t, err)
+	assert.Equal(t, 7, player.Remaining())
+	assert.Equal(t, want, runFlow(t, player))
+	assert.Equal(t, 0, player.Remaining())
+
+	// returns error if the cassette is exhausted
+	_, err = player.Do(context.Background(), http.MethodGet, "/session/session-1/url", nil)
+	assert.True(t, errors.Is(err, ErrExhausted))
+}
+
+func TestPlayer_Match(t *testing.T) {
+	ctx := context.Background()
+	cassette := `{"method":"POST","path":"/session/session-1/url","params":{"url":"https://example.com"},"response":{"value":null}}
+{"method":"GET","path":"/session/session-1/element/active","response":{"value":{"element-6066-11e4-a52e-4f735466cecf":"element-1"}}}
+{"method":"POST","path":"/session/session-1/element/element-1/click","params":{},"response":{"value":null}}
+`
+	// returns error if the params do not match
+	player, err := NewPlayer(strings.NewReader(cassette))
+	assert.Nil(t, err)
+	_, err = player.Do(ctx, http.MethodPost, "/session/123/url", w3cproto.Params{"url": "https://example.org"})
+	assert.True(t, errors.Is(err, ErrMismatch))
+	assert.Contains(t, err.Error(), "https://example.org")
+
+	// returns success if the ids differ from the recorded ones
+	player, err = NewPlayer(strings.NewReader(cassette))
+	assert.Nil(t, err)
+	_, err = player.Do(ctx, http.MethodPost, "/session/123/url", w3cproto.Params{"url": "https://example.com"})
+	assert.Nil(t, err)
+	resp, err := player.Do(ctx, http.MethodGet, "/session/123/element/active", nil)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"element-6066-11e4-a52e-4f735466cecf":"element-1"}`, string(resp.Value))
+	_, err = player.Do(ctx, http.MethodPost, "/session/123/element/element-1/click", w3cproto.Params{})
+	assert.Nil(t, err)
+
+	// returns success if the matcher ignores the params
+	player, err = NewPlayer(strings.NewReader(cassette), WithMatcher(MatchMethodAndPath))
+	assert.Nil(t, err)
+	_, err = player.Do(ctx, http.MethodPost, "/session/123/url", w3cproto.Params{"url": "https://example.org"})
+	assert.Nil(t, err)
+}
+
+func TestNewPlayer_InvalidCassette(t *testing.T) {
+	_, err := NewPlayer(strings.NewReader("{\"method\":\"GET\"}\n\nnot json\n"))
+	assert.EqualError(t, err, "cassette: line 3: invalid character 'o' in literal null (expecting 'u')")
+}
+
+func TestError_Err(t *testing.T) {
+	value := []byte(`{"error":"unknown error","message":"upstream","stacktrace":"#0 0x1\n\n  #1 0x2\n"}`)
+	want := w3cproto.ParseError(http.StatusServiceUnavailable, &w3cproto.Response{Value: value})
+
+	// the recorded protocol error is replayed as decoded by w3cproto
+	var recorded Error
+	data, err := json.Marshal(newError(want))
+	assert.Nil(t, err)
+	assert.Nil(t, json.Unmarshal(data, &recorded))
+	err = recorded.Err()
+	assert.Equal(t, want, err)
+	var protoErr *w3cproto.Error
+	assert.True(t, errors.As(err, &protoErr))
+	assert.Equal(t, []string{"#0 0x1", "#1 0x2"}, protoErr.Stacktrace)
+	assert.True(t, w3cproto.IsTransient(err))
+}
@@ -0,0 +1,155 @@
+package cassette
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sync"
+
+	"github.com/mediabuyerbot/go-webdriver/pkg/w3cproto"
+)
+
+var (
+	ErrExhausted = errors.New("cassette: no more recorded interactions")
+	ErrMismatch  = errors.New("cassette: command does not match the recorded interaction")
+)
+
+// Matcher reports whether the normalized command matches the recorded interaction.
+type Matcher func(recorded *Interaction, command *Interaction) bool
+
+// MatchMethodAndPath matches the commands with the same method and path.
+func MatchMethodAndPath(recorded *Interaction, command *Interaction) bool {
+	return recorded.Method == command.Method && recorded.Path == command.Path
+}
+
+// MatchParams matches the commands with the equal JSON params.
+func MatchParams(recorded *Interaction, command *Interaction) bool {
+	if len(recorded.Params) == 0 || len(command.Params) == 0 {
+		return len(recorded.Params) == len(command.Params)
+	}
+	var a, b interface{}
+	if err := json.Unmarshal(recorded.Params, &a); err != nil {
+		return false
+	}
+	if err := json.Unmarshal(command.Params, &b); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// MatchAll matches the commands matched by all the matchers.
+func MatchAll(matchers ...Matcher) Matcher {
+	return func(recorded *Interaction, command *Interaction) bool {
+		for _, match := range matchers {
+			if !match(recorded, command) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// DefaultMatcher matches the commands with the same method, path and params.
+var DefaultMatcher = MatchAll(MatchMethodAndPath, MatchParams)
+
+// Option configures the player.
+type Option func(*Player)
+
+// WithMatcher sets the matcher of the commands. DefaultMatcher is used by default.
+func WithMatcher(m Matcher) Option {
+	return func(p *Player) {
+		p.match = m
+	}
+}
+
+// Player is the doer that serves the recorded interactions back in order.
+// Each command must match the next recorded interaction.
+type Player struct {
+	interactions []Interaction
+	pos          int
+	match        Matcher
+	norm         *normalizer
+	lock         sync.Mutex
+}
+
+// NewPlayer reads the cassette from the reader.
+func NewPlayer(r io.Reader, opts ...Option) (*Player, error) {
+	p := &Player{
+		match: DefaultMatcher,
+		norm:  newNormalizer(),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 64<<20)
+	for line := 1; scanner.Scan(); line++ {
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+		var in Interaction
+		if err := json.Unmarshal(data, &in); err != nil {
+			return nil, fmt.Errorf("cassette: line %d: %v", line, err)
+		}
+		p.interactions = append(p.interactions, in)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Load reads the cassette from the file.
+func Load(filename string, opts ...Option) (*Player, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return NewPlayer(f, opts...)
+}
+
+// Do returns the outcome of the next recorded interaction.
+func (p *Player) Do(_ context.Context, method string, path string, params w3cproto.Params) (*w3cproto.Response, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	command, err := p.norm.request(method, path, params)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos >= len(p.interactions) {
+		return nil, fmt.Errorf("%w: %s %s", ErrExhausted, command.Method, command.Path)
+	}
+	recorded := &p.interactions[p.pos]
+	if !p.match(recorded, command) {
+		return nil, fmt.Errorf("%w #%d: expected %s %s %s, got %s %s %s", ErrMismatch, p.pos+1,
+			recorded.Method, recorded.Path, recorded.Params, command.Method, command.Path, command.Params)
+	}
+	p.pos++
+	if recorded.Error != nil {
+		return nil, recorded.Error.Err()
+	}
+	if recorded.Response == nil {
+		return nil, fmt.Errorf("cassette: interaction #%d has neither response nor error", p.pos)
+	}
+	// keeps the placeholders in sync with the recording
+	if _, err := p.norm.response(recorded.Response); err != nil {
+		return nil, err
+	}
+	resp := *recorded.Response
+	return &resp, nil
+}
+
+// Remaining returns the number of the interactions not replayed yet.
+func (p *Player) Remaining() int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return len(p.interactions) - p.pos
+}
@@ -0,0 +1,57 @@
+package cassette
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+
+	"github.com/mediabuyerbot/go-webdriver/pkg/w3cproto"
+)
+
+// Recorder is the doer that passes the commands to the next doer and writes
+// the normalized commands and their outcomes to the cassette, one JSON object per line.
+type Recorder struct {
+	next w3cproto.Doer
+	enc  *json.Encoder
+	norm *normalizer
+	lock sync.Mutex
+}
+
+// NewRecorder returns the recorder writing the cassette to the writer.
+func NewRecorder(next w3cproto.Doer, w io.Writer) *Recorder {
+	return &Recorder{
+		next: next,
+		enc:  json.NewEncoder(w),
+		norm: newNormalizer(),
+	}
+}
+
+// Record returns the middleware recording the commands to the writer.
+func Record(w io.Writer) w3cproto.Middleware {
+	return func(next w3cproto.Doer) w3cproto.Doer {
+		return NewRecorder(next, w)
+	}
+}
+
+// Do executes the command and records it. The error writing the cassette
+// is returned instead of the outcome of the command.
+func (r *Recorder) Do(ctx context.Context, method string, path string, p w3cproto.Params) (*w3cproto.Response, error) {
+	resp, err := r.next.Do(ctx, method, path, p)
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	in, nerr := r.norm.request(method, path, p)
+	if nerr != nil {
+		return nil, nerr
+	}
+	if err != nil {
+		in.Error = newError(err)
+	} else if in.Response, nerr = r.norm.response(resp); nerr != nil {
+		return nil, nerr
+	}
+	if werr := r.enc.Encode(in); werr != nil {
+		return nil, werr
+	}
+	return resp, err
+}
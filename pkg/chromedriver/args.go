@@ -67,6 +67,7 @@ func (ll LogLevel) Validate() error {
 
 type arguments struct {
 	args map[string]string
+	port int
 }
 
 func newArguments() *arguments {
@@ -80,6 +81,7 @@ func (a *arguments) setPort(port int) error {
 		return err
 	}
 	a.args[argsPort] = fmt.Sprintf(argsPort, port)
+	a.port = port
 	return nil
 }
 
@@ -0,0 +1,111 @@
+package chromedriver
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const helperDriverEnv = "GO_WEBDRIVER_HELPER_DRIVER"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(helperDriverEnv) == "1" {
+		runHelperDriver()
+	}
+	os.Exit(m.Run())
+}
+
+// runHelperDriver emulates a driver that ignores the shutdown signals and
+// leaves a browser process behind when the test binary is started by the tests below.
+func runHelperDriver() {
+	signal.Ignore(os.Interrupt, syscall.SIGTERM)
+
+	var port string
+	for _, arg := range os.Args {
+		if strings.HasPrefix(arg, "--port=") {
+			port = strings.TrimPrefix(arg, "--port=")
+		}
+	}
+	browser := exec.Command("sleep", "30")
+	if err := browser.Start(); err != nil {
+		os.Exit(1)
+	}
+	http.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"value":{"ready":true}}`))
+	})
+	_ = http.ListenAndServe(net.JoinHostPort(defaultHost, port), nil)
+	os.Exit(1)
+}
+
+func freeTestPort(t *testing.T) int {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port
+}
+
+func TestProcess_StopEscalation(t *testing.T) {
+	assert.Nil(t, os.Setenv(helperDriverEnv, "1"))
+	defer os.Unsetenv(helperDriverEnv)
+
+	var (
+		lock    sync.Mutex
+		stopped []int
+		started = make(chan int, 1)
+	)
+	driver, err := New(os.Args[0],
+		WithPort(freeTestPort(t)),
+		WithStopTimeout(50*time.Millisecond),
+		WithRunHook(func(pid int) {
+			started <- pid
+		}),
+		WithStopHook(func(pid int) {
+			lock.Lock()
+			stopped = append(stopped, pid)
+			lock.Unlock()
+		}),
+	)
+	assert.Nil(t, err)
+
+	ctx := context.Background()
+	done := make(chan error, 1)
+	go func() {
+		done <- driver.Run(ctx)
+	}()
+
+	var pid int
+	select {
+	case pid = <-started:
+	case err := <-done:
+		t.Fatalf("driver exited: %v", err)
+	}
+
+	assert.Nil(t, driver.Stop(ctx))
+	assert.Nil(t, <-done)
+
+	lock.Lock()
+	defer lock.Unlock()
+	assert.Contains(t, stopped, pid)
+	assert.Nil(t, driver.Stop(ctx))
+}
+
+func TestProcess_PortInUse(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer l.Close()
+
+	driver, err := New(os.Args[0], WithPort(l.Addr().(*net.TCPAddr).Port))
+	assert.Nil(t, err)
+	err = driver.Run(context.Background())
+	assert.Error(t, err)
+}
@@ -0,0 +1,125 @@
+// Package discovery finds the driver binaries and checks that they are compatible with the browsers.
+package discovery
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+var ErrDriverNotFound = errors.New("discovery: driver not found")
+
+// Driver describes where to look for a driver binary.
+type Driver struct {
+	// Name is the name of the driver binary without the extension, e.g. chromedriver.
+	Name string
+
+	// Env is the environment variable holding the path to the driver binary.
+	Env string
+
+	// Fallback returns the path checked if the driver is not found elsewhere.
+	Fallback func() string
+}
+
+var (
+	ChromeDriver = Driver{Name: "chromedriver", Env: "CHROMEDRIVER_PATH"}
+	GeckoDriver  = Driver{Name: "geckodriver", Env: "GECKODRIVER_PATH"}
+)
+
+// CacheDir returns the per-user directory the drivers are cached in.
+// The drivers are kept in the <CacheDir>/<name>/<version>/ directories.
+func CacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "go-webdriver"), nil
+}
+
+// Find returns the path to the driver binary. The driver is looked up in the following order:
+// the explicit path, the environment variable, the PATH directories, the per-user cache directory
+// (the latest cached version wins) and the fallback path.
+func (d Driver) Find(explicit string) (string, error) {
+	tried := make([]string, 0, 5)
+	if len(explicit) > 0 {
+		return lookPath(explicit)
+	}
+	if len(d.Env) > 0 {
+		if path := os.Getenv(d.Env); len(path) > 0 {
+			return lookPath(path)
+		}
+		tried = append(tried, "$"+d.Env)
+	}
+	if path, err := exec.LookPath(d.binaryName()); err == nil {
+		return path, nil
+	}
+	tried = append(tried, "$PATH")
+	if dir, err := CacheDir(); err == nil {
+		if path, ok := d.findCached(dir); ok {
+			return path, nil
+		}
+		tried = append(tried, filepath.Join(dir, d.Name))
+	}
+	if d.Fallback != nil {
+		if path := d.Fallback(); len(path) > 0 {
+			if path, err := lookPath(path); err == nil {
+				return path, nil
+			}
+			tried = append(tried, path)
+		}
+	}
+	return "", fmt.Errorf("%w: %s (looked in %s)", ErrDriverNotFound, d.Name, strings.Join(tried, ", "))
+}
+
+// CachePath returns the path of the driver binary of the version in the cache directory.
+func (d Driver) CachePath(cacheDir string, version string) string {
+	return filepath.Join(cacheDir, d.Name, version, d.binaryName())
+}
+
+func (d Driver) findCached(cacheDir string) (string, bool) {
+	dirs, err := ioutil.ReadDir(filepath.Join(cacheDir, d.Name))
+	if err != nil {
+		return "", false
+	}
+	var (
+		latest     Version
+		latestPath string
+	)
+	for _, dir := range dirs {
+		if !dir.IsDir() {
+			continue
+		}
+		version, err := ParseVersion(dir.Name())
+		if err != nil {
+			continue
+		}
+		path := d.CachePath(cacheDir, dir.Name())
+		if _, err := lookPath(path); err != nil {
+			continue
+		}
+		if len(latestPath) == 0 || latest.Less(version) {
+			latest, latestPath = version, path
+		}
+	}
+	return latestPath, len(latestPath) > 0
+}
+
+func (d Driver) binaryName() string {
+	if runtime.GOOS == "windows" {
+		return d.Name + ".exe"
+	}
+	return d.Name
+}
+
+func lookPath(path string) (string, error) {
+	path, err := exec.LookPath(path)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrDriverNotFound, err)
+	}
+	return path, nil
+}
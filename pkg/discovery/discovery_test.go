@@ -0,0 +1,130 @@
+//go:build !windows
+// +build !windows
+
+package discovery
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeTestBinary(t *testing.T, path string, output string) string {
+	assert.Nil(t, os.MkdirAll(filepath.Dir(path), os.ModePerm))
+	script := "#!/bin/sh\necho '" + output + "'\n"
+	assert.Nil(t, ioutil.WriteFile(path, []byte(script), 0755))
+	return path
+}
+
+func TestDriver_Find(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "discovery")
+	assert.Nil(t, err)
+	defer os.RemoveAll(tmp)
+
+	// isolate the lookup from the host
+	defer os.Setenv("PATH", os.Getenv("PATH"))
+	defer os.Setenv("XDG_CACHE_HOME", os.Getenv("XDG_CACHE_HOME"))
+	assert.Nil(t, os.Setenv("PATH", filepath.Join(tmp, "bin")))
+	assert.Nil(t, os.Setenv("XDG_CACHE_HOME", filepath.Join(tmp, "cache")))
+
+	driver := Driver{Name: "testdriver", Env: "TESTDRIVER_PATH"}
+
+	// not found
+	_, err = driver.Find("")
+	assert.True(t, errors.Is(err, ErrDriverNotFound))
+	assert.Contains(t, err.Error(), "$TESTDRIVER_PATH")
+
+	// fallback
+	fallback := writeTestBinary(t, filepath.Join(tmp, "fallback", "testdriver"), "1.0")
+	driver.Fallback = func() string { return fallback }
+	path, err := driver.Find("")
+	assert.Nil(t, err)
+	assert.Equal(t, fallback, path)
+
+	// cache directory, the latest version wins
+	cacheDir, err := CacheDir()
+	assert.Nil(t, err)
+	writeTestBinary(t, driver.CachePath(cacheDir, "9.0.1"), "9.0.1")
+	latest := writeTestBinary(t, driver.CachePath(cacheDir, "10.0.0"), "10.0.0")
+	assert.Nil(t, os.MkdirAll(filepath.Join(cacheDir, "testdriver", "broken"), os.ModePerm))
+	path, err = driver.Find("")
+	assert.Nil(t, err)
+	assert.Equal(t, latest, path)
+
+	// PATH
+	inPath := writeTestBinary(t, filepath.Join(tmp, "bin", "testdriver"), "2.0")
+	path, err = driver.Find("")
+	assert.Nil(t, err)
+	assert.Equal(t, inPath, path)
+
+	// environment variable
+	fromEnv := writeTestBinary(t, filepath.Join(tmp, "env", "testdriver"), "3.0")
+	assert.Nil(t, os.Setenv("TESTDRIVER_PATH", fromEnv))
+	defer os.Unsetenv("TESTDRIVER_PATH")
+	path, err = driver.Find("")
+	assert.Nil(t, err)
+	assert.Equal(t, fromEnv, path)
+
+	// explicit path
+	explicit := writeTestBinary(t, filepath.Join(tmp, "explicit", "testdriver"), "4.0")
+	path, err = driver.Find(explicit)
+	assert.Nil(t, err)
+	assert.Equal(t, explicit, path)
+
+	_, err = driver.Find(filepath.Join(tmp, "missing"))
+	assert.True(t, errors.Is(err, ErrDriverNotFound))
+}
+
+func TestParseVersion(t *testing.T) {
+	v, err := ParseVersion("ChromeDriver 114.0.5735.90 (386bc09e8f4f2e025eddae123f36f6263096ae49-refs/branch-heads/5735@{#1052})")
+	assert.Nil(t, err)
+	assert.Equal(t, Version{114, 0, 5735, 90}, v)
+	assert.Equal(t, 114, v.Major())
+	assert.Equal(t, 0, v.Minor())
+	assert.Equal(t, "114.0.5735.90", v.String())
+
+	v, err = ParseVersion("Mozilla Firefox 115.0.2esr")
+	assert.Nil(t, err)
+	assert.Equal(t, Version{115, 0, 2}, v)
+
+	_, err = ParseVersion("unknown")
+	assert.True(t, errors.Is(err, ErrInvalidVersion))
+
+	assert.True(t, Version{0, 33}.Less(Version{0, 34}))
+	assert.True(t, Version{1}.Less(Version{1, 0, 1}))
+	assert.False(t, Version{2}.Less(Version{1, 9}))
+	assert.False(t, Version{1, 0}.Less(Version{1}))
+}
+
+func TestCheck(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "discovery")
+	assert.Nil(t, err)
+	defer os.RemoveAll(tmp)
+
+	ctx := context.Background()
+
+	chromedriver := writeTestBinary(t, filepath.Join(tmp, "chromedriver"), "ChromeDriver 114.0.5735.90 (386bc09e)")
+	chrome114 := writeTestBinary(t, filepath.Join(tmp, "chrome114"), "Google Chrome 114.0.5735.198")
+	chrome116 := writeTestBinary(t, filepath.Join(tmp, "chrome116"), "Google Chrome 116.0.5845.96")
+
+	assert.Nil(t, CheckChrome(ctx, chromedriver, chrome114))
+	err = CheckChrome(ctx, chromedriver, chrome116)
+	assert.True(t, errors.Is(err, ErrVersionMismatch))
+	assert.Contains(t, err.Error(), "chromedriver 114.0.5735.90")
+	assert.Contains(t, err.Error(), "Chrome 116.0.5845.96")
+	assert.Error(t, CheckChrome(ctx, chromedriver, filepath.Join(tmp, "missing")))
+
+	geckodriver := writeTestBinary(t, filepath.Join(tmp, "geckodriver"), "geckodriver 0.33.0 (a80e5fd61076 2023-04-02 18:31 +0000)")
+	firefox115 := writeTestBinary(t, filepath.Join(tmp, "firefox115"), "Mozilla Firefox 115.0")
+	firefox91 := writeTestBinary(t, filepath.Join(tmp, "firefox91"), "Mozilla Firefox 91.0")
+
+	assert.Nil(t, CheckFirefox(ctx, geckodriver, firefox115))
+	err = CheckFirefox(ctx, geckodriver, firefox91)
+	assert.True(t, errors.Is(err, ErrVersionMismatch))
+	assert.Contains(t, err.Error(), "requires Firefox 102 or later")
+}
@@ -0,0 +1,194 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidVersion  = errors.New("discovery: invalid version")
+	ErrVersionMismatch = errors.New("discovery: driver and browser versions do not match")
+)
+
+var versionPattern = regexp.MustCompile(`\d+(\.\d+)+`)
+
+// versionTimeout bounds the time the binary has to print the version.
+const versionTimeout = 10 * time.Second
+
+// Version is a dotted version number, e.g. 114.0.5735.90.
+type Version []int
+
+// ParseVersion parses the first dotted version number found in the string.
+func ParseVersion(s string) (Version, error) {
+	match := versionPattern.FindString(s)
+	if len(match) == 0 {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidVersion, s)
+	}
+	parts := strings.Split(match, ".")
+	v := make(Version, len(parts))
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidVersion, s)
+		}
+		v[i] = n
+	}
+	return v, nil
+}
+
+// Major returns the major version number.
+func (v Version) Major() int {
+	if len(v) == 0 {
+		return 0
+	}
+	return v[0]
+}
+
+// Minor returns the minor version number.
+func (v Version) Minor() int {
+	if len(v) < 2 {
+		return 0
+	}
+	return v[1]
+}
+
+// Less returns true if the version precedes the other version.
+func (v Version) Less(other Version) bool {
+	for i := 0; i < len(v) || i < len(other); i++ {
+		var a, b int
+		if i < len(v) {
+			a = v[i]
+		}
+		if i < len(other) {
+			b = other[i]
+		}
+		if a != b {
+			return a < b
+		}
+	}
+	return false
+}
+
+func (v Version) String() string {
+	parts := make([]string, len(v))
+	for i, n := range v {
+		parts[i] = strconv.Itoa(n)
+	}
+	return strings.Join(parts, ".")
+}
+
+// BinaryVersion runs the binary with the --version flag and parses the output.
+func BinaryVersion(ctx context.Context, path string) (Version, error) {
+	ctx, cancel := context.WithTimeout(ctx, versionTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, path, "--version").Output()
+	if err != nil {
+		return nil, fmt.Errorf("discovery: %s --version: %w", path, err)
+	}
+	return ParseVersion(string(out))
+}
+
+// GeckoDriverRelease is the geckodriver release and the minimal Firefox version it supports.
+type GeckoDriverRelease struct {
+	Version    Version
+	MinFirefox int
+}
+
+// GeckoDriverReleases maps the geckodriver releases onto the minimal supported Firefox versions,
+// the latest release first. See https://firefox-source-docs.mozilla.org/testing/geckodriver/Support.html
+var GeckoDriverReleases = []GeckoDriverRelease{
+	{Version{0, 36, 0}, 128},
+	{Version{0, 35, 0}, 115},
+	{Version{0, 34, 0}, 115},
+	{Version{0, 33, 0}, 102},
+	{Version{0, 32, 0}, 102},
+	{Version{0, 31, 0}, 91},
+	{Version{0, 30, 0}, 78},
+	{Version{0, 29, 1}, 60},
+	{Version{0, 27, 0}, 60},
+}
+
+// CheckChrome returns an error that wraps ErrVersionMismatch if the major versions
+// of the chromedriver and the Chrome binaries are different.
+func CheckChrome(ctx context.Context, driverPath string, browserPath string) error {
+	driver, browser, err := versions(ctx, driverPath, browserPath)
+	if err != nil {
+		return err
+	}
+	if driver.Major() != browser.Major() {
+		return fmt.Errorf("%w: chromedriver %s (%s) does not support Chrome %s (%s), the major versions must be equal",
+			ErrVersionMismatch, driver, driverPath, browser, browserPath)
+	}
+	return nil
+}
+
+// CheckFirefox returns an error that wraps ErrVersionMismatch if the Firefox binary is older
+// than the minimal version supported by the geckodriver binary.
+func CheckFirefox(ctx context.Context, driverPath string, browserPath string) error {
+	driver, browser, err := versions(ctx, driverPath, browserPath)
+	if err != nil {
+		return err
+	}
+	for _, release := range GeckoDriverReleases {
+		if driver.Less(release.Version) {
+			continue
+		}
+		if browser.Major() < release.MinFirefox {
+			return fmt.Errorf("%w: geckodriver %s (%s) requires Firefox %d or later, found Firefox %s (%s)",
+				ErrVersionMismatch, driver, driverPath, release.MinFirefox, browser, browserPath)
+		}
+		break
+	}
+	return nil
+}
+
+func versions(ctx context.Context, driverPath string, browserPath string) (driver Version, browser Version, err error) {
+	driver, err = BinaryVersion(ctx, driverPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	browser, err = BinaryVersion(ctx, browserPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	return driver, browser, nil
+}
+
+// ChromeBinary returns the path to the Chrome binary found in the well-known locations.
+func ChromeBinary() (string, bool) {
+	switch runtime.GOOS {
+	case "darwin":
+		return findBinary(
+			"/Applications/Google Chrome.app/Contents/MacOS/Google Chrome",
+			"/Applications/Chromium.app/Contents/MacOS/Chromium",
+		)
+	default:
+		return findBinary("google-chrome", "google-chrome-stable", "chromium", "chromium-browser")
+	}
+}
+
+// FirefoxBinary returns the path to the Firefox binary found in the well-known locations.
+func FirefoxBinary() (string, bool) {
+	switch runtime.GOOS {
+	case "darwin":
+		return findBinary("/Applications/Firefox.app/Contents/MacOS/firefox")
+	default:
+		return findBinary("firefox")
+	}
+}
+
+func findBinary(names ...string) (string, bool) {
+	for _, name := range names {
+		if path, err := exec.LookPath(name); err == nil {
+			return path, true
+		}
+	}
+	return "", false
+}
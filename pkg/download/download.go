@@ -0,0 +1,300 @@
+// Package download resolves, downloads and caches the driver binaries compatible with the installed browsers.
+package download
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mediabuyerbot/go-webdriver/pkg/discovery"
+)
+
+const (
+	zipExt      = ".zip"
+	tarGzExt    = ".tar.gz"
+	checksumExt = ".sha256"
+	lockExt     = ".lock"
+
+	// DefaultStaleLockTimeout is the age after which the lock left by a crashed process is removed.
+	DefaultStaleLockTimeout = 10 * time.Minute
+
+	lockPollInterval = 100 * time.Millisecond
+)
+
+var (
+	ErrChecksumMismatch = errors.New("download: checksum mismatch")
+	ErrNoChecksum       = errors.New("download: checksum not published")
+	ErrBinaryNotFound   = errors.New("download: driver binary not found in the archive")
+)
+
+// Manager downloads the driver releases into the versioned cache directory
+// <CacheDir>/<driver>/<version>/<driver>, where discovery finds them.
+// The concurrent processes installing the same release wait for each other.
+type Manager struct {
+	// CacheDir is the cache directory. discovery.CacheDir() is used if empty.
+	CacheDir string
+
+	// Client is the HTTP client. http.DefaultClient is used if nil.
+	Client *http.Client
+
+	// AllowMissingChecksum allows the installation of the release that has no checksum when
+	// the mirror does not publish the <archive>.sha256 file. The installation fails with
+	// ErrNoChecksum by default, unless the source declares the checksum of the release
+	// optional, see Release.ChecksumOptional.
+	AllowMissingChecksum bool
+
+	// StaleLockTimeout is the age after which a lock is considered left by a crashed process.
+	// DefaultStaleLockTimeout is used if zero.
+	StaleLockTimeout time.Duration
+}
+
+// Install returns the path to the cached driver compatible with the browser version,
+// downloading the driver if it is not cached yet.
+func (m *Manager) Install(ctx context.Context, src Source, browser discovery.Version) (string, error) {
+	release, err := src.Resolve(ctx, m.client(), browser)
+	if err != nil {
+		return "", err
+	}
+	return m.InstallRelease(ctx, release)
+}
+
+// InstallRelease returns the path to the cached driver of the release,
+// downloading the release if it is not cached yet.
+func (m *Manager) InstallRelease(ctx context.Context, release Release) (string, error) {
+	cacheDir, err := m.cacheDir()
+	if err != nil {
+		return "", err
+	}
+	binPath := release.Driver.CachePath(cacheDir, release.Version)
+	if isFile(binPath) {
+		return binPath, nil
+	}
+	versionDir := filepath.Dir(binPath)
+	if err := os.MkdirAll(filepath.Dir(versionDir), 0755); err != nil {
+		return "", err
+	}
+	unlock, err := m.lock(ctx, versionDir+lockExt)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+
+	// the release could be installed while waiting for the lock
+	if isFile(binPath) {
+		return binPath, nil
+	}
+	if err := os.MkdirAll(versionDir, 0755); err != nil {
+		return "", err
+	}
+
+	archive, sum, err := m.fetch(ctx, versionDir, release.URL)
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(archive)
+
+	if err := m.verify(ctx, release, sum); err != nil {
+		return "", err
+	}
+	if err := extract(archive, release.URL, release.Binary, binPath); err != nil {
+		return "", err
+	}
+	return binPath, nil
+}
+
+// fetch downloads the archive into a temporary file and returns its path and SHA-256 checksum.
+func (m *Manager) fetch(ctx context.Context, dir string, url string) (filename string, sum string, err error) {
+	resp, err := do(ctx, m.client(), url)
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+
+	f, err := ioutil.TempFile(dir, ".archive")
+	if err != nil {
+		return "", "", err
+	}
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(f, hash), resp.Body); err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return "", "", err
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(f.Name())
+		return "", "", err
+	}
+	return f.Name(), hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func (m *Manager) verify(ctx context.Context, release Release, sum string) error {
+	want := release.Checksum
+	if len(want) == 0 {
+		data, err := get(ctx, m.client(), release.URL+checksumExt)
+		if err != nil {
+			var statusErr *StatusError
+			if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+				if m.AllowMissingChecksum || release.ChecksumOptional {
+					return nil
+				}
+				return fmt.Errorf("%w: %s", ErrNoChecksum, release.URL)
+			}
+			return err
+		}
+		// the format of the sha256sum utility: <checksum>  <filename>
+		fields := strings.Fields(string(data))
+		if len(fields) == 0 {
+			return fmt.Errorf("%w: %s", ErrNoChecksum, release.URL)
+		}
+		want = fields[0]
+	}
+	if !strings.EqualFold(want, sum) {
+		return fmt.Errorf("%w: %s: expected %s, got %s", ErrChecksumMismatch, release.URL, want, sum)
+	}
+	return nil
+}
+
+// lock creates the lock file, waiting while it is held by another process.
+func (m *Manager) lock(ctx context.Context, filename string) (unlock func(), err error) {
+	stale := m.StaleLockTimeout
+	if stale <= 0 {
+		stale = DefaultStaleLockTimeout
+	}
+	for {
+		f, err := os.OpenFile(filename, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			_, _ = fmt.Fprint(f, os.Getpid())
+			_ = f.Close()
+			return func() {
+				_ = os.Remove(filename)
+			}, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+		if info, err := os.Stat(filename); err == nil && time.Since(info.ModTime()) > stale {
+			_ = os.Remove(filename)
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(lockPollInterval):
+		}
+	}
+}
+
+func (m *Manager) client() *http.Client {
+	if m.Client != nil {
+		return m.Client
+	}
+	return http.DefaultClient
+}
+
+func (m *Manager) cacheDir() (string, error) {
+	if len(m.CacheDir) > 0 {
+		return m.CacheDir, nil
+	}
+	return discovery.CacheDir()
+}
+
+// extract writes the binary from the archive to the destination path.
+func extract(archive string, url string, binary string, dest string) error {
+	switch {
+	case strings.HasSuffix(url, zipExt):
+		return extractZip(archive, binary, dest)
+	case strings.HasSuffix(url, tarGzExt):
+		return extractTarGz(archive, binary, dest)
+	default:
+		return fmt.Errorf("download: unsupported archive %s", path.Base(url))
+	}
+}
+
+func extractZip(archive string, binary string, dest string) error {
+	zr, err := zip.OpenReader(archive)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+	for _, file := range zr.File {
+		if file.Name != binary {
+			continue
+		}
+		rc, err := file.Open()
+		if err != nil {
+			return err
+		}
+		defer rc.Close()
+		return writeBinary(rc, dest)
+	}
+	return fmt.Errorf("%w: %s", ErrBinaryNotFound, binary)
+}
+
+func extractTarGz(archive string, binary string, dest string) error {
+	f, err := os.Open(archive)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if strings.TrimPrefix(header.Name, "./") == binary && header.Typeflag == tar.TypeReg {
+			return writeBinary(tr, dest)
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrBinaryNotFound, binary)
+}
+
+// writeBinary writes the executable to a temporary file and renames it,
+// so the partially written binaries are never found in the cache.
+func writeBinary(r io.Reader, dest string) error {
+	f, err := ioutil.TempFile(filepath.Dir(dest), ".binary")
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return err
+	}
+	if err := f.Chmod(0755); err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), dest)
+}
+
+func isFile(filename string) bool {
+	info, err := os.Stat(filename)
+	return err == nil && !info.IsDir()
+}
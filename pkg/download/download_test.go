@@ -0,0 +1,285 @@
+package download
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mediabuyerbot/go-webdriver/pkg/discovery"
+)
+
+const testBinary = "#!/bin/sh\necho 'ChromeDriver 114.0.5735.90'\n"
+
+func zipArchive(t *testing.T, name string, content string) []byte {
+	buf := new(bytes.Buffer)
+	zw := zip.NewWriter(buf)
+	fw, err := zw.Create(name)
+	assert.Nil(t, err)
+	_, err = fw.Write([]byte(content))
+	assert.Nil(t, err)
+	assert.Nil(t, zw.Close())
+	return buf.Bytes()
+}
+
+func tarGzArchive(t *testing.T, name string, content string) []byte {
+	buf := new(bytes.Buffer)
+	gz := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gz)
+	assert.Nil(t, tw.WriteHeader(&tar.Header{
+		Name:     name,
+		Mode:     0755,
+		Size:     int64(len(content)),
+		Typeflag: tar.TypeReg,
+	}))
+	_, err := tw.Write([]byte(content))
+	assert.Nil(t, err)
+	assert.Nil(t, tw.Close())
+	assert.Nil(t, gz.Close())
+	return buf.Bytes()
+}
+
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// mirror is a stand-in for the driver download servers.
+type mirror struct {
+	*httptest.Server
+	files     map[string][]byte
+	downloads int32
+}
+
+func newMirror(files map[string][]byte) *mirror {
+	m := &mirror{files: files}
+	m.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, ok := m.files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		if ext := filepath.Ext(r.URL.Path); ext == zipExt || ext == ".gz" {
+			atomic.AddInt32(&m.downloads, 1)
+			// let the concurrent installations meet at the lock
+			time.Sleep(20 * time.Millisecond)
+		}
+		_, _ = w.Write(data)
+	}))
+	return m
+}
+
+func TestManager_InstallChromeDriver(t *testing.T) {
+	platform, err := chromePlatform()
+	if err != nil {
+		t.Skip(err)
+	}
+	archive := zipArchive(t, "chromedriver-"+platform+"/"+filepath.Base(discovery.ChromeDriver.CachePath("", "")), testBinary)
+	archivePath := "/114.0.5735.90/" + platform + "/chromedriver-" + platform + ".zip"
+	unverifiedPath := "/115.0.5790.102/" + platform + "/chromedriver-" + platform + ".zip"
+	srv := newMirror(map[string][]byte{
+		"/LATEST_RELEASE_114":     []byte("114.0.5735.90\n"),
+		archivePath:               archive,
+		archivePath + checksumExt: []byte(checksum(archive) + "  chromedriver.zip\n"),
+		"/LATEST_RELEASE_115":     []byte("115.0.5790.102\n"),
+		unverifiedPath:            archive,
+	})
+	defer srv.Close()
+
+	cacheDir, err := ioutil.TempDir("", "download")
+	assert.Nil(t, err)
+	defer os.RemoveAll(cacheDir)
+
+	ctx := context.Background()
+	manager := &Manager{CacheDir: cacheDir}
+	src := ChromeDriverSource{BaseURL: srv.URL}
+	browser := discovery.Version{114, 0, 5735, 198}
+
+	// concurrent installations download the release once
+	var wg sync.WaitGroup
+	paths := make([]string, 3)
+	for i := range paths {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			path, err := manager.Install(ctx, src, browser)
+			assert.Nil(t, err)
+			paths[i] = path
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&srv.downloads))
+
+	want := discovery.ChromeDriver.CachePath(cacheDir, "114.0.5735.90")
+	for _, path := range paths {
+		assert.Equal(t, want, path)
+	}
+	data, err := ioutil.ReadFile(want)
+	assert.Nil(t, err)
+	assert.Equal(t, testBinary, string(data))
+	info, err := os.Stat(want)
+	assert.Nil(t, err)
+	assert.NotZero(t, info.Mode()&0100)
+	_, err = os.Stat(filepath.Dir(want) + lockExt)
+	assert.True(t, os.IsNotExist(err))
+
+	// the checksum is not published, Chrome for Testing publishes no checksums
+	_, err = manager.Install(ctx, ChromeDriverSource{BaseURL: srv.URL, RequireChecksum: true}, discovery.Version{115})
+	assert.True(t, errors.Is(err, ErrNoChecksum))
+	path, err := manager.Install(ctx, src, discovery.Version{115})
+	assert.Nil(t, err)
+	assert.Equal(t, discovery.ChromeDriver.CachePath(cacheDir, "115.0.5790.102"), path)
+
+	// no release of the major version
+	_, err = manager.Install(ctx, src, discovery.Version{200})
+	assert.True(t, errors.Is(err, ErrNoRelease))
+}
+
+func TestManager_InstallGeckoDriver(t *testing.T) {
+	platform, ext, err := geckoPlatform()
+	if err != nil {
+		t.Skip(err)
+	}
+	if ext != tarGzExt {
+		t.Skip("zip archive")
+	}
+	archive := tarGzArchive(t, "geckodriver", testBinary)
+	srv := newMirror(map[string][]byte{
+		"/v0.35.0/geckodriver-v0.35.0-" + platform + tarGzExt: archive,
+	})
+	defer srv.Close()
+
+	cacheDir, err := ioutil.TempDir("", "download")
+	assert.Nil(t, err)
+	defer os.RemoveAll(cacheDir)
+
+	ctx := context.Background()
+	src := GeckoDriverSource{BaseURL: srv.URL}
+
+	// the checksum is not published
+	manager := &Manager{CacheDir: cacheDir}
+	_, err = manager.Install(ctx, src, discovery.Version{120, 0})
+	assert.True(t, errors.Is(err, ErrNoChecksum))
+
+	manager.AllowMissingChecksum = true
+	path, err := manager.Install(ctx, src, discovery.Version{120, 0})
+	assert.Nil(t, err)
+	assert.Equal(t, discovery.GeckoDriver.CachePath(cacheDir, "0.35.0"), path)
+
+	// too old browser
+	_, err = manager.Install(ctx, src, discovery.Version{52})
+	assert.True(t, errors.Is(err, ErrNoRelease))
+}
+
+func TestGeckoDriverSource_Digest(t *testing.T) {
+	platform, ext, err := geckoPlatform()
+	if err != nil {
+		t.Skip(err)
+	}
+	if ext != tarGzExt {
+		t.Skip("zip archive")
+	}
+	name := "geckodriver-v0.36.0-" + platform + tarGzExt
+	archive := tarGzArchive(t, "geckodriver", testBinary)
+	srv := newMirror(map[string][]byte{
+		"/v0.36.0/" + name:  archive,
+		"/api/tags/v0.36.0": []byte(`{"assets":[{"name":"` + name + `","digest":"sha256:` + checksum(archive) + `"}]}`),
+	})
+	defer srv.Close()
+
+	cacheDir, err := ioutil.TempDir("", "download")
+	assert.Nil(t, err)
+	defer os.RemoveAll(cacheDir)
+
+	ctx := context.Background()
+	src := GeckoDriverSource{BaseURL: srv.URL, APIURL: srv.URL + "/api"}
+	release, err := src.Resolve(ctx, http.DefaultClient, discovery.Version{130, 0})
+	assert.Nil(t, err)
+	assert.Equal(t, checksum(archive), release.Checksum)
+
+	// the checksum of the API is verified without the opt-out
+	manager := &Manager{CacheDir: cacheDir}
+	path, err := manager.Install(ctx, src, discovery.Version{130, 0})
+	assert.Nil(t, err)
+	assert.Equal(t, discovery.GeckoDriver.CachePath(cacheDir, "0.36.0"), path)
+
+	srv.files["/api/tags/v0.36.0"] = []byte(`{"assets":[{"name":"` + name + `","digest":"sha256:` + checksum(nil) + `"}]}`)
+	manager.CacheDir = filepath.Join(cacheDir, "other")
+	_, err = manager.Install(ctx, src, discovery.Version{130, 0})
+	assert.True(t, errors.Is(err, ErrChecksumMismatch))
+}
+
+func TestManager_InstallRelease(t *testing.T) {
+	archive := zipArchive(t, "driver", testBinary)
+	srv := newMirror(map[string][]byte{
+		"/driver.zip": archive,
+	})
+	defer srv.Close()
+
+	cacheDir, err := ioutil.TempDir("", "download")
+	assert.Nil(t, err)
+	defer os.RemoveAll(cacheDir)
+
+	ctx := context.Background()
+	manager := &Manager{CacheDir: cacheDir, StaleLockTimeout: time.Millisecond}
+	release := Release{
+		Driver:  discovery.Driver{Name: "driver"},
+		Version: "1.0.0",
+		URL:     srv.URL + "/driver.zip",
+		Binary:  "driver",
+	}
+
+	// checksum mismatch
+	release.Checksum = checksum([]byte("other"))
+	_, err = manager.InstallRelease(ctx, release)
+	assert.True(t, errors.Is(err, ErrChecksumMismatch))
+
+	// binary not found
+	release.Checksum = checksum(archive)
+	release.Binary = "missing"
+	_, err = manager.InstallRelease(ctx, release)
+	assert.True(t, errors.Is(err, ErrBinaryNotFound))
+
+	// archive not found
+	release.Binary = "driver"
+	release.URL = srv.URL + "/missing.zip"
+	_, err = manager.InstallRelease(ctx, release)
+	var statusErr *StatusError
+	assert.True(t, errors.As(err, &statusErr))
+	assert.Equal(t, http.StatusNotFound, statusErr.StatusCode)
+
+	// the stale lock is removed
+	release.URL = srv.URL + "/driver.zip"
+	lock := filepath.Join(cacheDir, "driver", "1.0.0"+lockExt)
+	assert.Nil(t, ioutil.WriteFile(lock, []byte("1"), 0644))
+	old := time.Now().Add(-time.Hour)
+	assert.Nil(t, os.Chtimes(lock, old, old))
+	path, err := manager.InstallRelease(ctx, release)
+	assert.Nil(t, err)
+	assert.Equal(t, filepath.Join(cacheDir, "driver", "1.0.0", filepath.Base(path)), path)
+
+	// the lock held by another process
+	manager.StaleLockTimeout = time.Hour
+	release.Version = "2.0.0"
+	lock = filepath.Join(cacheDir, "driver", "2.0.0"+lockExt)
+	assert.Nil(t, ioutil.WriteFile(lock, []byte("1"), 0644))
+	timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	_, err = manager.InstallRelease(timeoutCtx, release)
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
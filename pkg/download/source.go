@@ -0,0 +1,263 @@
+package download
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"runtime"
+	"strings"
+
+	"github.com/mediabuyerbot/go-webdriver/pkg/discovery"
+)
+
+const (
+	DefaultChromeDriverMetadataURL = "https://googlechromelabs.github.io/chrome-for-testing"
+	DefaultChromeDriverBaseURL     = "https://storage.googleapis.com/chrome-for-testing-public"
+	DefaultGeckoDriverBaseURL      = "https://github.com/mozilla/geckodriver/releases/download"
+	DefaultGeckoDriverAPIURL       = "https://api.github.com/repos/mozilla/geckodriver/releases"
+)
+
+var (
+	ErrUnsupportedPlatform = errors.New("download: unsupported platform")
+	ErrNoRelease           = errors.New("download: no compatible driver release")
+)
+
+// Release describes a driver release archive.
+type Release struct {
+	Driver  discovery.Driver
+	Version string
+
+	// URL is the URL of the .zip or .tar.gz archive.
+	URL string
+
+	// Binary is the path of the driver binary inside the archive.
+	Binary string
+
+	// Checksum is the hex encoded SHA-256 checksum of the archive. If empty the checksum
+	// is fetched from the <URL>.sha256 file, see Manager.AllowMissingChecksum.
+	Checksum string
+
+	// ChecksumOptional is set by the sources whose publisher has no checksums, e.g. Chrome
+	// for Testing. The archive is verified if the mirror publishes the <URL>.sha256 file,
+	// otherwise the release is installed without the verification.
+	ChecksumOptional bool
+}
+
+// Source resolves the driver release compatible with the browser version.
+type Source interface {
+	Resolve(ctx context.Context, client *http.Client, browser discovery.Version) (Release, error)
+}
+
+// ChromeDriverSource resolves the chromedriver releases published by the Chrome for Testing project.
+// The mirror must keep the same layout:
+//
+//	<MetadataURL>/LATEST_RELEASE_<major>
+//	<BaseURL>/<version>/<platform>/chromedriver-<platform>.zip
+//	<BaseURL>/<version>/<platform>/chromedriver-<platform>.zip.sha256 (optional)
+//
+// Chrome for Testing publishes no checksums, so the checksums of the releases are optional
+// unless RequireChecksum is set.
+type ChromeDriverSource struct {
+	// MetadataURL is the base URL of the LATEST_RELEASE_<major> files. The BaseURL is used if empty
+	// and the BaseURL is set, otherwise DefaultChromeDriverMetadataURL.
+	MetadataURL string
+
+	// BaseURL is the base URL of the archives. DefaultChromeDriverBaseURL is used if empty.
+	BaseURL string
+
+	// RequireChecksum rejects the releases without the <archive>.sha256 file,
+	// e.g. of the mirror publishing the checksums.
+	RequireChecksum bool
+}
+
+// Resolve returns the latest chromedriver release of the major version of the browser.
+func (s ChromeDriverSource) Resolve(ctx context.Context, client *http.Client, browser discovery.Version) (r Release, err error) {
+	platform, err := chromePlatform()
+	if err != nil {
+		return r, err
+	}
+	baseURL, metadataURL := s.BaseURL, s.MetadataURL
+	if len(metadataURL) == 0 {
+		metadataURL = baseURL
+	}
+	if len(baseURL) == 0 {
+		baseURL = DefaultChromeDriverBaseURL
+	}
+	if len(metadataURL) == 0 {
+		metadataURL = DefaultChromeDriverMetadataURL
+	}
+	url := fmt.Sprintf("%s/LATEST_RELEASE_%d", strings.TrimRight(metadataURL, "/"), browser.Major())
+	data, err := get(ctx, client, url)
+	if err != nil {
+		return r, fmt.Errorf("%w: chromedriver for Chrome %s: %v", ErrNoRelease, browser, err)
+	}
+	version := strings.TrimSpace(string(data))
+	if _, err := discovery.ParseVersion(version); err != nil {
+		return r, err
+	}
+	binary := "chromedriver-" + platform + "/chromedriver"
+	if runtime.GOOS == "windows" {
+		binary += ".exe"
+	}
+	return Release{
+		Driver:  discovery.ChromeDriver,
+		Version: version,
+		URL:     fmt.Sprintf("%s/%s/%s/chromedriver-%s.zip", strings.TrimRight(baseURL, "/"), version, platform, platform),
+		Binary:  binary,
+
+		ChecksumOptional: !s.RequireChecksum,
+	}, nil
+}
+
+// GeckoDriverSource resolves the geckodriver releases published on GitHub.
+// The mirror must keep the same layout:
+//
+//	<BaseURL>/v<version>/geckodriver-v<version>-<platform>.tar.gz
+type GeckoDriverSource struct {
+	// BaseURL is the base URL of the archives. DefaultGeckoDriverBaseURL is used if empty.
+	BaseURL string
+
+	// APIURL is the URL of the GitHub releases API publishing the SHA-256 digests of the archives:
+	//
+	//	<APIURL>/tags/v<version>
+	//
+	// DefaultGeckoDriverAPIURL is used if both BaseURL and APIURL are empty. The checksums of the
+	// mirrors without APIURL are fetched from the <archive>.sha256 files.
+	APIURL string
+}
+
+// Resolve returns the latest geckodriver release supporting the browser.
+func (s GeckoDriverSource) Resolve(ctx context.Context, client *http.Client, browser discovery.Version) (r Release, err error) {
+	platform, ext, err := geckoPlatform()
+	if err != nil {
+		return r, err
+	}
+	baseURL, apiURL := s.BaseURL, s.APIURL
+	if len(baseURL) == 0 {
+		baseURL = DefaultGeckoDriverBaseURL
+		if len(apiURL) == 0 {
+			apiURL = DefaultGeckoDriverAPIURL
+		}
+	}
+	for _, release := range discovery.GeckoDriverReleases {
+		if browser.Major() < release.MinFirefox {
+			continue
+		}
+		version := release.Version.String()
+		binary := "geckodriver"
+		if runtime.GOOS == "windows" {
+			binary += ".exe"
+		}
+		name := fmt.Sprintf("geckodriver-v%s-%s%s", version, platform, ext)
+		r = Release{
+			Driver:  discovery.GeckoDriver,
+			Version: version,
+			URL:     fmt.Sprintf("%s/v%s/%s", strings.TrimRight(baseURL, "/"), version, name),
+			Binary:  binary,
+		}
+		if len(apiURL) > 0 {
+			url := fmt.Sprintf("%s/tags/v%s", strings.TrimRight(apiURL, "/"), version)
+			if r.Checksum, err = assetDigest(ctx, client, url, name); err != nil {
+				return r, err
+			}
+		}
+		return r, nil
+	}
+	return r, fmt.Errorf("%w: geckodriver for Firefox %s", ErrNoRelease, browser)
+}
+
+// assetDigest returns the SHA-256 digest of the asset of the GitHub release,
+// the empty string if the digest is not published.
+func assetDigest(ctx context.Context, client *http.Client, url string, name string) (string, error) {
+	data, err := get(ctx, client, url)
+	if err != nil {
+		return "", err
+	}
+	var release struct {
+		Assets []struct {
+			Name   string `json:"name"`
+			Digest string `json:"digest"`
+		} `json:"assets"`
+	}
+	if err := json.Unmarshal(data, &release); err != nil {
+		return "", fmt.Errorf("download: GET %s: %v", url, err)
+	}
+	for _, asset := range release.Assets {
+		if asset.Name == name && strings.HasPrefix(asset.Digest, "sha256:") {
+			return strings.TrimPrefix(asset.Digest, "sha256:"), nil
+		}
+	}
+	return "", nil
+}
+
+func chromePlatform() (string, error) {
+	switch runtime.GOOS + "/" + runtime.GOARCH {
+	case "linux/amd64":
+		return "linux64", nil
+	case "darwin/amd64":
+		return "mac-x64", nil
+	case "darwin/arm64":
+		return "mac-arm64", nil
+	case "windows/386":
+		return "win32", nil
+	case "windows/amd64":
+		return "win64", nil
+	}
+	return "", fmt.Errorf("%w: chromedriver for %s/%s", ErrUnsupportedPlatform, runtime.GOOS, runtime.GOARCH)
+}
+
+func geckoPlatform() (platform string, ext string, err error) {
+	switch runtime.GOOS + "/" + runtime.GOARCH {
+	case "linux/amd64":
+		return "linux64", tarGzExt, nil
+	case "linux/arm64":
+		return "linux-aarch64", tarGzExt, nil
+	case "darwin/amd64":
+		return "macos", tarGzExt, nil
+	case "darwin/arm64":
+		return "macos-aarch64", tarGzExt, nil
+	case "windows/386":
+		return "win32", zipExt, nil
+	case "windows/amd64":
+		return "win64", zipExt, nil
+	}
+	return "", "", fmt.Errorf("%w: geckodriver for %s/%s", ErrUnsupportedPlatform, runtime.GOOS, runtime.GOARCH)
+}
+
+func get(ctx context.Context, client *http.Client, url string) ([]byte, error) {
+	resp, err := do(ctx, client, url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return ioutil.ReadAll(resp.Body)
+}
+
+func do(ctx context.Context, client *http.Client, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, &StatusError{URL: url, StatusCode: resp.StatusCode}
+	}
+	return resp, nil
+}
+
+// StatusError is returned when the mirror responds with an unexpected HTTP status.
+type StatusError struct {
+	URL        string
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("download: GET %s: %d %s", e.URL, e.StatusCode, http.StatusText(e.StatusCode))
+}
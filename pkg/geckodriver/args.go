@@ -108,6 +108,8 @@ func (f *flags) Build() []string {
 
 type arguments struct {
 	store map[string]string
+	host  string
+	port  int
 }
 
 func newArguments() *arguments {
@@ -159,6 +161,7 @@ func (a *arguments) SetMarionettePort(port int) error {
 
 func (a *arguments) SetHost(host string) {
 	a.store[argsHost] = fmt.Sprintf(argsHost, host)
+	a.host = host
 }
 
 func (a *arguments) SetPort(port int) error {
@@ -166,6 +169,7 @@ func (a *arguments) SetPort(port int) error {
 		return err
 	}
 	a.store[argsPort] = fmt.Sprintf(argsPort, port)
+	a.port = port
 	return nil
 }
 
@@ -1,6 +1,11 @@
 package geckodriver
 
-import "io"
+import (
+	"io"
+	"time"
+
+	"github.com/mediabuyerbot/go-webdriver/pkg/proctree"
+)
 
 type Option func(cd *Process)
 
@@ -78,6 +83,8 @@ func WithRunHook(hook Hook) Option {
 	}
 }
 
+// WithStopHook sets the hook called with the pid of the driver when it exits
+// and with the pid of every browser process killed on shutdown.
 func WithStopHook(hook Hook) Option {
 	return func(p *Process) {
 		p.stopHook = hook
@@ -95,3 +102,36 @@ func WithStdout(writer io.Writer) Option {
 		p.stdout = writer
 	}
 }
+
+// WithEnv adds the environment variables in the form "key=value" to the environment of the driver.
+func WithEnv(env ...string) Option {
+	return func(p *Process) {
+		p.env = append(p.env, env...)
+	}
+}
+
+// WithStartTimeout sets the time the driver has to report the readiness after the start.
+// readiness.DefaultTimeout is used if not set.
+func WithStartTimeout(timeout time.Duration) Option {
+	return func(p *Process) {
+		p.startTimeout = timeout
+	}
+}
+
+// WithStopTimeout sets the time the driver has to exit after each shutdown signal
+// before the next one is sent. DefaultStopTimeout is used if not set.
+func WithStopTimeout(timeout time.Duration) Option {
+	return func(p *Process) {
+		if timeout > 0 {
+			p.stopTimeout = timeout
+		}
+	}
+}
+
+// WithJanitor tracks the driver with the janitor, so the driver and its browsers
+// are killed by the janitor if the current process dies without stopping them.
+func WithJanitor(janitor *proctree.Janitor) Option {
+	return func(p *Process) {
+		p.janitor = janitor
+	}
+}
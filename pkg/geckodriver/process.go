@@ -2,12 +2,27 @@ package geckodriver
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
+	"net"
 	"os"
 	"os/exec"
+	"strconv"
 	"sync"
 	"syscall"
 	"time"
+
+	"github.com/mediabuyerbot/go-webdriver/pkg/proctree"
+	"github.com/mediabuyerbot/go-webdriver/pkg/readiness"
+)
+
+const (
+	defaultHost = "127.0.0.1"
+	// DefaultStopTimeout is the time the driver has to exit after each shutdown signal.
+	DefaultStopTimeout = 5 * time.Second
+
+	defaultPort = 4444
 )
 
 type Hook func(pid int)
@@ -21,10 +36,17 @@ type Process struct {
 
 	stdout io.Writer
 	stderr io.Writer
+	env    []string
 
 	runHook  Hook
 	stopHook Hook
 
+	startTimeout time.Duration
+	stopTimeout  time.Duration
+	exited       chan struct{}
+
+	janitor *proctree.Janitor
+
 	lock sync.RWMutex
 }
 
@@ -39,6 +61,8 @@ func New(bin string, opts ...Option) (*Process, error) {
 		args:  newArguments(),
 		flags: newFlags(),
 		bin:   driverPath,
+
+		stopTimeout: DefaultStopTimeout,
 	}
 
 	for _, opt := range opts {
@@ -48,49 +72,127 @@ func New(bin string, opts ...Option) (*Process, error) {
 	return cd, nil
 }
 
+// Run starts the driver and waits for it to exit. The run hook is called as soon as
+// the driver reports the readiness through the /status endpoint. Returns an error if
+// the port is already in use, or if the driver exits or is not ready before the start timeout.
+// The driver runs in its own process group and is shut down with Stop when the context is done.
 func (d *Process) Run(ctx context.Context) error {
 	d.lock.Lock()
-	d.cmd = exec.CommandContext(ctx, d.bin, d.buildArgs()...)
+	host, port := d.addr()
+	if err := readiness.CheckPort(host, port); err != nil {
+		d.lock.Unlock()
+		return err
+	}
+	d.cmd = exec.Command(d.bin, d.buildArgs()...)
+	proctree.SetGroup(d.cmd)
+	cmd := d.cmd
 
 	d.setWriters()
+	if len(d.env) > 0 {
+		d.cmd.Env = append(os.Environ(), d.env...)
+	}
 
 	if err := d.cmd.Start(); err != nil {
+		d.cmd = nil
 		d.lock.Unlock()
 		return err
 	}
+	exited := make(chan struct{})
+	d.exited = exited
 	d.lock.Unlock()
 
-	if d.runHook != nil {
-		go func() {
-			time.Sleep(time.Second)
-			d.runHook(d.cmd.Process.Pid)
-		}()
+	pid := cmd.Process.Pid
+	if d.janitor != nil {
+		_ = d.janitor.Track(pid)
 	}
-
-	err := d.cmd.Wait()
-	if err != nil && d.isSignaled() {
+	probed := make(chan error, 1)
+	go func() {
+		probe := readiness.Probe{
+			Addr:    "http://" + net.JoinHostPort(host, strconv.Itoa(port)),
+			Timeout: d.startTimeout,
+			Exited:  exited,
+		}
+		if err := probe.Wait(ctx); err != nil {
+			if !errors.Is(err, readiness.ErrProcessExited) && ctx.Err() == nil {
+				d.shutdown(context.Background(), pid, exited)
+			}
+			probed <- err
+			return
+		}
+		probed <- nil
+		if d.runHook != nil {
+			d.runHook(pid)
+		}
+	}()
+	go func() {
+		select {
+		case <-ctx.Done():
+			d.shutdown(context.Background(), pid, exited)
+		case <-exited:
+		}
+	}()
+
+	err := cmd.Wait()
+	close(exited)
+	if probeErr := <-probed; probeErr != nil {
+		if err != nil && errors.Is(probeErr, readiness.ErrProcessExited) {
+			probeErr = fmt.Errorf("%w: %v", probeErr, err)
+		}
+		err = probeErr
+	} else if err != nil && d.isSignaled() {
 		err = nil
 	}
 
+	if d.janitor != nil {
+		_ = d.janitor.Untrack(pid)
+	}
 	if d.stopHook != nil {
-		d.stopHook(d.cmd.Process.Pid)
+		d.stopHook(pid)
 	}
 
 	d.lock.Lock()
 	d.cmd = nil
+	d.exited = nil
 	d.lock.Unlock()
 
 	return err
 }
 
-func (d *Process) Stop(_ context.Context) error {
-	d.lock.Lock()
-	defer d.lock.Unlock()
+// Stop shuts the driver down. The driver gets the interrupt signal first and,
+// if it does not exit within the stop timeout, the SIGTERM and SIGKILL signals
+// are sent to its process group. The browser processes left behind are killed
+// and reported through the stop hook. The context bounds the graceful part of
+// the shutdown, the driver is killed as soon as the context is done.
+func (d *Process) Stop(ctx context.Context) error {
+	d.lock.RLock()
+	cmd, exited := d.cmd, d.exited
+	d.lock.RUnlock()
 
-	if d.cmd == nil {
+	if cmd == nil {
 		return nil
 	}
-	return d.cmd.Process.Signal(os.Interrupt)
+	d.shutdown(ctx, cmd.Process.Pid, exited)
+	return nil
+}
+
+func (d *Process) shutdown(ctx context.Context, pid int, exited <-chan struct{}) {
+	for _, child := range proctree.Shutdown(ctx, pid, exited, d.stopTimeout) {
+		if d.stopHook != nil {
+			d.stopHook(child)
+		}
+	}
+}
+
+// addr returns the host and the port the driver listens on.
+func (d *Process) addr() (host string, port int) {
+	host, port = d.args.host, d.args.port
+	if len(host) == 0 {
+		host = defaultHost
+	}
+	if port == 0 {
+		port = defaultPort
+	}
+	return host, port
 }
 
 func (d *Process) setWriters() {
@@ -0,0 +1,238 @@
+package proctree
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/mitchellh/go-ps"
+)
+
+const pidFileExt = ".json"
+
+// Record describes a driver process tracked by the janitor.
+type Record struct {
+	// PID is the pid of the driver. The driver is the leader of the process group
+	// the browser processes started by it belong to.
+	PID        int    `json:"pid"`
+	Executable string `json:"executable"`
+
+	// PGID is the process group of the driver and the browsers, zero if not supported.
+	PGID int `json:"pgid"`
+	// StartTime is the start time of the driver in the clock ticks since the boot, zero if unknown.
+	// The process group of the driver that is gone is killed only if its processes started later.
+	StartTime uint64 `json:"startTime"`
+
+	// Owner is the pid of the process that started the driver.
+	Owner           int    `json:"owner"`
+	OwnerExecutable string `json:"ownerExecutable"`
+
+	Started time.Time `json:"started"`
+
+	file string
+}
+
+// Janitor keeps a PID file for every driver started by this library and kills the drivers
+// and the browsers whose owner process is gone, e.g. after a crash.
+//
+//	janitor, err := proctree.NewJanitor(proctree.DefaultDir())
+//	if err != nil {
+//		return err
+//	}
+//	// at startup
+//	killed, err := janitor.Sweep()
+//	// on an interval
+//	go janitor.Run(ctx, time.Minute)
+type Janitor struct {
+	dir string
+}
+
+// DefaultDir returns the default directory of the PID files.
+func DefaultDir() string {
+	return filepath.Join(os.TempDir(), "go-webdriver")
+}
+
+// NewJanitor returns a new instance of Janitor keeping the PID files in the directory.
+func NewJanitor(dir string) (*Janitor, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &Janitor{dir: dir}, nil
+}
+
+// Dir returns the directory of the PID files.
+func (j *Janitor) Dir() string {
+	return j.dir
+}
+
+// Track writes the PID file of the driver owned by the current process.
+func (j *Janitor) Track(pid int) error {
+	owner := os.Getpid()
+	record := Record{
+		PID:             pid,
+		Executable:      executable(pid),
+		PGID:            processGroup(pid),
+		StartTime:       startTime(pid),
+		Owner:           owner,
+		OwnerExecutable: executable(owner),
+		Started:         time.Now(),
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(j.dir, ".pid")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), j.pidFile(pid))
+}
+
+// Untrack removes the PID file of the driver.
+func (j *Janitor) Untrack(pid int) error {
+	err := os.Remove(j.pidFile(pid))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// Records returns the records of all tracked drivers.
+func (j *Janitor) Records() ([]Record, error) {
+	files, err := ioutil.ReadDir(j.dir)
+	if err != nil {
+		return nil, err
+	}
+	records := make([]Record, 0, len(files))
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), pidFileExt) {
+			continue
+		}
+		data, err := ioutil.ReadFile(filepath.Join(j.dir, file.Name()))
+		if err != nil {
+			continue
+		}
+		var record Record
+		if err := json.Unmarshal(data, &record); err != nil || record.PID <= 0 {
+			// the file is broken, nothing can be killed
+			_ = os.Remove(filepath.Join(j.dir, file.Name()))
+			continue
+		}
+		record.file = filepath.Join(j.dir, file.Name())
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// Sweep kills the drivers and the browsers whose owner process is gone
+// and returns the records of the killed drivers. If the driver is gone too, e.g. after
+// a crash, the browsers left in its process group are killed. The records are removed.
+func (j *Janitor) Sweep() ([]Record, error) {
+	records, err := j.Records()
+	if err != nil {
+		return nil, err
+	}
+	killed := make([]Record, 0)
+	for _, record := range records {
+		if isAlive(record.Owner, record.OwnerExecutable) {
+			continue
+		}
+		var signaled bool
+		switch {
+		case isAlive(record.PID, record.Executable) && startedAt(record.PID, record.StartTime):
+			children, _ := Descendants(record.PID)
+			_ = SignalGroup(record.PID, syscall.SIGKILL)
+			_ = Kill(record.PID)
+			for _, child := range children {
+				_ = Kill(child)
+			}
+			signaled = true
+		case isOrphanedGroup(record):
+			signaled = SignalGroup(record.PGID, syscall.SIGKILL) == nil
+		}
+		if err := os.Remove(record.file); err != nil && !os.IsNotExist(err) {
+			return killed, err
+		}
+		if signaled {
+			killed = append(killed, record)
+		}
+	}
+	return killed, nil
+}
+
+// Run sweeps the orphaned processes on the interval until the context is done.
+func (j *Janitor) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		_, _ = j.Sweep()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (j *Janitor) pidFile(pid int) string {
+	return filepath.Join(j.dir, strconv.Itoa(pid)+pidFileExt)
+}
+
+// isOrphanedGroup returns true if the driver of the record is gone and its process group still
+// has the processes started by the driver. The pgid is not reused while the group has processes,
+// but the pid of the driver can lead another group now, so the group is orphaned only if the pid
+// is free and all processes of the group started after the driver.
+func isOrphanedGroup(record Record) bool {
+	if record.PGID <= 0 || record.StartTime == 0 || IsRunning(record.PGID) {
+		return false
+	}
+	members, err := groupMembers(record.PGID)
+	if err != nil || len(members) == 0 {
+		return false
+	}
+	for _, pid := range members {
+		if startTime(pid) < record.StartTime {
+			return false
+		}
+	}
+	return true
+}
+
+// startedAt returns true if the process started at the time or the time is unknown,
+// so the reused pids of the same executable are not taken for the tracked processes.
+func startedAt(pid int, ticks uint64) bool {
+	return ticks == 0 || startTime(pid) == ticks
+}
+
+// isAlive returns true if the process exists and runs the executable,
+// so the reused pids are not taken for the tracked processes.
+func isAlive(pid int, exe string) bool {
+	proc, err := ps.FindProcess(pid)
+	if err != nil || proc == nil {
+		return false
+	}
+	return len(exe) == 0 || proc.Executable() == exe
+}
+
+func executable(pid int) string {
+	proc, err := ps.FindProcess(pid)
+	if err != nil || proc == nil {
+		return ""
+	}
+	return proc.Executable()
+}
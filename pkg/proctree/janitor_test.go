@@ -0,0 +1,202 @@
+//go:build !windows
+// +build !windows
+
+package proctree
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJanitor(t *testing.T) {
+	dir, err := ioutil.TempDir("", "janitor")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	janitor, err := NewJanitor(dir)
+	assert.Nil(t, err)
+	assert.Equal(t, dir, janitor.Dir())
+
+	// the driver owned by the current process is not killed
+	owned := exec.Command("sleep", "30")
+	SetGroup(owned)
+	assert.Nil(t, owned.Start())
+	defer func() {
+		_ = owned.Process.Kill()
+		_ = owned.Wait()
+	}()
+	assert.Nil(t, janitor.Track(owned.Process.Pid))
+
+	// the driver whose owner is gone is killed
+	orphan := exec.Command("sleep", "30")
+	SetGroup(orphan)
+	assert.Nil(t, orphan.Start())
+	exited := make(chan struct{})
+	go func() {
+		_ = orphan.Wait()
+		close(exited)
+	}()
+	owner := exec.Command("true")
+	assert.Nil(t, owner.Run())
+	data, err := json.Marshal(Record{
+		PID:             orphan.Process.Pid,
+		Executable:      "sleep",
+		Owner:           owner.Process.Pid,
+		OwnerExecutable: "true",
+	})
+	assert.Nil(t, err)
+	assert.Nil(t, ioutil.WriteFile(janitor.pidFile(orphan.Process.Pid), data, 0644))
+
+	// the broken files are removed
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "2.json"), []byte(`{`), 0644))
+
+	records, err := janitor.Records()
+	assert.Nil(t, err)
+	assert.Len(t, records, 2)
+
+	killed, err := janitor.Sweep()
+	assert.Nil(t, err)
+	assert.Len(t, killed, 1)
+	assert.Equal(t, orphan.Process.Pid, killed[0].PID)
+	<-exited
+
+	records, err = janitor.Records()
+	assert.Nil(t, err)
+	assert.Len(t, records, 1)
+	assert.Equal(t, owned.Process.Pid, records[0].PID)
+	assert.Equal(t, os.Getpid(), records[0].Owner)
+	assert.True(t, IsRunning(owned.Process.Pid))
+
+	assert.Nil(t, janitor.Untrack(owned.Process.Pid))
+	assert.Nil(t, janitor.Untrack(owned.Process.Pid))
+	records, err = janitor.Records()
+	assert.Nil(t, err)
+	assert.Empty(t, records)
+}
+
+func TestJanitor_ReusedPID(t *testing.T) {
+	dir, err := ioutil.TempDir("", "janitor")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	janitor, err := NewJanitor(dir)
+	assert.Nil(t, err)
+
+	// the pid of the driver that is gone belongs to the leader of another process group
+	other := exec.Command("sleep", "30")
+	SetGroup(other)
+	assert.Nil(t, other.Start())
+	exited := make(chan struct{})
+	go func() {
+		_ = other.Wait()
+		close(exited)
+	}()
+	defer func() {
+		_ = other.Process.Kill()
+		<-exited
+	}()
+	owner := exec.Command("true")
+	assert.Nil(t, owner.Run())
+	data, err := json.Marshal(Record{
+		PID:             other.Process.Pid,
+		Executable:      "chromedriver",
+		Owner:           owner.Process.Pid,
+		OwnerExecutable: "true",
+	})
+	assert.Nil(t, err)
+	assert.Nil(t, ioutil.WriteFile(janitor.pidFile(other.Process.Pid), data, 0644))
+
+	killed, err := janitor.Sweep()
+	assert.Nil(t, err)
+	assert.Empty(t, killed)
+	select {
+	case <-exited:
+		t.Fatal("the process is signalled")
+	case <-time.After(200 * time.Millisecond):
+	}
+	records, err := janitor.Records()
+	assert.Nil(t, err)
+	assert.Empty(t, records)
+}
+
+func TestJanitor_CrashedDriver(t *testing.T) {
+	dir, err := ioutil.TempDir("", "janitor")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	janitor, err := NewJanitor(dir)
+	assert.Nil(t, err)
+
+	// the driver leads the process group of the browser
+	driver := exec.Command("sleep", "30")
+	SetGroup(driver)
+	assert.Nil(t, driver.Start())
+	record := Record{
+		PID:        driver.Process.Pid,
+		Executable: "sleep",
+		PGID:       processGroup(driver.Process.Pid),
+		StartTime:  startTime(driver.Process.Pid),
+	}
+	if record.StartTime == 0 {
+		_ = driver.Process.Kill()
+		_ = driver.Wait()
+		t.Skip("the start time of the processes is unknown")
+	}
+	browser := exec.Command("sleep", "30")
+	browser.SysProcAttr = &syscall.SysProcAttr{Setpgid: true, Pgid: driver.Process.Pid}
+	assert.Nil(t, browser.Start())
+	exited := make(chan struct{})
+	go func() {
+		_ = browser.Wait()
+		close(exited)
+	}()
+	defer func() {
+		_ = browser.Process.Kill()
+		<-exited
+	}()
+
+	// the driver crashes, the browser is left running
+	assert.Nil(t, driver.Process.Kill())
+	_ = driver.Wait()
+	owner := exec.Command("true")
+	assert.Nil(t, owner.Run())
+	record.Owner, record.OwnerExecutable = owner.Process.Pid, "true"
+
+	// the group is not signalled if the browser started before the driver
+	stale := record
+	stale.StartTime = startTime(browser.Process.Pid) + 1
+	data, err := json.Marshal(stale)
+	assert.Nil(t, err)
+	assert.Nil(t, ioutil.WriteFile(janitor.pidFile(stale.PID), data, 0644))
+	killed, err := janitor.Sweep()
+	assert.Nil(t, err)
+	assert.Empty(t, killed)
+	select {
+	case <-exited:
+		t.Fatal("the process is signalled")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	// the browser left in the group of the driver is killed
+	data, err = json.Marshal(record)
+	assert.Nil(t, err)
+	assert.Nil(t, ioutil.WriteFile(janitor.pidFile(record.PID), data, 0644))
+	killed, err = janitor.Sweep()
+	assert.Nil(t, err)
+	assert.Len(t, killed, 1)
+	select {
+	case <-exited:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the process is not killed")
+	}
+	assert.Equal(t, syscall.SIGKILL, signaled(browser))
+	records, err := janitor.Records()
+	assert.Nil(t, err)
+	assert.Empty(t, records)
+}
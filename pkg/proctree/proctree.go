@@ -0,0 +1,42 @@
+// Package proctree manages the process trees of the drivers and the browsers started by them.
+package proctree
+
+import (
+	"github.com/mitchellh/go-ps"
+)
+
+// Descendants returns the pids of all descendants of the process, the children first.
+func Descendants(pid int) ([]int, error) {
+	procs, err := ps.Processes()
+	if err != nil {
+		return nil, err
+	}
+	children := make(map[int][]int)
+	for _, proc := range procs {
+		children[proc.PPid()] = append(children[proc.PPid()], proc.Pid())
+	}
+	var (
+		pids  []int
+		queue = []int{pid}
+		seen  = map[int]bool{pid: true}
+	)
+	for len(queue) > 0 {
+		parent := queue[0]
+		queue = queue[1:]
+		for _, child := range children[parent] {
+			if seen[child] {
+				continue
+			}
+			seen[child] = true
+			pids = append(pids, child)
+			queue = append(queue, child)
+		}
+	}
+	return pids, nil
+}
+
+// IsRunning returns true if the process exists.
+func IsRunning(pid int) bool {
+	proc, err := ps.FindProcess(pid)
+	return err == nil && proc != nil
+}
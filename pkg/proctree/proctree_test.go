@@ -0,0 +1,33 @@
+//go:build !windows
+// +build !windows
+
+package proctree
+
+import (
+	"os/exec"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignalGroup(t *testing.T) {
+	cmd := exec.Command("sh", "-c", "sleep 30 & sleep 30 & wait")
+	SetGroup(cmd)
+	assert.Nil(t, cmd.Start())
+	assert.True(t, IsRunning(cmd.Process.Pid))
+
+	var children []int
+	for i := 0; i < 100 && len(children) < 2; i++ {
+		time.Sleep(10 * time.Millisecond)
+		pids, err := Descendants(cmd.Process.Pid)
+		assert.Nil(t, err)
+		children = pids
+	}
+	assert.Len(t, children, 2)
+
+	assert.Nil(t, SignalGroup(cmd.Process.Pid, syscall.SIGKILL))
+	_ = cmd.Wait()
+	assert.False(t, IsRunning(cmd.Process.Pid))
+}
@@ -0,0 +1,59 @@
+//go:build !windows
+// +build !windows
+
+package proctree
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+
+	"github.com/mitchellh/go-ps"
+)
+
+// SetGroup makes the command run in its own process group, so the whole
+// tree of the processes started by the command can be signaled at once.
+func SetGroup(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setpgid = true
+}
+
+// SignalGroup sends the signal to the process group led by the process.
+func SignalGroup(pid int, sig os.Signal) error {
+	s, ok := sig.(syscall.Signal)
+	if !ok {
+		s = syscall.SIGINT
+	}
+	return syscall.Kill(-pid, s)
+}
+
+// Kill kills the process.
+func Kill(pid int) error {
+	return syscall.Kill(pid, syscall.SIGKILL)
+}
+
+// processGroup returns the process group of the process, zero if the process is gone.
+func processGroup(pid int) int {
+	pgid, err := syscall.Getpgid(pid)
+	if err != nil {
+		return 0
+	}
+	return pgid
+}
+
+// groupMembers returns the pids of the processes of the process group.
+func groupMembers(pgid int) ([]int, error) {
+	procs, err := ps.Processes()
+	if err != nil {
+		return nil, err
+	}
+	var pids []int
+	for _, proc := range procs {
+		if processGroup(proc.Pid()) == pgid {
+			pids = append(pids, proc.Pid())
+		}
+	}
+	return pids, nil
+}
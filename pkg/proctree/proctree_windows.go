@@ -0,0 +1,44 @@
+//go:build windows
+// +build windows
+
+package proctree
+
+import (
+	"os"
+	"os/exec"
+)
+
+// SetGroup is a no-op on windows.
+func SetGroup(_ *exec.Cmd) {}
+
+// SignalGroup sends the signal to the process. The process groups are not supported on windows.
+func SignalGroup(pid int, sig os.Signal) error {
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	if sig == os.Interrupt {
+		// interrupts are not implemented on windows
+		return proc.Kill()
+	}
+	return proc.Signal(sig)
+}
+
+// Kill kills the process.
+func Kill(pid int) error {
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	return proc.Kill()
+}
+
+// processGroup always returns zero, the process groups are not supported on windows.
+func processGroup(_ int) int {
+	return 0
+}
+
+// groupMembers always returns no pids, the process groups are not supported on windows.
+func groupMembers(_ int) ([]int, error) {
+	return nil, nil
+}
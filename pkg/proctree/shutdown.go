@@ -0,0 +1,66 @@
+package proctree
+
+import (
+	"context"
+	"os"
+	"syscall"
+	"time"
+)
+
+// Shutdown stops the process running in its own process group, see SetGroup. The exited channel
+// is closed when the process exits. The process group gets the interrupt signal first and, if the
+// process does not exit within the timeout, the SIGTERM and SIGKILL signals. The context bounds
+// the graceful part of the shutdown, the group is killed as soon as the context is done.
+// The descendants of the process left running, e.g. the browsers started in their own groups,
+// are killed afterwards. Returns the pids of the killed descendants.
+func Shutdown(ctx context.Context, pid int, exited <-chan struct{}, timeout time.Duration) (killed []int) {
+	children, _ := Descendants(pid)
+
+	var done bool
+	for _, sig := range []os.Signal{os.Interrupt, syscall.SIGTERM, syscall.SIGKILL} {
+		if done = isClosed(exited); done {
+			break
+		}
+		_ = SignalGroup(pid, sig)
+		if done = waitExit(ctx, exited, timeout); done {
+			break
+		}
+	}
+	if !done {
+		// SIGKILL can not be ignored, the process exits shortly
+		<-exited
+	}
+
+	for _, child := range children {
+		if !IsRunning(child) {
+			continue
+		}
+		if err := Kill(child); err != nil {
+			continue
+		}
+		killed = append(killed, child)
+	}
+	return killed
+}
+
+func waitExit(ctx context.Context, exited <-chan struct{}, timeout time.Duration) bool {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-exited:
+		return true
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return false
+	}
+}
+
+func isClosed(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,90 @@
+//go:build !windows
+// +build !windows
+
+package proctree
+
+import (
+	"context"
+	"os/exec"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// startGroup starts the shell script in its own process group. The returned channel
+// is closed when the script exits.
+func startGroup(t *testing.T, script string) (*exec.Cmd, <-chan struct{}) {
+	cmd := exec.Command("sh", "-c", script)
+	SetGroup(cmd)
+	assert.Nil(t, cmd.Start())
+	exited := make(chan struct{})
+	go func() {
+		_ = cmd.Wait()
+		close(exited)
+	}()
+	return cmd, exited
+}
+
+// waitDescendants waits until the process has n descendants.
+func waitDescendants(t *testing.T, pid int, n int) []int {
+	var pids []int
+	for i := 0; i < 100 && len(pids) < n; i++ {
+		time.Sleep(10 * time.Millisecond)
+		pids, _ = Descendants(pid)
+	}
+	assert.Len(t, pids, n)
+	return pids
+}
+
+func signaled(cmd *exec.Cmd) syscall.Signal {
+	status, _ := cmd.ProcessState.Sys().(syscall.WaitStatus)
+	if !status.Signaled() {
+		return 0
+	}
+	return status.Signal()
+}
+
+func TestShutdown(t *testing.T) {
+	ctx := context.Background()
+
+	// exits on the interrupt signal
+	cmd, exited := startGroup(t, "exec sleep 30")
+	killed := Shutdown(ctx, cmd.Process.Pid, exited, time.Second)
+	assert.Empty(t, killed)
+	assert.Equal(t, syscall.SIGINT, signaled(cmd))
+
+	// exits on the SIGTERM signal
+	cmd, exited = startGroup(t, `trap "" INT; exec sleep 30`)
+	killed = Shutdown(ctx, cmd.Process.Pid, exited, 50*time.Millisecond)
+	assert.Empty(t, killed)
+	assert.Equal(t, syscall.SIGTERM, signaled(cmd))
+
+	// ignores the signals and is killed
+	cmd, exited = startGroup(t, `trap "" INT TERM; exec sleep 30`)
+	killed = Shutdown(ctx, cmd.Process.Pid, exited, 50*time.Millisecond)
+	assert.Empty(t, killed)
+	assert.Equal(t, syscall.SIGKILL, signaled(cmd))
+
+	// is killed as soon as the context is done
+	cmd, exited = startGroup(t, `trap "" INT TERM; exec sleep 30`)
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	start := time.Now()
+	Shutdown(cancelCtx, cmd.Process.Pid, exited, time.Minute)
+	assert.True(t, time.Since(start) < 10*time.Second)
+	assert.Equal(t, syscall.SIGKILL, signaled(cmd))
+
+	// the descendant running in another process group is killed
+	cmd, exited = startGroup(t, "set -m; sleep 30 & wait")
+	children := waitDescendants(t, cmd.Process.Pid, 1)
+	killed = Shutdown(ctx, cmd.Process.Pid, exited, 50*time.Millisecond)
+	assert.Equal(t, children, killed)
+
+	// the exited process is not signaled
+	cmd, exited = startGroup(t, "exit 0")
+	<-exited
+	assert.Empty(t, Shutdown(ctx, cmd.Process.Pid, exited, time.Second))
+	assert.Equal(t, syscall.Signal(0), signaled(cmd))
+}
@@ -0,0 +1,32 @@
+package proctree
+
+import (
+	"io/ioutil"
+	"strconv"
+	"strings"
+)
+
+// startTime returns the start time of the process in the clock ticks since the boot,
+// zero if the process is gone.
+func startTime(pid int) uint64 {
+	data, err := ioutil.ReadFile("/proc/" + strconv.Itoa(pid) + "/stat")
+	if err != nil {
+		return 0
+	}
+	// the command name in the parentheses can contain the spaces
+	stat := string(data)
+	i := strings.LastIndexByte(stat, ')')
+	if i < 0 {
+		return 0
+	}
+	// the fields after the command name start with the state, the start time is the 22nd field
+	fields := strings.Fields(stat[i+1:])
+	if len(fields) < 20 {
+		return 0
+	}
+	ticks, err := strconv.ParseUint(fields[19], 10, 64)
+	if err != nil {
+		return 0
+	}
+	return ticks
+}
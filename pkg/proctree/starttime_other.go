@@ -0,0 +1,9 @@
+//go:build !linux
+// +build !linux
+
+package proctree
+
+// startTime always returns zero, the start time of the processes is read on linux only.
+func startTime(_ int) uint64 {
+	return 0
+}
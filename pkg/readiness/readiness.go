@@ -0,0 +1,133 @@
+// Package readiness probes the WebDriver remote ends started as the local processes.
+package readiness
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/mediabuyerbot/go-webdriver/pkg/w3cproto"
+)
+
+const (
+	DefaultTimeout  = 20 * time.Second
+	DefaultInterval = 50 * time.Millisecond
+)
+
+var (
+	ErrNotReady      = errors.New("readiness: driver is not ready")
+	ErrProcessExited = errors.New("readiness: driver process exited")
+	ErrPortInUse     = errors.New("readiness: port is already in use")
+)
+
+// Probe polls the /status endpoint of the remote end until it reports the readiness.
+type Probe struct {
+	// Addr is the base URL of the remote end, e.g. http://127.0.0.1:9515.
+	Addr string
+
+	// Timeout is the time after which the probe gives up. DefaultTimeout is used if zero.
+	Timeout time.Duration
+
+	// Interval is the time between the status requests. DefaultInterval is used if zero.
+	Interval time.Duration
+
+	// Exited is closed when the driver process exits. The probe stops as soon as it is closed.
+	Exited <-chan struct{}
+
+	// Client is the HTTP client used for the status requests. http.DefaultClient is used if nil.
+	Client *http.Client
+}
+
+// Wait polls the remote end until the status is ready. Returns an error that wraps ErrNotReady
+// if the deadline passes, ErrProcessExited if the process exited or the context error.
+func (p Probe) Wait(ctx context.Context) error {
+	timeout := p.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	interval := p.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var lastErr error
+	for {
+		select {
+		case <-p.Exited:
+			return ErrProcessExited
+		default:
+		}
+
+		ready, err := p.status(ctx, interval)
+		if err == nil && ready {
+			return nil
+		}
+		lastErr = err
+
+		select {
+		case <-p.Exited:
+			return ErrProcessExited
+		case <-ctx.Done():
+			if ctx.Err() != context.DeadlineExceeded {
+				return ctx.Err()
+			}
+			if lastErr != nil {
+				return fmt.Errorf("%w after %v: %v", ErrNotReady, timeout, lastErr)
+			}
+			return fmt.Errorf("%w after %v", ErrNotReady, timeout)
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p Probe) status(ctx context.Context, interval time.Duration) (bool, error) {
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	// a single request must not outlive the next tick, otherwise a hung
+	// listener would block the probe until the deadline
+	reqTimeout := interval
+	if reqTimeout < time.Second {
+		reqTimeout = time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, reqTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.Addr+"/status", nil)
+	if err != nil {
+		return false, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		Value w3cproto.Status `json:"value"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return false, err
+	}
+	return body.Value.Ready, nil
+}
+
+// CheckPort returns ErrPortInUse if the port on the host cannot be listened on.
+func CheckPort(host string, port int) error {
+	l, err := net.Listen("tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return fmt.Errorf("%w: %d", ErrPortInUse, port)
+	}
+	return l.Close()
+}
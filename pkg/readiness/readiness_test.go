@@ -0,0 +1,68 @@
+package readiness
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProbe_Wait(t *testing.T) {
+	ctx := context.Background()
+
+	// returns success after the driver becomes ready
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/status", r.URL.Path)
+		if atomic.AddInt32(&calls, 1) < 3 {
+			_, _ = w.Write([]byte(`{"value":{"ready":false,"message":"starting"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"value":{"ready":true,"message":""}}`))
+	}))
+	defer srv.Close()
+
+	err := Probe{Addr: srv.URL, Interval: time.Millisecond}.Wait(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+
+	// returns timeout
+	atomic.StoreInt32(&calls, -1000)
+	err = Probe{Addr: srv.URL, Interval: time.Millisecond, Timeout: 20 * time.Millisecond}.Wait(ctx)
+	assert.True(t, errors.Is(err, ErrNotReady))
+
+	// returns process exited
+	exited := make(chan struct{})
+	close(exited)
+	err = Probe{Addr: srv.URL, Exited: exited}.Wait(ctx)
+	assert.Equal(t, ErrProcessExited, err)
+
+	// returns context error
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	err = Probe{Addr: "http://127.0.0.1:1", Interval: time.Millisecond}.Wait(cancelCtx)
+	assert.Equal(t, context.Canceled, err)
+
+	// returns timeout with the last connection error
+	err = Probe{Addr: "http://127.0.0.1:1", Interval: time.Millisecond, Timeout: 20 * time.Millisecond}.Wait(ctx)
+	assert.True(t, errors.Is(err, ErrNotReady))
+	assert.Contains(t, err.Error(), "127.0.0.1:1")
+}
+
+func TestCheckPort(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	port := l.Addr().(*net.TCPAddr).Port
+
+	err = CheckPort("127.0.0.1", port)
+	assert.True(t, errors.Is(err, ErrPortInUse))
+
+	assert.Nil(t, l.Close())
+	assert.Nil(t, CheckPort("127.0.0.1", port))
+}
@@ -0,0 +1,362 @@
+package w3cproto
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Actions represents a low-level interface for providing virtualised device input to the web browser.
+// Every action of an input source occupies one tick. Actions of different input sources
+// that share the same tick index are dispatched together.
+type Actions interface {
+
+	// Perform performs a sequence of actions. Input sources with fewer ticks
+	// are padded with pauses in the request, so all sources end on the same tick.
+	// The input sources are not modified.
+	Perform(ctx context.Context, sources ...InputSource) error
+
+	// Release releases all the keys and pointer buttons that are currently depressed.
+	Release(ctx context.Context) error
+}
+
+const (
+	NoneInput    InputSourceType = "none"
+	KeyInput     InputSourceType = "key"
+	PointerInput InputSourceType = "pointer"
+	WheelInput   InputSourceType = "wheel"
+)
+
+const (
+	MousePointer PointerType = "mouse"
+	PenPointer   PointerType = "pen"
+	TouchPointer PointerType = "touch"
+)
+
+const (
+	LeftButton   MouseButton = 0
+	MiddleButton MouseButton = 1
+	RightButton  MouseButton = 2
+)
+
+type (
+	InputSourceType string
+	PointerType     string
+	MouseButton     int
+)
+
+func (t InputSourceType) String() string {
+	return string(t)
+}
+
+func (pt PointerType) String() string {
+	return string(pt)
+}
+
+var (
+	// ViewportOrigin the coordinates are relative to the top left corner of the viewport.
+	ViewportOrigin = Origin{kind: "viewport"}
+
+	// PointerOrigin the coordinates are relative to the current pointer position.
+	PointerOrigin = Origin{kind: "pointer"}
+)
+
+// Origin represents the starting point of the pointer move and wheel scroll actions.
+type Origin struct {
+	kind      string
+	elementID string
+}
+
+// ElementOrigin the coordinates are relative to the center of the element.
+func ElementOrigin(elem WebElement) Origin {
+	return Origin{elementID: elem.ID()}
+}
+
+func (o Origin) value() interface{} {
+	if len(o.elementID) > 0 {
+		return map[string]string{WebElementIdentifier: o.elementID}
+	}
+	if len(o.kind) == 0 {
+		return ViewportOrigin.kind
+	}
+	return o.kind
+}
+
+// InputSource represents a virtual device providing input events.
+type InputSource interface {
+
+	// ID returns the unique identifier of the input source.
+	ID() string
+
+	// Type returns the type of the input source.
+	Type() InputSourceType
+
+	// Ticks returns the number of the queued actions.
+	Ticks() int
+
+	pause(d time.Duration)
+	params() Params
+}
+
+// AlignTicks pads the input sources with pauses, so the next action of each source
+// starts on the same tick.
+func AlignTicks(sources ...InputSource) {
+	var max int
+	for _, source := range sources {
+		if source.Ticks() > max {
+			max = source.Ticks()
+		}
+	}
+	for _, source := range sources {
+		for source.Ticks() < max {
+			source.pause(0)
+		}
+	}
+}
+
+type inputSource struct {
+	id      string
+	typ     InputSourceType
+	actions []Params
+}
+
+func (s *inputSource) ID() string {
+	return s.id
+}
+
+func (s *inputSource) Type() InputSourceType {
+	return s.typ
+}
+
+func (s *inputSource) Ticks() int {
+	return len(s.actions)
+}
+
+func (s *inputSource) pause(d time.Duration) {
+	s.actions = append(s.actions, pauseAction(d))
+}
+
+func pauseAction(d time.Duration) Params {
+	return Params{
+		"type":     "pause",
+		"duration": milliseconds(d),
+	}
+}
+
+// alignedParams returns the params of the input sources padded with pauses like AlignTicks does.
+// The sources are not modified, so they can be performed again.
+func alignedParams(sources ...InputSource) []Params {
+	var max int
+	for _, source := range sources {
+		if source.Ticks() > max {
+			max = source.Ticks()
+		}
+	}
+	seq := make([]Params, len(sources))
+	for i, source := range sources {
+		p := source.params()
+		actions, _ := p["actions"].([]Params)
+		padded := make([]Params, len(actions), max)
+		copy(padded, actions)
+		for len(padded) < max {
+			padded = append(padded, pauseAction(0))
+		}
+		p["actions"] = padded
+		seq[i] = p
+	}
+	return seq
+}
+
+func (s *inputSource) params() Params {
+	actions := s.actions
+	if actions == nil {
+		actions = []Params{}
+	}
+	return Params{
+		"type":    s.typ,
+		"id":      s.id,
+		"actions": actions,
+	}
+}
+
+// NoneSource represents an input source that only supports the pause action.
+type NoneSource struct {
+	inputSource
+}
+
+// NewNoneSource creates a new instance of NoneSource.
+func NewNoneSource(id string) *NoneSource {
+	return &NoneSource{inputSource{id: id, typ: NoneInput}}
+}
+
+// Pause waits for the given duration.
+func (s *NoneSource) Pause(d time.Duration) *NoneSource {
+	s.pause(d)
+	return s
+}
+
+// KeySource represents a keyboard input source.
+type KeySource struct {
+	inputSource
+}
+
+// NewKeySource creates a new instance of KeySource.
+func NewKeySource(id string) *KeySource {
+	return &KeySource{inputSource{id: id, typ: KeyInput}}
+}
+
+// Pause waits for the given duration.
+func (s *KeySource) Pause(d time.Duration) *KeySource {
+	s.pause(d)
+	return s
+}
+
+// KeyDown presses the key.
+func (s *KeySource) KeyDown(k Key) *KeySource {
+	s.actions = append(s.actions, Params{"type": "keyDown", "value": string(k)})
+	return s
+}
+
+// KeyUp releases the key.
+func (s *KeySource) KeyUp(k Key) *KeySource {
+	s.actions = append(s.actions, Params{"type": "keyUp", "value": string(k)})
+	return s
+}
+
+// PointerSource represents a pointer input source such as a mouse, a pen or a touch.
+type PointerSource struct {
+	inputSource
+	pointerType PointerType
+}
+
+// NewPointerSource creates a new instance of PointerSource.
+func NewPointerSource(id string, pt PointerType) *PointerSource {
+	if len(pt) == 0 {
+		pt = MousePointer
+	}
+	return &PointerSource{
+		inputSource: inputSource{id: id, typ: PointerInput},
+		pointerType: pt,
+	}
+}
+
+// PointerType returns the type of the pointer.
+func (s *PointerSource) PointerType() PointerType {
+	return s.pointerType
+}
+
+// Pause waits for the given duration.
+func (s *PointerSource) Pause(d time.Duration) *PointerSource {
+	s.pause(d)
+	return s
+}
+
+// PointerDown presses the pointer button.
+func (s *PointerSource) PointerDown(button MouseButton) *PointerSource {
+	s.actions = append(s.actions, Params{"type": "pointerDown", "button": button})
+	return s
+}
+
+// PointerUp releases the pointer button.
+func (s *PointerSource) PointerUp(button MouseButton) *PointerSource {
+	s.actions = append(s.actions, Params{"type": "pointerUp", "button": button})
+	return s
+}
+
+// PointerMove moves the pointer to the x, y offset relative to the origin over the given duration.
+func (s *PointerSource) PointerMove(d time.Duration, origin Origin, x, y int) *PointerSource {
+	s.actions = append(s.actions, Params{
+		"type":     "pointerMove",
+		"duration": milliseconds(d),
+		"origin":   origin.value(),
+		"x":        x,
+		"y":        y,
+	})
+	return s
+}
+
+// PointerCancel cancels the pointer action.
+func (s *PointerSource) PointerCancel() *PointerSource {
+	s.actions = append(s.actions, Params{"type": "pointerCancel"})
+	return s
+}
+
+func (s *PointerSource) params() Params {
+	p := s.inputSource.params()
+	p["parameters"] = Params{"pointerType": s.pointerType}
+	return p
+}
+
+// WheelSource represents a scroll wheel input source.
+type WheelSource struct {
+	inputSource
+}
+
+// NewWheelSource creates a new instance of WheelSource.
+func NewWheelSource(id string) *WheelSource {
+	return &WheelSource{inputSource{id: id, typ: WheelInput}}
+}
+
+// Pause waits for the given duration.
+func (s *WheelSource) Pause(d time.Duration) *WheelSource {
+	s.pause(d)
+	return s
+}
+
+// Scroll scrolls by deltaX, deltaY from the x, y offset relative to the origin over the given duration.
+// The pointer origin is not supported by the wheel input source.
+func (s *WheelSource) Scroll(d time.Duration, origin Origin, x, y, deltaX, deltaY int) *WheelSource {
+	s.actions = append(s.actions, Params{
+		"type":     "scroll",
+		"duration": milliseconds(d),
+		"origin":   origin.value(),
+		"x":        x,
+		"y":        y,
+		"deltaX":   deltaX,
+		"deltaY":   deltaY,
+	})
+	return s
+}
+
+type actions struct {
+	id      string
+	request Doer
+}
+
+// NewActions creates a new instance of Actions.
+func NewActions(doer Doer, sessID string) Actions {
+	return &actions{
+		id:      sessID,
+		request: doer,
+	}
+}
+
+func (a *actions) Perform(ctx context.Context, sources ...InputSource) error {
+	if len(sources) == 0 {
+		return ErrInvalidArgument
+	}
+	seq := alignedParams(sources...)
+	resp, err := a.request.Do(ctx, http.MethodPost, "/session/"+a.id+"/actions", Params{"actions": seq})
+	if err != nil {
+		return err
+	}
+	if resp.Success() {
+		return nil
+	}
+	return ErrInvalidResponse
+}
+
+func (a *actions) Release(ctx context.Context) error {
+	resp, err := a.request.Do(ctx, http.MethodDelete, "/session/"+a.id+"/actions", nil)
+	if err != nil {
+		return err
+	}
+	if resp.Success() {
+		return nil
+	}
+	return ErrInvalidResponse
+}
+
+func milliseconds(d time.Duration) int64 {
+	return int64(d / time.Millisecond)
+}
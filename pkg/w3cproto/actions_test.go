@@ -0,0 +1,132 @@
+package w3cproto
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/golang/mock/gomock"
+)
+
+var actionsErr = &Error{Code: "error"}
+
+func newActions(t *testing.T, sessID string) (Actions, *MockDoer, func()) {
+	ctrl := gomock.NewController(t)
+	cli := NewMockDoer(ctrl)
+	cx := NewActions(cli, sessID)
+	return cx, cli, func() {
+		ctrl.Finish()
+	}
+}
+
+func TestActions_Perform(t *testing.T) {
+	actions, cli, done := newActions(t, "123")
+	defer done()
+
+	ctx := context.TODO()
+	elem := webElement{wid: testWebElementID, sid: "123"}
+
+	keyboard := NewKeySource("keyboard").KeyDown(ShiftKey)
+	mouse := NewPointerSource("mouse", "")
+	AlignTicks(keyboard, mouse)
+	mouse.PointerMove(100*time.Millisecond, ElementOrigin(elem), 1, 2).
+		PointerDown(LeftButton).
+		PointerUp(LeftButton)
+	keyboard.KeyUp(ShiftKey)
+	wheel := NewWheelSource("wheel").Scroll(0, ViewportOrigin, 0, 0, 10, 20)
+
+	// returns success, the sources are performed twice
+	cli.EXPECT().Do(ctx, http.MethodPost, "/session/123/actions", gomock.Any()).Times(2).Return(
+		&Response{
+			Value: []byte(`null`),
+		}, nil).Do(func(_ context.Context, _ string, _ string, p Params) {
+		have, err := json.Marshal(p)
+		assert.Nil(t, err)
+		want := `{"actions":[` +
+			`{"actions":[{"type":"keyDown","value":"\ue008"},{"type":"keyUp","value":"\ue008"},{"duration":0,"type":"pause"},{"duration":0,"type":"pause"}],"id":"keyboard","type":"key"},` +
+			`{"actions":[{"duration":0,"type":"pause"},{"duration":100,"origin":{"element-6066-11e4-a52e-4f735466cecf":"` + testWebElementID + `"},"type":"pointerMove","x":1,"y":2},{"button":0,"type":"pointerDown"},{"button":0,"type":"pointerUp"}],"id":"mouse","parameters":{"pointerType":"mouse"},"type":"pointer"},` +
+			`{"actions":[{"deltaX":10,"deltaY":20,"duration":0,"origin":"viewport","type":"scroll","x":0,"y":0},{"duration":0,"type":"pause"},{"duration":0,"type":"pause"},{"duration":0,"type":"pause"}],"id":"wheel","type":"wheel"}` +
+			`]}`
+		assert.JSONEq(t, want, string(have))
+	})
+	err := actions.Perform(ctx, keyboard, mouse, wheel)
+	assert.Nil(t, err)
+	// the sources of the caller are not padded
+	assert.Equal(t, 2, keyboard.Ticks())
+	assert.Equal(t, 4, mouse.Ticks())
+	assert.Equal(t, 1, wheel.Ticks())
+	err = actions.Perform(ctx, keyboard, mouse, wheel)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, keyboard.Ticks())
+
+	// returns error
+	none := NewNoneSource("none").Pause(time.Second)
+	cli.EXPECT().Do(ctx, http.MethodPost, "/session/123/actions", gomock.Any()).Times(1).Return(nil, actionsErr)
+	err = actions.Perform(ctx, none)
+	assert.Equal(t, actionsErr, err)
+
+	// returns error (invalid response)
+	cli.EXPECT().Do(ctx, http.MethodPost, "/session/123/actions", gomock.Any()).Times(1).Return(
+		&Response{
+			Value: []byte(`{}`),
+		}, nil)
+	err = actions.Perform(ctx, none)
+	assert.Equal(t, ErrInvalidResponse, err)
+
+	// returns error (without input sources)
+	err = actions.Perform(ctx)
+	assert.Equal(t, ErrInvalidArgument, err)
+}
+
+func TestActions_Release(t *testing.T) {
+	actions, cli, done := newActions(t, "123")
+	defer done()
+
+	ctx := context.TODO()
+
+	// returns success
+	cli.EXPECT().Do(ctx, http.MethodDelete, "/session/123/actions", nil).Times(1).Return(
+		&Response{
+			Value: []byte(`null`),
+		}, nil)
+	err := actions.Release(ctx)
+	assert.Nil(t, err)
+
+	// returns error
+	cli.EXPECT().Do(ctx, http.MethodDelete, "/session/123/actions", nil).Times(1).Return(nil, actionsErr)
+	err = actions.Release(ctx)
+	assert.Equal(t, actionsErr, err)
+
+	// returns error (invalid response)
+	cli.EXPECT().Do(ctx, http.MethodDelete, "/session/123/actions", nil).Times(1).Return(
+		&Response{
+			Value: []byte(`{}`),
+		}, nil)
+	err = actions.Release(ctx)
+	assert.Equal(t, ErrInvalidResponse, err)
+}
+
+func TestInputSource(t *testing.T) {
+	pen := NewPointerSource("pen", PenPointer).Pause(time.Second).PointerCancel()
+	assert.Equal(t, "pen", pen.ID())
+	assert.Equal(t, PointerInput, pen.Type())
+	assert.Equal(t, PenPointer, pen.PointerType())
+	assert.Equal(t, 2, pen.Ticks())
+
+	keyboard := NewKeySource("keyboard").Pause(time.Second)
+	assert.Equal(t, KeyInput, keyboard.Type())
+	assert.Equal(t, Params{"type": "pause", "duration": int64(1000)}, keyboard.actions[0])
+
+	wheel := NewWheelSource("wheel").Pause(0).Scroll(0, PointerOrigin, 0, 0, 0, 0)
+	assert.Equal(t, WheelInput, wheel.Type())
+	assert.Equal(t, "pointer", wheel.actions[1]["origin"])
+
+	none := NewNoneSource("none")
+	assert.Equal(t, NoneInput, none.Type())
+	assert.Equal(t, []Params{}, none.params()["actions"])
+	assert.Equal(t, "viewport", Origin{}.value())
+}
@@ -4,7 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"strings"
+	"time"
 )
 
 const (
@@ -143,16 +146,25 @@ func (c Cookie) SetExpiry(v int64) Cookie {
 	return c
 }
 
+// Expiry returns the expiry in seconds since Unix Epoch, 0 if the cookie has no expiry.
+// The expiry of the cookie decoded from JSON is the float64 number.
 func (c Cookie) Expiry() int64 {
 	v, ok := c[CookieExpiryKey]
 	if !ok {
 		return 0
 	}
-	n, ok := v.(int64)
-	if !ok {
-		return 0
-	}
-	return n
+	switch n := v.(type) {
+	case int64:
+		return n
+	case int:
+		return int64(n)
+	case float64:
+		return int64(n)
+	case json.Number:
+		i, _ := n.Int64()
+		return i
+	}
+	return 0
 }
 
 func (c Cookie) SetHttpOnly(v bool) Cookie {
@@ -189,6 +201,72 @@ func (c Cookie) SameSite() string {
 	return n
 }
 
+// HTTPCookie converts the cookie to the *http.Cookie. The expiry is converted to Expires,
+// the cookie without the expiry is the session cookie.
+func (c Cookie) HTTPCookie() *http.Cookie {
+	hc := &http.Cookie{
+		Name:     c.Name(),
+		Path:     c.Path(),
+		Domain:   c.Domain(),
+		Secure:   c.Secure(),
+		HttpOnly: c.HttpOnly(),
+	}
+	switch v := c.Value().(type) {
+	case nil:
+	case string:
+		hc.Value = v
+	default:
+		hc.Value = fmt.Sprint(v)
+	}
+	if _, ok := c[CookieExpiryKey]; ok {
+		hc.Expires = time.Unix(c.Expiry(), 0).UTC()
+	}
+	switch strings.ToLower(c.SameSite()) {
+	case "lax":
+		hc.SameSite = http.SameSiteLaxMode
+	case "strict":
+		hc.SameSite = http.SameSiteStrictMode
+	case "none":
+		hc.SameSite = http.SameSiteNoneMode
+	}
+	return hc
+}
+
+// CookieFromHTTP converts the *http.Cookie to the cookie. MaxAge takes precedence over Expires,
+// the cookie with the negative MaxAge gets the expiry in the past, so the browser deletes it.
+func CookieFromHTTP(hc *http.Cookie) Cookie {
+	c := MakeCookie().SetName(hc.Name).SetValue(hc.Value)
+	if len(hc.Path) > 0 {
+		c.SetPath(hc.Path)
+	}
+	if len(hc.Domain) > 0 {
+		c.SetDomain(hc.Domain)
+	}
+	if hc.Secure {
+		c.SetSecure(true)
+	}
+	if hc.HttpOnly {
+		c.SetHttpOnly(true)
+	}
+	switch {
+	case hc.MaxAge > 0:
+		c.SetExpiry(time.Now().Add(time.Duration(hc.MaxAge) * time.Second).Unix())
+	case hc.MaxAge < 0:
+		c.SetExpiry(time.Now().Add(-time.Hour).Unix())
+	case !hc.Expires.IsZero():
+		c.SetExpiry(hc.Expires.Unix())
+	}
+	switch hc.SameSite {
+	case http.SameSiteLaxMode:
+		c.SetSameSite("Lax")
+	case http.SameSiteStrictMode:
+		c.SetSameSite("Strict")
+	case http.SameSiteNoneMode:
+		c.SetSameSite("None")
+	}
+	return c
+}
+
 func (c Cookie) Get(key string) interface{} {
 	v, ok := c[key]
 	if !ok {
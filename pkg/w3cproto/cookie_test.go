@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -201,3 +202,40 @@ func TestCookies_GetNamedCookie(t *testing.T) {
 	assert.Error(t, err)
 	assert.Empty(t, haveCookie.Value())
 }
+
+func TestCookie_HTTPCookie(t *testing.T) {
+	var cookie Cookie
+	err := json.Unmarshal([]byte(`{"name":"token","value":"abc","path":"/app","domain":".example.com",
+		"secure":true,"httpOnly":true,"expiry":1893456000,"sameSite":"Strict"}`), &cookie)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1893456000), cookie.Expiry())
+
+	hc := cookie.HTTPCookie()
+	assert.Equal(t, &http.Cookie{
+		Name:     "token",
+		Value:    "abc",
+		Path:     "/app",
+		Domain:   ".example.com",
+		Expires:  time.Unix(1893456000, 0).UTC(),
+		Secure:   true,
+		HttpOnly: true,
+		SameSite: http.SameSiteStrictMode,
+	}, hc)
+	assert.Equal(t, cookie.SetExpiry(1893456000), CookieFromHTTP(hc))
+
+	// the session cookie has no expiry
+	hc = MakeCookie().SetName("sid").SetValue(42).HTTPCookie()
+	assert.Equal(t, "42", hc.Value)
+	assert.True(t, hc.Expires.IsZero())
+	assert.Equal(t, http.SameSite(0), hc.SameSite)
+	assert.Equal(t, Cookie{"name": "sid", "value": "42"}, CookieFromHTTP(hc))
+}
+
+func TestCookieFromHTTP_MaxAge(t *testing.T) {
+	now := time.Now().Unix()
+	cookie := CookieFromHTTP(&http.Cookie{Name: "a", MaxAge: 60, Expires: time.Unix(1, 0), SameSite: http.SameSiteLaxMode})
+	assert.InDelta(t, now+60, cookie.Expiry(), 1)
+	assert.Equal(t, "Lax", cookie.SameSite())
+	cookie = CookieFromHTTP(&http.Cookie{Name: "a", MaxAge: -1})
+	assert.Less(t, cookie.Expiry(), now)
+}
@@ -52,7 +52,7 @@ func (d *document) GetPageSource(ctx context.Context) (source string, err error)
 	if err != nil {
 		return source, err
 	}
-	return string(resp.Value), nil
+	return stringValue(resp.Value), nil
 }
 
 func (d *document) ExecuteScript(ctx context.Context, script string, args []interface{}) ([]byte, error) {
@@ -41,6 +41,15 @@ func TestDocument_GetPageSource(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, wantSource, haveSource)
 
+	// returns success (JSON string)
+	cli.EXPECT().Do(ctx, http.MethodGet, "/session/123/source", nil).Times(1).Return(
+		&Response{
+			Value: []byte(`"<html>\n<body class=\"page\"></body></html>"`),
+		}, nil)
+	haveSource, err = doc.GetPageSource(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, "<html>\n<body class=\"page\"></body></html>", haveSource)
+
 	// returns error
 	cli.EXPECT().Do(ctx, http.MethodGet, "/session/123/source", nil).Times(1).Return(nil, documentErr)
 	haveSource, err = doc.GetPageSource(ctx)
@@ -42,6 +42,9 @@ type WebElement interface {
 	// IsEnabled returns true if the element is enabled.
 	IsEnabled(ctx context.Context) (bool, error)
 
+	// IsDisplayed returns true if the element is visible to the user.
+	IsDisplayed(ctx context.Context) (bool, error)
+
 	// GetAttribute returns the named attribute of the element.
 	GetAttribute(ctx context.Context, name string) (string, error)
 
@@ -51,8 +54,15 @@ type WebElement interface {
 	// GetProperty returns the value of the specified property of the element.
 	GetProperty(ctx context.Context, name string) (string, error)
 
+	// GetPropertyValue returns the JSON encoded value of the specified property of the element,
+	// e.g. the boolean "checked" or the number "selectedIndex" property.
+	GetPropertyValue(ctx context.Context, name string) (json.RawMessage, error)
+
 	// GetCSSValue returns the value of the specified CSS property of the element.
 	GetCSSValue(ctx context.Context, name string) (string, error)
+
+	// ShadowRoot returns the shadow root of the element.
+	ShadowRoot(ctx context.Context) (ShadowRoot, error)
 }
 
 // Point is a 2D point.
@@ -85,7 +95,7 @@ func (w webElement) Click(ctx context.Context) error {
 // SendKeys types into the element.
 func (w webElement) SendKeys(ctx context.Context, keys ...Key) error {
 	if len(keys) == 0 {
-		return ErrInvalidArguments
+		return ErrInvalidArgument
 	}
 	str := make([]string, len(keys))
 	for i, k := range keys {
@@ -119,7 +129,7 @@ func (w webElement) Clear(ctx context.Context) error {
 // FindOne finds a child element.
 func (w webElement) FindOne(ctx context.Context, by FindElementStrategy, value string) (WebElement, error) {
 	if len(value) == 0 {
-		return nil, ErrInvalidArguments
+		return nil, ErrInvalidArgument
 	}
 	p := Params{
 		"using": by,
@@ -147,7 +157,7 @@ func (w webElement) FindOne(ctx context.Context, by FindElementStrategy, value s
 // Find finds multiple children elements.
 func (w webElement) Find(ctx context.Context, by FindElementStrategy, value string) ([]WebElement, error) {
 	if len(value) == 0 {
-		return nil, ErrInvalidArguments
+		return nil, ErrInvalidArgument
 	}
 	p := Params{
 		"using": by,
@@ -185,7 +195,7 @@ func (w webElement) TagName(ctx context.Context) (s string, err error) {
 	if resp.Success() {
 		return s, err
 	}
-	s = string(resp.Value)
+	s = stringValue(resp.Value)
 	return s, nil
 }
 
@@ -228,10 +238,24 @@ func (w webElement) IsEnabled(ctx context.Context) (flag bool, err error) {
 	return flag, nil
 }
 
+// IsDisplayed returns true if the element is visible to the user. The command is not
+// the part of the W3C specification, see the "Element Displayedness" appendix. The drivers,
+// e.g. chromedriver and geckodriver, compute it with the Selenium "isShown" atom.
+func (w webElement) IsDisplayed(ctx context.Context) (flag bool, err error) {
+	resp, err := w.request.Do(ctx, http.MethodGet, "/session/"+w.sid+"/element/"+w.wid+"/displayed", nil)
+	if err != nil {
+		return flag, err
+	}
+	if err := json.Unmarshal(resp.Value, &flag); err != nil {
+		return flag, err
+	}
+	return flag, nil
+}
+
 // GetAttribute returns the named attribute of the element.
 func (w webElement) GetAttribute(ctx context.Context, name string) (s string, err error) {
 	if len(name) == 0 {
-		return s, ErrInvalidArguments
+		return s, ErrInvalidArgument
 	}
 	resp, err := w.request.Do(ctx, http.MethodGet, "/session/"+w.sid+"/element/"+w.wid+"/attribute/"+name, nil)
 	if err != nil {
@@ -249,7 +273,7 @@ func (w webElement) GetAttribute(ctx context.Context, name string) (s string, er
 // GetProperty returns the value of the specified property of the element.
 func (w webElement) GetProperty(ctx context.Context, name string) (s string, err error) {
 	if len(name) == 0 {
-		return s, ErrInvalidArguments
+		return s, ErrInvalidArgument
 	}
 	resp, err := w.request.Do(ctx, http.MethodGet, "/session/"+w.sid+"/element/"+w.wid+"/property/"+name, nil)
 	if err != nil {
@@ -264,10 +288,22 @@ func (w webElement) GetProperty(ctx context.Context, name string) (s string, err
 	return s, nil
 }
 
+// GetPropertyValue returns the JSON encoded value of the specified property of the element.
+func (w webElement) GetPropertyValue(ctx context.Context, name string) (json.RawMessage, error) {
+	if len(name) == 0 {
+		return nil, ErrInvalidArgument
+	}
+	resp, err := w.request.Do(ctx, http.MethodGet, "/session/"+w.sid+"/element/"+w.wid+"/property/"+name, nil)
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(resp.Value), nil
+}
+
 // GetCSSValue returns the value of the specified CSS property of the element.
 func (w webElement) GetCSSValue(ctx context.Context, name string) (s string, err error) {
 	if len(name) == 0 {
-		return s, ErrInvalidArguments
+		return s, ErrInvalidArgument
 	}
 	resp, err := w.request.Do(ctx, http.MethodGet, "/session/"+w.sid+"/element/"+w.wid+"/css/"+name, nil)
 	if err != nil {
@@ -290,3 +326,24 @@ func (w webElement) Rect(ctx context.Context) (r Rect, err error) {
 	}
 	return r, nil
 }
+
+// ShadowRoot returns the shadow root of the element.
+func (w webElement) ShadowRoot(ctx context.Context) (ShadowRoot, error) {
+	resp, err := w.request.Do(ctx, http.MethodGet, "/session/"+w.sid+"/element/"+w.wid+"/shadow", nil)
+	if err != nil {
+		return nil, err
+	}
+	var sr shadowResp
+	if err := json.Unmarshal(resp.Value, &sr); err != nil {
+		return nil, err
+	}
+	id, ok := sr.ID()
+	if !ok {
+		return nil, ErrNoSuchShadowRoot
+	}
+	return shadowRoot{
+		rid:     id,
+		sid:     w.sid,
+		request: w.request,
+	}, nil
+}
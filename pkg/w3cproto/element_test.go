@@ -104,7 +104,7 @@ func TestWebElement_Find(t *testing.T) {
 	// returns error invalid arguments
 	elems, err = webElem.Find(ctx, ByCSSSelector, "")
 	assert.Error(t, err)
-	assert.Equal(t, ErrInvalidArguments, err)
+	assert.Equal(t, ErrInvalidArgument, err)
 	assert.Nil(t, elems)
 
 	// returns error unmarshal JSON
@@ -153,7 +153,7 @@ func TestWebElement_FindOne(t *testing.T) {
 	// returns error invalid arguments
 	elem, err = webElem.FindOne(ctx, ByCSSSelector, "")
 	assert.Error(t, err)
-	assert.Equal(t, ErrInvalidArguments, err)
+	assert.Equal(t, ErrInvalidArgument, err)
 	assert.Nil(t, elem)
 
 	// returns error unmarshal JSON
@@ -223,7 +223,7 @@ func TestWebElement_GetCSSValue(t *testing.T) {
 	// returns error invalid args
 	val, err = webElem.GetCSSValue(ctx, "")
 	assert.Error(t, err)
-	assert.Equal(t, ErrInvalidArguments, err)
+	assert.Equal(t, ErrInvalidArgument, err)
 	assert.Empty(t, val)
 
 	// returns error
@@ -270,7 +270,7 @@ func TestWebElement_GetProperty(t *testing.T) {
 	// returns error invalid args
 	val, err = webElem.GetProperty(ctx, "")
 	assert.Error(t, err)
-	assert.Equal(t, ErrInvalidArguments, err)
+	assert.Equal(t, ErrInvalidArgument, err)
 	assert.Empty(t, val)
 
 	// returns error
@@ -291,6 +291,32 @@ func TestWebElement_GetProperty(t *testing.T) {
 	assert.Empty(t, val)
 }
 
+func TestWebElement_GetPropertyValue(t *testing.T) {
+	webElem, cli, done := newWebElement(t, "123")
+	defer done()
+
+	ctx := context.Background()
+
+	// returns success
+	cli.EXPECT().Do(ctx, http.MethodGet, "/session/123/element/"+testWebElementID+"/property/checked", nil).Times(1).Return(
+		&Response{
+			Value: []byte(`true`),
+		}, nil)
+	val, err := webElem.GetPropertyValue(ctx, "checked")
+	assert.Nil(t, err)
+	assert.Equal(t, `true`, string(val))
+
+	// returns error invalid args
+	_, err = webElem.GetPropertyValue(ctx, "")
+	assert.Equal(t, ErrInvalidArgument, err)
+
+	// returns error
+	cli.EXPECT().Do(ctx, http.MethodGet, "/session/123/element/"+testWebElementID+"/property/checked", nil).Times(1).Return(
+		nil, elementWebErr)
+	_, err = webElem.GetPropertyValue(ctx, "checked")
+	assert.Error(t, err)
+}
+
 func TestWebElement_Text(t *testing.T) {
 	webElem, cli, done := newWebElement(t, "123")
 	defer done()
@@ -364,6 +390,38 @@ func TestWebElement_IsEnabled(t *testing.T) {
 	assert.False(t, flag)
 }
 
+func TestWebElement_IsDisplayed(t *testing.T) {
+	webElem, cli, done := newWebElement(t, "123")
+	defer done()
+
+	ctx := context.Background()
+
+	// returns success
+	cli.EXPECT().Do(ctx, http.MethodGet, "/session/123/element/"+testWebElementID+"/displayed", nil).Times(1).Return(
+		&Response{
+			Value: []byte(`true`),
+		}, nil)
+	flag, err := webElem.IsDisplayed(ctx)
+	assert.Nil(t, err)
+	assert.True(t, flag)
+
+	// returns error
+	cli.EXPECT().Do(ctx, http.MethodGet, "/session/123/element/"+testWebElementID+"/displayed", nil).Times(1).Return(
+		nil, elementWebErr)
+	flag, err = webElem.IsDisplayed(ctx)
+	assert.Error(t, err)
+	assert.False(t, flag)
+
+	// returns error JSON unmarshal
+	cli.EXPECT().Do(ctx, http.MethodGet, "/session/123/element/"+testWebElementID+"/displayed", nil).Times(1).Return(
+		&Response{
+			Value: []byte(`"`),
+		}, nil)
+	flag, err = webElem.IsDisplayed(ctx)
+	assert.Error(t, err)
+	assert.False(t, flag)
+}
+
 func TestWebElement_IsSelected(t *testing.T) {
 	webElem, cli, done := newWebElement(t, "123")
 	defer done()
@@ -512,7 +570,7 @@ func TestWebElement_SendKeys(t *testing.T) {
 
 	// returns error invalid args
 	err = webElem.SendKeys(ctx)
-	assert.Equal(t, ErrInvalidArguments, err)
+	assert.Equal(t, ErrInvalidArgument, err)
 
 	// returns error
 	cli.EXPECT().Do(ctx, http.MethodPost, "/session/123/element/"+testWebElementID+"/value", gomock.Any()).Times(1).Return(
@@ -528,3 +586,40 @@ func TestWebElement_SendKeys(t *testing.T) {
 	err = webElem.SendKeys(ctx, PageDownKey, PageUpKey)
 	assert.Error(t, err)
 }
+
+func TestWebElement_ShadowRoot(t *testing.T) {
+	webElem, cli, done := newWebElement(t, "123")
+	defer done()
+
+	ctx := context.Background()
+
+	// returns success
+	cli.EXPECT().Do(ctx, http.MethodGet, "/session/123/element/"+testWebElementID+"/shadow", nil).Times(1).Return(&Response{
+		Value: []byte(`{"shadow-6066-11e4-a52e-4f735466cecf":"` + testShadowRootID + `"}`),
+	}, nil)
+	root, err := webElem.ShadowRoot(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, testShadowRootID, root.ID())
+
+	// returns error
+	cli.EXPECT().Do(ctx, http.MethodGet, "/session/123/element/"+testWebElementID+"/shadow", nil).Times(1).Return(nil, elementWebErr)
+	root, err = webElem.ShadowRoot(ctx)
+	assert.Equal(t, elementWebErr, err)
+	assert.Nil(t, root)
+
+	// returns error no such shadow root
+	cli.EXPECT().Do(ctx, http.MethodGet, "/session/123/element/"+testWebElementID+"/shadow", nil).Times(1).Return(&Response{
+		Value: []byte(`{"element-6066-11e4-a52e-4f735466cecf":"` + testShadowRootID + `"}`),
+	}, nil)
+	root, err = webElem.ShadowRoot(ctx)
+	assert.Equal(t, ErrNoSuchShadowRoot, err)
+	assert.Nil(t, root)
+
+	// returns error unmarshal JSON
+	cli.EXPECT().Do(ctx, http.MethodGet, "/session/123/element/"+testWebElementID+"/shadow", nil).Times(1).Return(&Response{
+		Value: []byte(`{shadow"}`),
+	}, nil)
+	root, err = webElem.ShadowRoot(ctx)
+	assert.Error(t, err)
+	assert.Nil(t, root)
+}
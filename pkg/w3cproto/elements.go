@@ -130,7 +130,7 @@ func NewElements(doer Doer, sessID string) Elements {
 
 func (e *elements) FindOne(ctx context.Context, by FindElementStrategy, value string) (WebElement, error) {
 	if len(value) == 0 {
-		return nil, ErrInvalidArguments
+		return nil, ErrInvalidArgument
 	}
 	p := Params{
 		"value": value,
@@ -157,7 +157,7 @@ func (e *elements) FindOne(ctx context.Context, by FindElementStrategy, value st
 
 func (e *elements) Find(ctx context.Context, by FindElementStrategy, value string) ([]WebElement, error) {
 	if len(value) == 0 {
-		return nil, ErrInvalidArguments
+		return nil, ErrInvalidArgument
 	}
 	p := Params{
 		"value": value,
@@ -108,7 +108,7 @@ func TestElements_Find(t *testing.T) {
 	// returns error invalid arguments
 	webElems, err = elem.Find(ctx, ByCSSSelector, "")
 	assert.Error(t, err)
-	assert.Equal(t, ErrInvalidArguments, err)
+	assert.Equal(t, ErrInvalidArgument, err)
 	assert.Nil(t, webElems)
 
 	// returns error unmarshal JSON
@@ -157,7 +157,7 @@ func TestElements_FindOne(t *testing.T) {
 	// returns error invalid arguments
 	webElem, err = elem.FindOne(ctx, ByCSSSelector, "")
 	assert.Error(t, err)
-	assert.Equal(t, ErrInvalidArguments, err)
+	assert.Equal(t, ErrInvalidArgument, err)
 	assert.Nil(t, webElem)
 
 	// returns error unmarshal JSON
@@ -4,15 +4,176 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
+	"strings"
 )
 
 var (
-	ErrInvalidResponse      = errors.New("w3c: invalid response")
-	ErrInvalidArguments     = errors.New("w3c: invalid arguments")
+	ErrInvalidResponse = errors.New("w3c: invalid response")
+
+	// ErrInvalidArguments is the former name of ErrInvalidArgument.
+	//
+	// Deprecated: use ErrInvalidArgument.
+	ErrInvalidArguments error = ErrInvalidArgument
+
 	ErrUnknownWindowHandler = errors.New("w3c: unknown window handler")
-	ErrNoSuchElement        = errors.New("w3c: no such element")
 )
 
+// ErrorCode represents a WebDriver error code. Every error code is a sentinel error
+// that matches the protocol errors with the same code using errors.Is.
+//
+//	if errors.Is(err, w3cproto.ErrStaleElementReference) {
+//		// find the element again
+//	}
+type ErrorCode string
+
+// List of the WebDriver error codes.
+const (
+	// The Element Click command could not be completed because the element receiving
+	// the events is obscuring the element that was requested clicked.
+	ErrElementClickIntercepted ErrorCode = "element click intercepted"
+
+	// A command could not be completed because the element is not pointer- or keyboard interactable.
+	ErrElementNotInteractable ErrorCode = "element not interactable"
+
+	// Navigation caused the user agent to hit a certificate warning, which is usually the result
+	// of an expired or invalid TLS certificate.
+	ErrInsecureCertificate ErrorCode = "insecure certificate"
+
+	// The arguments passed to a command are either invalid or malformed. The error is also
+	// returned if the arguments are rejected by the client before the command is sent.
+	ErrInvalidArgument ErrorCode = "invalid argument"
+
+	// An illegal attempt was made to set a cookie under a different domain than the current page.
+	ErrInvalidCookieDomain ErrorCode = "invalid cookie domain"
+
+	// A command could not be completed because the element is in an invalid state,
+	// e.g. attempting to clear an element that isn't both editable and resettable.
+	ErrInvalidElementState ErrorCode = "invalid element state"
+
+	// Argument was an invalid selector.
+	ErrInvalidSelector ErrorCode = "invalid selector"
+
+	// Occurs if the given session id is not in the list of active sessions,
+	// meaning the session either does not exist or that it's not active.
+	ErrInvalidSessionID ErrorCode = "invalid session id"
+
+	// An error occurred while executing JavaScript supplied by the user.
+	ErrJavaScriptError ErrorCode = "javascript error"
+
+	// The target for mouse interaction is not in the browser's viewport and cannot be brought into that viewport.
+	ErrMoveTargetOutOfBounds ErrorCode = "move target out of bounds"
+
+	// An attempt was made to operate on a modal dialog when one was not open.
+	ErrNoSuchAlert ErrorCode = "no such alert"
+
+	// No cookie matching the given path name was found amongst the associated cookies
+	// of the current browsing context's active document.
+	ErrNoSuchCookie ErrorCode = "no such cookie"
+
+	// An element could not be located on the page using the given search parameters.
+	ErrNoSuchElement ErrorCode = "no such element"
+
+	// A command to switch to a frame could not be satisfied because the frame could not be found.
+	ErrNoSuchFrame ErrorCode = "no such frame"
+
+	// A command to switch to a window could not be satisfied because the window could not be found.
+	ErrNoSuchWindow ErrorCode = "no such window"
+
+	// The element does not have a shadow root.
+	ErrNoSuchShadowRoot ErrorCode = "no such shadow root"
+
+	// A script did not complete before its timeout expired.
+	ErrScriptTimeout ErrorCode = "script timeout"
+
+	// A new session could not be created.
+	ErrSessionNotCreated ErrorCode = "session not created"
+
+	// A command failed because the referenced element is no longer attached to the DOM.
+	ErrStaleElementReference ErrorCode = "stale element reference"
+
+	// A command failed because the referenced shadow root is no longer attached to the DOM.
+	ErrDetachedShadowRoot ErrorCode = "detached shadow root"
+
+	// An operation did not complete before its timeout expired.
+	ErrTimeout ErrorCode = "timeout"
+
+	// A command to set a cookie's value could not be satisfied.
+	ErrUnableToSetCookie ErrorCode = "unable to set cookie"
+
+	// A screen capture was made impossible.
+	ErrUnableToCaptureScreen ErrorCode = "unable to capture screen"
+
+	// A modal dialog was open, blocking this operation.
+	ErrUnexpectedAlertOpen ErrorCode = "unexpected alert open"
+
+	// A command could not be executed because the remote end is not aware of it.
+	ErrUnknownCommand ErrorCode = "unknown command"
+
+	// An unknown error occurred in the remote end while processing the command.
+	ErrUnknownError ErrorCode = "unknown error"
+
+	// The requested command matched a known URL but did not match any method for that URL.
+	ErrUnknownMethod ErrorCode = "unknown method"
+
+	// Indicates that a command that should have executed properly cannot be supported for some reason.
+	ErrUnsupportedOperation ErrorCode = "unsupported operation"
+)
+
+var errorCodes = map[ErrorCode]struct{}{
+	ErrElementClickIntercepted: {},
+	ErrElementNotInteractable:  {},
+	ErrInsecureCertificate:     {},
+	ErrInvalidArgument:         {},
+	ErrInvalidCookieDomain:     {},
+	ErrInvalidElementState:     {},
+	ErrInvalidSelector:         {},
+	ErrInvalidSessionID:        {},
+	ErrJavaScriptError:         {},
+	ErrMoveTargetOutOfBounds:   {},
+	ErrNoSuchAlert:             {},
+	ErrNoSuchCookie:            {},
+	ErrNoSuchElement:           {},
+	ErrNoSuchFrame:             {},
+	ErrNoSuchWindow:            {},
+	ErrNoSuchShadowRoot:        {},
+	ErrScriptTimeout:           {},
+	ErrSessionNotCreated:       {},
+	ErrStaleElementReference:   {},
+	ErrDetachedShadowRoot:      {},
+	ErrTimeout:                 {},
+	ErrUnableToSetCookie:       {},
+	ErrUnableToCaptureScreen:   {},
+	ErrUnexpectedAlertOpen:     {},
+	ErrUnknownCommand:          {},
+	ErrUnknownError:            {},
+	ErrUnknownMethod:           {},
+	ErrUnsupportedOperation:    {},
+}
+
+// httpErrorCodes maps the codes of the errors without a JSON payload onto the WebDriver error codes.
+var httpErrorCodes = map[string]ErrorCode{
+	StatusMissingCommandParameters: ErrInvalidArgument,
+	StatusUnknownCommand:           ErrUnknownCommand,
+	StatusInvalidCommandMethod:     ErrUnknownMethod,
+	StatusFailedCommand:            ErrUnknownError,
+	StatusUnimplementedCommand:     ErrUnsupportedOperation,
+}
+
+func (c ErrorCode) Error() string {
+	return "w3c: " + string(c)
+}
+
+func (c ErrorCode) String() string {
+	return string(c)
+}
+
+// IsKnown returns true if the code is defined by the WebDriver specification.
+func (c ErrorCode) IsKnown() bool {
+	_, ok := errorCodes[c]
+	return ok
+}
+
 // Error represents a WebDriver protocol error.
 type Error struct {
 	Code          string                 `json:"error"`
@@ -20,7 +181,11 @@ type Error struct {
 	RawStacktrace string                 `json:"stacktrace"`
 	Data          map[string]interface{} `json:"data"`
 
-	Stacktrace []string
+	Stacktrace []string `json:"-"`
+
+	// StatusCode is the HTTP status of the response, e.g. 503 of the proxy
+	// in front of the remote end responding with the JSON error.
+	StatusCode int `json:"-"`
 }
 
 func (e Error) Error() string {
@@ -30,12 +195,80 @@ func (e Error) Error() string {
 	)
 }
 
+// ErrorCode returns the WebDriver error code of the error.
+// Returns ErrUnknownError if the remote end responded with an unknown code.
+func (e Error) ErrorCode() ErrorCode {
+	code := ErrorCode(e.Code)
+	if code.IsKnown() {
+		return code
+	}
+	if code, ok := httpErrorCodes[e.Code]; ok {
+		return code
+	}
+	return ErrUnknownError
+}
+
+// Is reports whether the error matches the target error code.
+func (e Error) Is(target error) bool {
+	code, ok := target.(ErrorCode)
+	if !ok {
+		return false
+	}
+	return e.ErrorCode() == code
+}
+
+// HTTPError is returned when the remote end responds with an HTTP error status and a body
+// that is not a JSON response, e.g. the 502 Bad Gateway page of a proxy in front of the remote end.
+type HTTPError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("w3c: %d %s, %s", e.StatusCode, http.StatusText(e.StatusCode), e.Body)
+}
+
+// ErrorCodeOf returns the WebDriver error code of the error chain.
+func ErrorCodeOf(err error) (code ErrorCode, ok bool) {
+	if err == nil {
+		return code, false
+	}
+	if errors.As(err, &code) {
+		return code, true
+	}
+	var cmdErr *Error
+	if errors.As(err, &cmdErr) {
+		return cmdErr.ErrorCode(), true
+	}
+	var cmdErrValue Error
+	if errors.As(err, &cmdErrValue) {
+		return cmdErrValue.ErrorCode(), true
+	}
+	return code, false
+}
+
 func IsUnknownWindowHandler(err error) bool {
 	return ErrUnknownWindowHandler == err
 }
 
+// IsUnexpectedAlertOpen returns true if a modal dialog was open, blocking the command.
+func IsUnexpectedAlertOpen(err error) bool {
+	return errors.Is(err, ErrUnexpectedAlertOpen)
+}
+
+// IsNoSuchAlert returns true if the command operated on a modal dialog when one was not open.
+func IsNoSuchAlert(err error) bool {
+	return errors.Is(err, ErrNoSuchAlert)
+}
+
+// ParseError returns the error of the response with the HTTP status, decoded the same way
+// as the error responses of the remote end, e.g. to replay the recorded response.
+func ParseError(statusCode int, resp *Response) error {
+	return parseError(statusCode, resp)
+}
+
 func parseError(respStatusCode int, resp *Response) error {
-	cmdErr := new(Error)
+	cmdErr := &Error{StatusCode: respStatusCode}
 	// if error not JSON
 	if err := json.Unmarshal(resp.Value, cmdErr); err != nil {
 		cmdErr.Code = httpStatusCode(respStatusCode)
@@ -53,7 +286,7 @@ func parseError(respStatusCode int, resp *Response) error {
 	switch {
 	// {"status": 19, "value": null}
 	case isErrorWithStatusAndPayloadNull:
-		cmdErr.Code = httpStatusCode(respStatusCode)
+		cmdErr.Code = legacyErrorCode(respStatusCode, resp.Status)
 		statusMsg, ok := statusCode[resp.Status]
 		if ok {
 			cmdErr.Message = statusMsg
@@ -62,7 +295,7 @@ func parseError(respStatusCode int, resp *Response) error {
 	// {"status": 19, "value": {"error":"error code", "message": "error message"}}
 	case isErrorWithStatusAndPayload:
 		if len(cmdErr.Code) == 0 {
-			cmdErr.Code = httpStatusCode(respStatusCode)
+			cmdErr.Code = legacyErrorCode(respStatusCode, resp.Status)
 		}
 		if len(cmdErr.Message) == 0 {
 			cmdErr.Message = cmdErr.Code
@@ -82,5 +315,30 @@ func parseError(respStatusCode int, resp *Response) error {
 			cmdErr.Code = httpStatusCode(respStatusCode)
 		}
 	}
+	cmdErr.Stacktrace = splitStacktrace(cmdErr.RawStacktrace)
 	return cmdErr
 }
+
+// legacyErrorCode returns the WebDriver error code of the JSON Wire Protocol status.
+func legacyErrorCode(respStatusCode int, status int) string {
+	if code, ok := StatusErrorCode(status); ok {
+		return string(code)
+	}
+	return httpStatusCode(respStatusCode)
+}
+
+func splitStacktrace(raw string) []string {
+	lines := strings.Split(raw, "\n")
+	frames := make([]string, 0, len(lines))
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		frames = append(frames, line)
+	}
+	if len(frames) == 0 {
+		return nil
+	}
+	return frames
+}
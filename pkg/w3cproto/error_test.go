@@ -1,6 +1,8 @@
 package w3cproto
 
 import (
+	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -38,7 +40,7 @@ func TestError_ParseError(t *testing.T) {
 				Value:     []byte(`{"message":"error message", "data": {"x":"1"}}`),
 			},
 			e: &Error{
-				Code:          httpStatusCode(200),
+				Code:          string(ErrInvalidSelector),
 				Message:       "error message",
 				RawStacktrace: "",
 				Data: map[string]interface{}{
@@ -138,7 +140,7 @@ func TestError_ParseError(t *testing.T) {
 				Value:     []byte(`null`),
 			},
 			e: &Error{
-				Code:          httpStatusCode(200),
+				Code:          string(ErrTimeout),
 				Message:       StatusText(TimeoutStatusCode),
 				RawStacktrace: "",
 				Data:          nil,
@@ -161,3 +163,74 @@ func TestError_ParseError(t *testing.T) {
 		})
 	}
 }
+
+func TestError_IsUnexpectedAlertOpen(t *testing.T) {
+	assert.True(t, IsUnexpectedAlertOpen(&Error{Code: "unexpected alert open"}))
+	assert.True(t, IsUnexpectedAlertOpen(Error{Code: "unexpected alert open"}))
+	assert.False(t, IsUnexpectedAlertOpen(&Error{Code: "no such alert"}))
+	assert.False(t, IsUnexpectedAlertOpen(ErrInvalidResponse))
+	assert.True(t, IsNoSuchAlert(&Error{Code: "no such alert"}))
+	assert.False(t, IsNoSuchAlert(nil))
+}
+
+func TestError_Is(t *testing.T) {
+	err := parseError(404, &Response{
+		Value: []byte(`{"error":"no such element","message":"no such element: Unable to locate element","stacktrace":"#0 0x55d5d0a1\n#1 0x55d5d0a2\n\n"}`),
+	})
+	assert.True(t, errors.Is(err, ErrNoSuchElement))
+	assert.False(t, errors.Is(err, ErrStaleElementReference))
+	assert.Equal(t, []string{"#0 0x55d5d0a1", "#1 0x55d5d0a2"}, err.(*Error).Stacktrace)
+
+	wrapped := fmt.Errorf("find button: %w", err)
+	assert.True(t, errors.Is(wrapped, ErrNoSuchElement))
+	code, ok := ErrorCodeOf(wrapped)
+	assert.True(t, ok)
+	assert.Equal(t, ErrNoSuchElement, code)
+
+	// legacy status
+	err = parseError(200, &Response{Status: StaleElementReferenceStatusCode, Value: []byte(`null`)})
+	assert.True(t, errors.Is(err, ErrStaleElementReference))
+
+	// without JSON payload
+	err = parseError(404, &Response{Value: []byte(`not found`)})
+	assert.True(t, errors.Is(err, ErrUnknownCommand))
+
+	// unknown code
+	err = Error{Code: "custom"}
+	assert.True(t, errors.Is(err, ErrUnknownError))
+	assert.False(t, errors.Is(err, ErrInvalidResponse))
+
+	// client side sentinel
+	code, ok = ErrorCodeOf(ErrNoSuchShadowRoot)
+	assert.True(t, ok)
+	assert.Equal(t, ErrNoSuchShadowRoot, code)
+	assert.Equal(t, "w3c: no such shadow root", ErrNoSuchShadowRoot.Error())
+
+	// arguments rejected by the client
+	wrapped = fmt.Errorf("print: %w", ErrInvalidArgument)
+	assert.True(t, errors.Is(wrapped, ErrInvalidArgument))
+	assert.True(t, errors.Is(wrapped, ErrInvalidArguments))
+	assert.False(t, errors.Is(wrapped, ErrInvalidSelector))
+	code, ok = ErrorCodeOf(wrapped)
+	assert.True(t, ok)
+	assert.Equal(t, ErrInvalidArgument, code)
+	assert.Equal(t, "w3c: invalid argument", ErrInvalidArgument.Error())
+
+	_, ok = ErrorCodeOf(ErrInvalidResponse)
+	assert.False(t, ok)
+	_, ok = ErrorCodeOf(nil)
+	assert.False(t, ok)
+}
+
+func TestStatusErrorCode(t *testing.T) {
+	for status := range statusCode {
+		if status == SuccessStatusCode {
+			continue
+		}
+		code, ok := StatusErrorCode(status)
+		assert.True(t, ok, "status %d", status)
+		assert.True(t, code.IsKnown(), "status %d", status)
+	}
+	_, ok := StatusErrorCode(SuccessStatusCode)
+	assert.False(t, ok)
+}
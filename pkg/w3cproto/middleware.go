@@ -0,0 +1,179 @@
+package w3cproto
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// RequestIDHeader is the default header carrying the request id.
+const RequestIDHeader = "X-Request-Id"
+
+// DoerFunc is an adapter to allow the use of ordinary functions as doers.
+type DoerFunc func(ctx context.Context, method string, path string, p Params) (*Response, error)
+
+// Do calls f(ctx, method, path, p).
+func (f DoerFunc) Do(ctx context.Context, method string, path string, p Params) (*Response, error) {
+	return f(ctx, method, path, p)
+}
+
+// Middleware wraps the doer to add behaviour to every command,
+// e.g. logging, authentication, tracing or fault injection.
+type Middleware func(next Doer) Doer
+
+// Chain wraps the doer with the middlewares. The first middleware is the outermost one,
+// i.e. it sees the command first and the response last.
+func Chain(doer Doer, middlewares ...Middleware) Doer {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		if middlewares[i] != nil {
+			doer = middlewares[i](doer)
+		}
+	}
+	return doer
+}
+
+// Command describes the completed command passed to the interceptor.
+type Command struct {
+	Method   string
+	Path     string
+	Params   Params
+	Response *Response
+	Err      error
+	Start    time.Time
+	Duration time.Duration
+}
+
+// Interceptor observes the completed commands.
+type Interceptor func(ctx context.Context, cmd *Command)
+
+// Intercept returns the middleware that calls the interceptor after each command.
+func Intercept(fn Interceptor) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(ctx context.Context, method string, path string, p Params) (*Response, error) {
+			start := time.Now()
+			resp, err := next.Do(ctx, method, path, p)
+			fn(ctx, &Command{
+				Method:   method,
+				Path:     path,
+				Params:   p,
+				Response: resp,
+				Err:      err,
+				Start:    start,
+				Duration: time.Since(start),
+			})
+			return resp, err
+		})
+	}
+}
+
+// Logger is the interface of the command logger, e.g. *log.Logger.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// LogCommands returns the middleware that logs each command as a line of key=value pairs:
+//
+//	w3c: method=POST path=/session/123/url duration=15ms request_id=4f1c... status=ok
+//
+// The params and the response values are not logged, they may contain secrets.
+// The request id is logged if PropagateRequestID precedes the middleware in the chain.
+func LogCommands(logger Logger) Middleware {
+	return Intercept(func(ctx context.Context, cmd *Command) {
+		var b strings.Builder
+		fmt.Fprintf(&b, "w3c: method=%s path=%s duration=%s", cmd.Method, cmd.Path, cmd.Duration)
+		if id := RequestIDFromContext(ctx); len(id) > 0 {
+			fmt.Fprintf(&b, " request_id=%s", id)
+		}
+		if cmd.Err != nil {
+			var e *Error
+			if errors.As(cmd.Err, &e) {
+				fmt.Fprintf(&b, " status=error code=%q", e.Code)
+			} else {
+				b.WriteString(" status=error")
+			}
+			fmt.Fprintf(&b, " error=%q", cmd.Err.Error())
+		} else {
+			b.WriteString(" status=ok")
+		}
+		logger.Printf("%s", b.String())
+	})
+}
+
+// InjectHeaders returns the middleware that adds the headers to the HTTP request of each command.
+// The injected headers replace the default headers with the same key.
+func InjectHeaders(headers http.Header) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(ctx context.Context, method string, path string, p Params) (*Response, error) {
+			return next.Do(ContextWithHeaders(ctx, headers), method, path, p)
+		})
+	}
+}
+
+// PropagateRequestID returns the middleware that sends the request id in the header.
+// The id stored in the context by ContextWithRequestID is used if any, otherwise a random id
+// is generated for each command. RequestIDHeader is used if the header is empty.
+func PropagateRequestID(header string) Middleware {
+	if len(header) == 0 {
+		header = RequestIDHeader
+	}
+	return func(next Doer) Doer {
+		return DoerFunc(func(ctx context.Context, method string, path string, p Params) (*Response, error) {
+			id := RequestIDFromContext(ctx)
+			if len(id) == 0 {
+				id = newRequestID()
+				ctx = ContextWithRequestID(ctx, id)
+			}
+			headers := make(http.Header)
+			headers.Set(header, id)
+			return next.Do(ContextWithHeaders(ctx, headers), method, path, p)
+		})
+	}
+}
+
+type (
+	headersKey   struct{}
+	requestIDKey struct{}
+)
+
+// ContextWithHeaders returns the context carrying the headers that the transport adds
+// to the HTTP request. The headers are merged with the headers already in the context.
+func ContextWithHeaders(ctx context.Context, headers http.Header) context.Context {
+	merged := HeadersFromContext(ctx).Clone()
+	if merged == nil {
+		merged = make(http.Header, len(headers))
+	}
+	for key, values := range headers {
+		merged[http.CanonicalHeaderKey(key)] = append([]string(nil), values...)
+	}
+	return context.WithValue(ctx, headersKey{}, merged)
+}
+
+// HeadersFromContext returns the headers stored in the context.
+func HeadersFromContext(ctx context.Context) http.Header {
+	headers, _ := ctx.Value(headersKey{}).(http.Header)
+	return headers
+}
+
+// ContextWithRequestID returns the context carrying the request id.
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request id stored in the context.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+func newRequestID() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(buf)
+}
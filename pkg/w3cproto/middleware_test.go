@@ -0,0 +1,132 @@
+package w3cproto
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+type testLogger struct {
+	buf bytes.Buffer
+}
+
+func (l *testLogger) Printf(format string, v ...interface{}) {
+	fmt.Fprintf(&l.buf, format, v...)
+	l.buf.WriteByte('\n')
+}
+
+func TestChain(t *testing.T) {
+	var calls []string
+	trace := func(name string) Middleware {
+		return func(next Doer) Doer {
+			return DoerFunc(func(ctx context.Context, method string, path string, p Params) (*Response, error) {
+				calls = append(calls, name+" before")
+				resp, err := next.Do(ctx, method, path, p)
+				calls = append(calls, name+" after")
+				return resp, err
+			})
+		}
+	}
+	doer := Chain(DoerFunc(func(ctx context.Context, method string, path string, p Params) (*Response, error) {
+		calls = append(calls, "doer")
+		return &Response{Value: []byte("null")}, nil
+	}), trace("first"), nil, trace("second"))
+
+	resp, err := doer.Do(context.Background(), http.MethodGet, "/status", nil)
+	assert.Nil(t, err)
+	assert.True(t, resp.Success())
+	assert.Equal(t, []string{"first before", "second before", "doer", "second after", "first after"}, calls)
+}
+
+func TestIntercept(t *testing.T) {
+	wantErr := errors.New("connection refused")
+	var cmd *Command
+	doer := Chain(DoerFunc(func(ctx context.Context, method string, path string, p Params) (*Response, error) {
+		return nil, wantErr
+	}), Intercept(func(ctx context.Context, c *Command) {
+		cmd = c
+	}))
+
+	_, err := doer.Do(context.Background(), http.MethodPost, "/session/123/url", Params{"url": "http://x"})
+	assert.Equal(t, wantErr, err)
+	assert.Equal(t, http.MethodPost, cmd.Method)
+	assert.Equal(t, "/session/123/url", cmd.Path)
+	assert.Equal(t, Params{"url": "http://x"}, cmd.Params)
+	assert.Nil(t, cmd.Response)
+	assert.Equal(t, wantErr, cmd.Err)
+	assert.False(t, cmd.Start.IsZero())
+	assert.True(t, cmd.Duration >= 0)
+}
+
+func TestLogCommands(t *testing.T) {
+	logger := new(testLogger)
+	doer := Chain(DoerFunc(func(ctx context.Context, method string, path string, p Params) (*Response, error) {
+		if path == "/session/123/element" {
+			return nil, &Error{Code: "no such element", Message: "not found"}
+		}
+		return &Response{Value: []byte("null")}, nil
+	}), LogCommands(logger))
+	ctx := ContextWithRequestID(context.Background(), "abc")
+
+	// returns success
+	_, err := doer.Do(ctx, http.MethodGet, "/session/123/url", nil)
+	assert.Nil(t, err)
+	assert.Regexp(t, `^w3c: method=GET path=/session/123/url duration=\S+ request_id=abc status=ok\n$`, logger.buf.String())
+
+	// returns error
+	logger.buf.Reset()
+	_, err = doer.Do(context.Background(), http.MethodPost, "/session/123/element", Params{"value": "secret"})
+	assert.NotNil(t, err)
+	assert.Regexp(t, `^w3c: method=POST path=/session/123/element duration=\S+ status=error code="no such element" error=".+"\n$`, logger.buf.String())
+	assert.NotContains(t, logger.buf.String(), "secret")
+}
+
+func TestInjectHeadersAndRequestID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client, transport := newHttpClient(t, ctrl)
+	var requests []*http.Request
+	transport.EXPECT().Do(gomock.Any()).Times(3).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+		requests = append(requests, req)
+		return &http.Response{
+			Body:       makeBody(`{"value": null}`),
+			StatusCode: http.StatusOK,
+		}, nil
+	})
+	headers := make(http.Header)
+	headers.Set("Authorization", "Bearer token")
+	headers.Set("Accept", "application/json;q=0.9")
+	doer := Chain(WithClient(client), PropagateRequestID(""), InjectHeaders(headers))
+
+	// generates the request ids
+	_, err := doer.Do(context.Background(), http.MethodGet, "/session/123/url", nil)
+	assert.Nil(t, err)
+	_, err = doer.Do(context.Background(), http.MethodPost, "/session/123/url", Params{"url": "http://x"})
+	assert.Nil(t, err)
+	// uses the request id of the context
+	_, err = doer.Do(ContextWithRequestID(context.Background(), "abc"), http.MethodDelete, "/session/123", nil)
+	assert.Nil(t, err)
+
+	assert.Len(t, requests, 3)
+	for _, req := range requests {
+		assert.Equal(t, "Bearer token", req.Header.Get("Authorization"))
+		assert.Equal(t, []string{"application/json;q=0.9"}, req.Header.Values("Accept"))
+		assert.Equal(t, "application/json;charset=utf-8", req.Header.Get("Content-Type"))
+	}
+	assert.Len(t, requests[0].Header.Get(RequestIDHeader), 32)
+	assert.NotEqual(t, requests[0].Header.Get(RequestIDHeader), requests[1].Header.Get(RequestIDHeader))
+	assert.Equal(t, "abc", requests[2].Header.Get(RequestIDHeader))
+}
+
+func TestContextWithHeaders(t *testing.T) {
+	ctx := ContextWithHeaders(context.Background(), http.Header{"x-a": {"1"}})
+	ctx2 := ContextWithHeaders(ctx, http.Header{"X-B": {"2"}, "X-A": {"3"}})
+	assert.Equal(t, http.Header{"X-A": {"1"}}, HeadersFromContext(ctx))
+	assert.Equal(t, http.Header{"X-A": {"3"}, "X-B": {"2"}}, HeadersFromContext(ctx2))
+	assert.Nil(t, HeadersFromContext(context.Background()))
+}
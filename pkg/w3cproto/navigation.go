@@ -44,7 +44,7 @@ func NewNavigation(doer Doer, sessID string) Navigation {
 
 func (n *navigation) NavigateTo(ctx context.Context, url string) (err error) {
 	if len(url) == 0 {
-		return fmt.Errorf("%v, url is empty", ErrInvalidArguments)
+		return fmt.Errorf("%v, url is empty", ErrInvalidArgument)
 	}
 	if !strings.HasPrefix(url, "http") {
 		url = "http://" + url
@@ -64,7 +64,7 @@ func (n *navigation) GetCurrentURL(ctx context.Context) (s string, err error) {
 	if err != nil {
 		return s, err
 	}
-	return string(resp.Value), nil
+	return stringValue(resp.Value), nil
 }
 
 func (n *navigation) Back(ctx context.Context) error {
@@ -105,5 +105,5 @@ func (n *navigation) GetTitle(ctx context.Context) (title string, err error) {
 	if err != nil {
 		return title, err
 	}
-	return string(resp.Value), nil
+	return stringValue(resp.Value), nil
 }
@@ -93,6 +93,17 @@ func TestNavigation_GetCurrentURL(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, curURL, botURL)
 
+	// returns success (JSON string)
+	cli.EXPECT().Do(ctx, http.MethodGet, "/session/123/url", nil).Times(1).Return(
+		&Response{
+			SessionID: "132",
+			Status:    0,
+			Value:     []byte(`"` + botURL + `/?q=a%20b"`),
+		}, nil)
+	curURL, err = navigation.GetCurrentURL(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, botURL+"/?q=a%20b", curURL)
+
 	// returns errors (invalid response)
 	cli.EXPECT().Do(ctx, http.MethodGet, "/session/123/url", nil).Times(1).Return(
 		nil, ErrInvalidResponse)
@@ -223,6 +234,13 @@ func TestNavigation_GetTitle(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, haveTitle, wantTitle)
 
+	// returns success (JSON string)
+	cli.EXPECT().Do(ctx, http.MethodGet, "/session/123/title", nil).Times(1).Return(
+		&Response{Value: []byte(`"MediaBuyerBot \"beta\""`)}, nil)
+	haveTitle, err = navigation.GetTitle(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, `MediaBuyerBot "beta"`, haveTitle)
+
 	// returns error
 	cli.EXPECT().Do(ctx, http.MethodGet, "/session/123/title", nil).Times(1).Return(nil, ErrInvalidResponse)
 	haveTitle, err = navigation.GetTitle(ctx)
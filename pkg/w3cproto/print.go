@@ -0,0 +1,178 @@
+package w3cproto
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+)
+
+// Print represents a print page protocol.
+type Print interface {
+
+	// PDF renders the current page as a paginated PDF document.
+	PDF(ctx context.Context, opts PrintOptions) (io.Reader, error)
+}
+
+const (
+	Portrait  PrintOrientation = "portrait"
+	Landscape PrintOrientation = "landscape"
+)
+
+type PrintOrientation string
+
+func (o PrintOrientation) String() string {
+	return string(o)
+}
+
+func (o PrintOrientation) Validate() error {
+	switch o {
+	case Portrait, Landscape:
+		return nil
+	default:
+		return fmt.Errorf("w3c: unknown print orientation %s", o)
+	}
+}
+
+const (
+	minPrintScale    = 0.1
+	maxPrintScale    = 2
+	minPrintPageSize = 2.54 / 72
+)
+
+var pageRangeRegexp = regexp.MustCompile(`^\s*(\d+\s*-?\s*\d*|-\s*\d+)\s*$`)
+
+// PageSize represents the paper size in centimeters.
+type PageSize struct {
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+// PageMargin represents the page margins in centimeters.
+type PageMargin struct {
+	Top    float64 `json:"top"`
+	Bottom float64 `json:"bottom"`
+	Left   float64 `json:"left"`
+	Right  float64 `json:"right"`
+}
+
+// PrintOptions represents the print parameters.
+type PrintOptions struct {
+	Orientation PrintOrientation
+	Scale       float64
+	Background  bool
+	Page        PageSize
+	Margin      PageMargin
+	ShrinkToFit bool
+
+	// PageRanges the pages to print, e.g. "1", "3-5", "7-".
+	// Prints all pages if omitted.
+	PageRanges []string
+}
+
+// DefaultPrintOptions returns the print parameters defined by the specification:
+// portrait US letter page with 1cm margins, scale 1, no background and shrink to fit.
+func DefaultPrintOptions() PrintOptions {
+	return PrintOptions{
+		Orientation: Portrait,
+		Scale:       1,
+		Page: PageSize{
+			Width:  21.59,
+			Height: 27.94,
+		},
+		Margin: PageMargin{
+			Top:    1,
+			Bottom: 1,
+			Left:   1,
+			Right:  1,
+		},
+		ShrinkToFit: true,
+	}
+}
+
+// WithDefaults returns the options with the zero orientation, scale and page size
+// replaced by the default ones. The margins and the flags are kept as is.
+func (o PrintOptions) WithDefaults() PrintOptions {
+	defaults := DefaultPrintOptions()
+	if len(o.Orientation) == 0 {
+		o.Orientation = defaults.Orientation
+	}
+	if o.Scale == 0 {
+		o.Scale = defaults.Scale
+	}
+	if o.Page.Width == 0 {
+		o.Page.Width = defaults.Page.Width
+	}
+	if o.Page.Height == 0 {
+		o.Page.Height = defaults.Page.Height
+	}
+	return o
+}
+
+func (o PrintOptions) Validate() error {
+	if err := o.Orientation.Validate(); err != nil {
+		return err
+	}
+	if o.Scale < minPrintScale || o.Scale > maxPrintScale {
+		return fmt.Errorf("%w, scale must be between %v and %v", ErrInvalidArgument, minPrintScale, maxPrintScale)
+	}
+	if o.Page.Width < minPrintPageSize || o.Page.Height < minPrintPageSize {
+		return fmt.Errorf("%w, page is too small", ErrInvalidArgument)
+	}
+	if o.Margin.Top < 0 || o.Margin.Bottom < 0 || o.Margin.Left < 0 || o.Margin.Right < 0 {
+		return fmt.Errorf("%w, margin must be non-negative", ErrInvalidArgument)
+	}
+	for _, pageRange := range o.PageRanges {
+		if !pageRangeRegexp.MatchString(pageRange) {
+			return fmt.Errorf("%w, invalid page range %q", ErrInvalidArgument, pageRange)
+		}
+	}
+	return nil
+}
+
+func (o PrintOptions) params() Params {
+	pageRanges := o.PageRanges
+	if pageRanges == nil {
+		pageRanges = []string{}
+	}
+	return Params{
+		"orientation": o.Orientation,
+		"scale":       o.Scale,
+		"background":  o.Background,
+		"page":        o.Page,
+		"margin":      o.Margin,
+		"shrinkToFit": o.ShrinkToFit,
+		"pageRanges":  pageRanges,
+	}
+}
+
+type printer struct {
+	id      string
+	request Doer
+}
+
+// NewPrint creates a new instance of Print.
+func NewPrint(doer Doer, sessID string) Print {
+	return &printer{
+		id:      sessID,
+		request: doer,
+	}
+}
+
+func (p *printer) PDF(ctx context.Context, opts PrintOptions) (io.Reader, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	resp, err := p.request.Do(ctx, http.MethodPost, "/session/"+p.id+"/print", opts.params())
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Value) < 2 {
+		return nil, ErrInvalidResponse
+	}
+	buf := bytes.NewBuffer(resp.Value[1 : len(resp.Value)-1])
+	return base64.NewDecoder(base64.StdEncoding, buf), nil
+}
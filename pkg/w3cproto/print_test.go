@@ -0,0 +1,103 @@
+package w3cproto
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/golang/mock/gomock"
+)
+
+var printErr = &Error{Code: "error"}
+
+func newPrint(t *testing.T, sessID string) (Print, *MockDoer, func()) {
+	ctrl := gomock.NewController(t)
+	cli := NewMockDoer(ctrl)
+	cx := NewPrint(cli, sessID)
+	return cx, cli, func() {
+		ctrl.Finish()
+	}
+}
+
+func TestPrint_PDF(t *testing.T) {
+	pr, cli, done := newPrint(t, "123")
+	defer done()
+
+	ctx := context.TODO()
+	opts := DefaultPrintOptions()
+	opts.Orientation = Landscape
+	opts.PageRanges = []string{"1", "3-5", "7-", "-2"}
+
+	// returns success
+	cli.EXPECT().Do(ctx, http.MethodPost, "/session/123/print", Params{
+		"orientation": Landscape,
+		"scale":       float64(1),
+		"background":  false,
+		"page":        PageSize{Width: 21.59, Height: 27.94},
+		"margin":      PageMargin{Top: 1, Bottom: 1, Left: 1, Right: 1},
+		"shrinkToFit": true,
+		"pageRanges":  []string{"1", "3-5", "7-", "-2"},
+	}).Times(1).Return(
+		&Response{
+			Value: []byte(`"JVBERi0xLjQK"`),
+		}, nil)
+	reader, err := pr.PDF(ctx, opts)
+	assert.Nil(t, err)
+	have, err := ioutil.ReadAll(reader)
+	assert.Nil(t, err)
+	assert.Equal(t, "%PDF-1.4\n", string(have))
+
+	// returns error
+	cli.EXPECT().Do(ctx, http.MethodPost, "/session/123/print", gomock.Any()).Times(1).Return(nil, printErr)
+	_, err = pr.PDF(ctx, opts)
+	assert.Equal(t, printErr, err)
+
+	// returns error empty data
+	cli.EXPECT().Do(ctx, http.MethodPost, "/session/123/print", gomock.Any()).Times(1).Return(
+		&Response{
+			Value: []byte(``),
+		}, nil)
+	_, err = pr.PDF(ctx, opts)
+	assert.Equal(t, ErrInvalidResponse, err)
+
+	// returns error (invalid options)
+	_, err = pr.PDF(ctx, PrintOptions{})
+	assert.Error(t, err)
+}
+
+func TestPrintOptions_Validate(t *testing.T) {
+	assert.Nil(t, DefaultPrintOptions().Validate())
+
+	opts := DefaultPrintOptions()
+	opts.Orientation = "diagonal"
+	assert.Error(t, opts.Validate())
+
+	opts = DefaultPrintOptions()
+	opts.Scale = 2.5
+	assert.True(t, errors.Is(opts.Validate(), ErrInvalidArgument))
+
+	opts = DefaultPrintOptions()
+	opts.Page.Width = 0
+	assert.Error(t, opts.Validate())
+
+	opts = DefaultPrintOptions()
+	opts.Margin.Left = -1
+	assert.Error(t, opts.Validate())
+
+	opts = DefaultPrintOptions()
+	opts.PageRanges = []string{"a-b"}
+	assert.Error(t, opts.Validate())
+}
+
+func TestPrintOptions_WithDefaults(t *testing.T) {
+	opts := PrintOptions{Orientation: Landscape, Page: PageSize{Width: 10}}.WithDefaults()
+	assert.Nil(t, opts.Validate())
+	assert.Equal(t, Landscape, opts.Orientation)
+	assert.Equal(t, float64(1), opts.Scale)
+	assert.Equal(t, PageSize{Width: 10, Height: 27.94}, opts.Page)
+	assert.Equal(t, PageMargin{}, opts.Margin)
+}
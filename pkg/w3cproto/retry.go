@@ -0,0 +1,186 @@
+package w3cproto
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	DefaultRetryAttempts  = 3
+	DefaultInitialBackoff = 100 * time.Millisecond
+	DefaultMaxBackoff     = 5 * time.Second
+	DefaultBackoffFactor  = 2.0
+	DefaultJitter         = 0.2
+)
+
+// RetryPolicy configures the retries of the commands failed with transient errors.
+//
+// The idempotent commands are retried on any transient error. The other commands may
+// have been executed by the remote end before the failure, so they are retried only
+// if the connection was refused, i.e. the command has not reached the remote end.
+type RetryPolicy struct {
+	// MaxAttempts is the maximal number of attempts including the first one.
+	// DefaultRetryAttempts is used if zero.
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the first retry. DefaultInitialBackoff is used if zero.
+	InitialBackoff time.Duration
+
+	// MaxBackoff bounds the delay between the attempts. DefaultMaxBackoff is used if zero.
+	MaxBackoff time.Duration
+
+	// Factor multiplies the delay after each retry. DefaultBackoffFactor is used if less than 1.
+	Factor float64
+
+	// Jitter is the fraction of the delay randomly subtracted from it, so that the clients
+	// do not retry in lockstep. DefaultJitter is used if zero, a negative value disables the jitter.
+	Jitter float64
+
+	// Idempotent reports whether the command can be repeated safely. IsIdempotent is used if nil.
+	Idempotent func(method string, path string) bool
+
+	// OnRetry is called before waiting for the next attempt.
+	OnRetry func(ctx context.Context, attempt int, err error, delay time.Duration)
+}
+
+// Retry returns the middleware retrying the commands failed with transient errors according
+// to the policy. The retries stop when the context is done or its deadline is too close
+// for the next attempt, the last error is returned.
+func Retry(policy RetryPolicy) Middleware {
+	return func(next Doer) Doer {
+		return DoerFunc(func(ctx context.Context, method string, path string, p Params) (*Response, error) {
+			idempotent := policy.idempotent(method, path)
+			for attempt := 1; ; attempt++ {
+				resp, err := next.Do(ctx, method, path, p)
+				if err == nil || attempt >= policy.maxAttempts() {
+					return resp, err
+				}
+				if !IsConnectionRefused(err) && !(idempotent && IsTransient(err)) {
+					return resp, err
+				}
+				delay := policy.backoff(attempt)
+				if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+					return resp, err
+				}
+				if policy.OnRetry != nil {
+					policy.OnRetry(ctx, attempt, err, delay)
+				}
+				timer := time.NewTimer(delay)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return resp, err
+				case <-timer.C:
+				}
+			}
+		})
+	}
+}
+
+func (p RetryPolicy) maxAttempts() int {
+	if p.MaxAttempts <= 0 {
+		return DefaultRetryAttempts
+	}
+	return p.MaxAttempts
+}
+
+func (p RetryPolicy) idempotent(method string, path string) bool {
+	if p.Idempotent != nil {
+		return p.Idempotent(method, path)
+	}
+	return IsIdempotent(method, path)
+}
+
+// backoff returns the delay after the attempt (starting from 1).
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	initial, max, factor, jitter := p.InitialBackoff, p.MaxBackoff, p.Factor, p.Jitter
+	if initial <= 0 {
+		initial = DefaultInitialBackoff
+	}
+	if max <= 0 {
+		max = DefaultMaxBackoff
+	}
+	if factor < 1 {
+		factor = DefaultBackoffFactor
+	}
+	if jitter == 0 {
+		jitter = DefaultJitter
+	}
+	delay := float64(initial) * math.Pow(factor, float64(attempt-1))
+	if delay > float64(max) {
+		delay = float64(max)
+	}
+	if jitter > 0 {
+		delay -= delay * math.Min(jitter, 1) * rand.Float64()
+	}
+	return time.Duration(delay)
+}
+
+// IsIdempotent returns true for the GET commands and the POST commands locating the elements,
+// which do not change the state of the browser.
+func IsIdempotent(method string, path string) bool {
+	switch method {
+	case http.MethodGet:
+		return true
+	case http.MethodPost:
+		return strings.HasSuffix(path, "/element") || strings.HasSuffix(path, "/elements")
+	}
+	return false
+}
+
+// IsTransient returns true if the command failed with an error that may not repeat:
+// a refused or reset connection, an unexpected EOF, the 502, 503 or 504 HTTP status
+// with or without the JSON error or the "chrome not reachable" error of chromedriver.
+func IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+	if IsConnectionRefused(err) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		switch httpErr.StatusCode {
+		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+	var cmdErr *Error
+	if errors.As(err, &cmdErr) {
+		switch cmdErr.StatusCode {
+		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return strings.Contains(cmdErr.Message, "chrome not reachable")
+	}
+	// the HTTP client flattens the connection errors into messages
+	msg := err.Error()
+	return strings.HasSuffix(msg, "EOF") || strings.Contains(msg, "connection reset by peer")
+}
+
+// IsConnectionRefused returns true if the remote end refused the connection,
+// e.g. the driver is still starting.
+func IsConnectionRefused(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+	var cmdErr *Error
+	if errors.As(err, &cmdErr) {
+		return false
+	}
+	return strings.Contains(err.Error(), "connection refused")
+}
@@ -0,0 +1,179 @@
+package w3cproto
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mediabuyerbot/httpclient"
+	"github.com/stretchr/testify/assert"
+)
+
+func newRetryDoer(policy RetryPolicy, errs ...error) (Doer, *int) {
+	calls := new(int)
+	return Chain(DoerFunc(func(ctx context.Context, method string, path string, p Params) (*Response, error) {
+		*calls++
+		if *calls <= len(errs) {
+			return nil, errs[*calls-1]
+		}
+		return &Response{Value: []byte("null")}, nil
+	}), Retry(policy)), calls
+}
+
+func TestRetry(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, Jitter: -1}
+	ctx := context.Background()
+	refused := errors.New(`Get "http://127.0.0.1:4444/status": dial tcp 127.0.0.1:4444: connect: connection refused`)
+	unreachable := &Error{Code: "unknown error", Message: "unknown error: chrome not reachable"}
+	unavailable := &HTTPError{StatusCode: http.StatusServiceUnavailable}
+
+	// returns success after the transient errors
+	doer, calls := newRetryDoer(policy, io.EOF, unavailable)
+	_, err := doer.Do(ctx, http.MethodGet, "/session/123/url", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, *calls)
+
+	// returns the last error after the max attempts
+	doer, calls = newRetryDoer(policy, io.EOF, unavailable, unreachable)
+	_, err = doer.Do(ctx, http.MethodGet, "/session/123/url", nil)
+	assert.Equal(t, unreachable, err)
+	assert.Equal(t, 3, *calls)
+
+	// returns the permanent error at once
+	doer, calls = newRetryDoer(policy, &Error{Code: "no such element"})
+	_, err = doer.Do(ctx, http.MethodPost, "/session/123/element", nil)
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, *calls)
+
+	// retries the locator commands
+	doer, calls = newRetryDoer(policy, unavailable)
+	_, err = doer.Do(ctx, http.MethodPost, "/session/123/element/456/elements", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, *calls)
+
+	// returns error of the non-idempotent command that could reach the remote end
+	doer, calls = newRetryDoer(policy, io.EOF)
+	_, err = doer.Do(ctx, http.MethodPost, "/session/123/element/456/click", nil)
+	assert.Equal(t, io.EOF, err)
+	assert.Equal(t, 1, *calls)
+
+	// retries the non-idempotent command that did not reach the remote end
+	doer, calls = newRetryDoer(policy, refused)
+	_, err = doer.Do(ctx, http.MethodPost, "/session", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, *calls)
+
+	// returns error if the deadline is too close for the next attempt
+	slow := RetryPolicy{InitialBackoff: time.Minute}
+	doer, calls = newRetryDoer(slow, io.EOF)
+	deadlineCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	_, err = doer.Do(deadlineCtx, http.MethodGet, "/session/123/url", nil)
+	assert.Equal(t, io.EOF, err)
+	assert.Equal(t, 1, *calls)
+}
+
+func TestRetry_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var retries int
+	policy := RetryPolicy{
+		InitialBackoff: time.Minute,
+		OnRetry: func(ctx context.Context, attempt int, err error, delay time.Duration) {
+			retries++
+			assert.Equal(t, 1, attempt)
+			assert.Equal(t, io.EOF, err)
+			cancel()
+		},
+	}
+	doer, calls := newRetryDoer(policy, io.EOF)
+	_, err := doer.Do(ctx, http.MethodGet, "/session/123/url", nil)
+	assert.Equal(t, io.EOF, err)
+	assert.Equal(t, 1, *calls)
+	assert.Equal(t, 1, retries)
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second, Jitter: -1}
+	assert.Equal(t, 100*time.Millisecond, policy.backoff(1))
+	assert.Equal(t, 200*time.Millisecond, policy.backoff(2))
+	assert.Equal(t, 800*time.Millisecond, policy.backoff(4))
+	assert.Equal(t, time.Second, policy.backoff(5))
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		delay := policy.backoff(2)
+		assert.True(t, delay > 100*time.Millisecond && delay <= 200*time.Millisecond, delay)
+	}
+}
+
+func TestIsTransient(t *testing.T) {
+	assert.False(t, IsTransient(nil))
+	assert.True(t, IsTransient(io.ErrUnexpectedEOF))
+	assert.True(t, IsTransient(errors.New(`Post "http://127.0.0.1:4444/session": EOF`)))
+	assert.True(t, IsTransient(errors.New("read tcp: connection reset by peer")))
+	assert.True(t, IsTransient(&HTTPError{StatusCode: http.StatusBadGateway}))
+	assert.True(t, IsTransient(&HTTPError{StatusCode: http.StatusGatewayTimeout}))
+	assert.False(t, IsTransient(&HTTPError{StatusCode: http.StatusInternalServerError}))
+	assert.True(t, IsTransient(&Error{Code: "unknown error", StatusCode: http.StatusServiceUnavailable}))
+	assert.False(t, IsTransient(&Error{Code: "unknown error", StatusCode: http.StatusInternalServerError}))
+	assert.True(t, IsTransient(parseError(http.StatusBadGateway, &Response{Value: []byte(`{"error":"unknown error","message":"upstream"}`)})))
+	assert.True(t, IsTransient(&Error{Code: "unknown error", Message: "unknown error: chrome not reachable"}))
+	assert.False(t, IsTransient(&Error{Code: "unknown error", Message: "connection refused"}))
+	assert.False(t, IsTransient(errors.New("w3c: invalid response")))
+}
+
+func TestRetry_Transport(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte("<html>Service Unavailable</html>"))
+			return
+		}
+		_, _ = w.Write([]byte(`{"value": null}`))
+	}))
+	defer srv.Close()
+	policy := RetryPolicy{InitialBackoff: time.Millisecond}
+
+	// returns success after the 503 response of the proxy
+	client, err := httpclient.New(httpclient.WithBaseURL(srv.URL))
+	assert.Nil(t, err)
+	resp, err := Chain(WithClient(client), Retry(policy)).Do(context.Background(), http.MethodGet, "/status", nil)
+	assert.Nil(t, err)
+	assert.True(t, resp.Success())
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+
+	// returns success after the 503 response with the JSON error
+	atomic.StoreInt32(&requests, 0)
+	jsonSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"value": {"error": "unknown error", "message": "no node is available"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"value": null}`))
+	}))
+	defer jsonSrv.Close()
+	client, err = httpclient.New(httpclient.WithBaseURL(jsonSrv.URL))
+	assert.Nil(t, err)
+	resp, err = Chain(WithClient(client), Retry(policy)).Do(context.Background(), http.MethodGet, "/status", nil)
+	assert.Nil(t, err)
+	assert.True(t, resp.Success())
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+
+	// returns error after the refused connections
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+	client, err = httpclient.New(httpclient.WithBaseURL(closed.URL))
+	assert.Nil(t, err)
+	var retries int
+	policy.OnRetry = func(context.Context, int, error, time.Duration) { retries++ }
+	_, err = Chain(WithClient(client), Retry(policy)).Do(context.Background(), http.MethodPost, "/session", nil)
+	assert.True(t, IsConnectionRefused(err))
+	assert.Equal(t, DefaultRetryAttempts-1, retries)
+}
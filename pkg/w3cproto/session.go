@@ -93,6 +93,26 @@ func NewSession(ctx context.Context, request Doer, opts BrowserOptions) (Session
 	}, nil
 }
 
+// AttachSession creates a new instance of Session for the existing session with the id and the capabilities,
+// e.g. the session created before the restart of the local end. The session is alive if the remote end
+// returns the window handle. The session with the closed current window is alive too.
+func AttachSession(ctx context.Context, request Doer, id string, caps Capabilities) (Session, error) {
+	if len(id) == 0 {
+		return nil, ErrUnknownSession
+	}
+	if _, err := NewContext(request, id).GetWindowHandle(ctx); err != nil && !errors.Is(err, ErrNoSuchWindow) {
+		return nil, err
+	}
+	if caps == nil {
+		caps = make(Capabilities)
+	}
+	return &session{
+		id:      id,
+		cap:     caps,
+		request: request,
+	}, nil
+}
+
 func (s *session) ID() string {
 	return s.id
 }
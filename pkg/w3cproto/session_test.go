@@ -104,6 +104,42 @@ func TestNewSession(t *testing.T) {
 	assert.Nil(t, sess)
 }
 
+func TestAttachSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	doer := NewMockDoer(ctrl)
+	caps := Capabilities{CapabilityBrowserName: "chrome"}
+
+	// returns success
+	doer.EXPECT().Do(ctx, http.MethodGet, "/session/123/window", nil).Times(1).Return(
+		&Response{Value: []byte(`"CDwindow-1"`)}, nil)
+	sess, err := AttachSession(ctx, doer, "123", caps)
+	assert.Nil(t, err)
+	assert.Equal(t, "123", sess.ID())
+	assert.Equal(t, caps, sess.Capabilities())
+
+	// returns success if the current window is closed
+	doer.EXPECT().Do(ctx, http.MethodGet, "/session/123/window", nil).Times(1).Return(
+		nil, &Error{Code: "no such window", Message: "target window already closed"})
+	sess, err = AttachSession(ctx, doer, "123", nil)
+	assert.Nil(t, err)
+	assert.NotNil(t, sess.Capabilities())
+
+	// returns error if the session is not alive
+	doer.EXPECT().Do(ctx, http.MethodGet, "/session/123/window", nil).Times(1).Return(
+		nil, &Error{Code: "invalid session id", Message: "invalid session id"})
+	sess, err = AttachSession(ctx, doer, "123", caps)
+	assert.True(t, errors.Is(err, ErrInvalidSessionID))
+	assert.Nil(t, sess)
+
+	// returns error if the id is empty
+	sess, err = AttachSession(ctx, doer, "", caps)
+	assert.Equal(t, ErrUnknownSession, err)
+	assert.Nil(t, sess)
+}
+
 func TestSession_Delete(t *testing.T) {
 	ctrl := gomock.NewController(t)
 
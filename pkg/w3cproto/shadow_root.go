@@ -0,0 +1,101 @@
+package w3cproto
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// ShadowRootIdentifier the shadow root identifier is the string constant.
+const ShadowRootIdentifier = "shadow-6066-11e4-a52e-4f735466cecf"
+
+// ShadowRoot represents the shadow root of the element. The shadow root is a search context
+// for the elements hidden behind the shadow DOM boundary.
+type ShadowRoot interface {
+
+	// ID returns the identifier of the shadow root.
+	ID() string
+
+	// FindOne finds an element inside the shadow root.
+	FindOne(ctx context.Context, by FindElementStrategy, value string) (WebElement, error)
+
+	// Find finds multiple elements inside the shadow root.
+	Find(ctx context.Context, by FindElementStrategy, value string) ([]WebElement, error)
+}
+
+type shadowResp map[string]string
+
+func (sr shadowResp) ID() (s string, ok bool) {
+	s, ok = sr[ShadowRootIdentifier]
+	return
+}
+
+type shadowRoot struct {
+	rid     string
+	sid     string
+	request Doer
+}
+
+func (s shadowRoot) ID() string {
+	return s.rid
+}
+
+// FindOne finds an element inside the shadow root.
+func (s shadowRoot) FindOne(ctx context.Context, by FindElementStrategy, value string) (WebElement, error) {
+	if len(value) == 0 {
+		return nil, ErrInvalidArgument
+	}
+	p := Params{
+		"using": by,
+		"value": value,
+	}
+	resp, err := s.request.Do(ctx, http.MethodPost, "/session/"+s.sid+"/shadow/"+s.rid+"/element", p)
+	if err != nil {
+		return nil, err
+	}
+	var er elemResp
+	if err := json.Unmarshal(resp.Value, &er); err != nil {
+		return nil, err
+	}
+	id, ok := er.ID()
+	if !ok {
+		return nil, ErrNoSuchElement
+	}
+	return webElement{
+		wid:     id,
+		sid:     s.sid,
+		request: s.request,
+	}, nil
+}
+
+// Find finds multiple elements inside the shadow root.
+func (s shadowRoot) Find(ctx context.Context, by FindElementStrategy, value string) ([]WebElement, error) {
+	if len(value) == 0 {
+		return nil, ErrInvalidArgument
+	}
+	p := Params{
+		"using": by,
+		"value": value,
+	}
+	resp, err := s.request.Do(ctx, http.MethodPost, "/session/"+s.sid+"/shadow/"+s.rid+"/elements", p)
+	if err != nil {
+		return nil, err
+	}
+	var elms []elemResp
+	if err := json.Unmarshal(resp.Value, &elms); err != nil {
+		return nil, err
+	}
+	webElements := make([]WebElement, len(elms))
+	for i, wid := range elms {
+		id, ok := wid.ID()
+		if !ok {
+			return nil, ErrNoSuchElement
+		}
+		webElements[i] = webElement{
+			wid:     id,
+			sid:     s.sid,
+			request: s.request,
+		}
+	}
+	return webElements, nil
+}
@@ -0,0 +1,117 @@
+package w3cproto
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/golang/mock/gomock"
+)
+
+const testShadowRootID = "5ab2dbd8-e3d7-4e6a-9cfb-6bc0d6f2b1a4"
+
+func newShadowRoot(t *testing.T, sessID string) (ShadowRoot, *MockDoer, func()) {
+	ctrl := gomock.NewController(t)
+	cli := NewMockDoer(ctrl)
+	cx := shadowRoot{
+		sid:     sessID,
+		rid:     testShadowRootID,
+		request: cli,
+	}
+	return cx, cli, func() {
+		ctrl.Finish()
+	}
+}
+
+func TestShadowRoot_FindOne(t *testing.T) {
+	root, cli, done := newShadowRoot(t, "123")
+	defer done()
+
+	ctx := context.Background()
+
+	// returns success
+	cli.EXPECT().Do(ctx, http.MethodPost, "/session/123/shadow/"+testShadowRootID+"/element", gomock.Any()).Times(1).Return(&Response{
+		Value: []byte(`{"element-6066-11e4-a52e-4f735466cecf":"` + testWebElementID + `"}`),
+	}, nil).Do(func(_ context.Context, _ string, _ string, p Params) {
+		assert.Equal(t, ByCSSSelector, p["using"])
+		assert.Equal(t, "button", p["value"])
+	})
+	elem, err := root.FindOne(ctx, ByCSSSelector, "button")
+	assert.Nil(t, err)
+	assert.Equal(t, testWebElementID, elem.ID())
+
+	// returns error
+	cli.EXPECT().Do(ctx, http.MethodPost, "/session/123/shadow/"+testShadowRootID+"/element", gomock.Any()).Times(1).Return(nil, elementsErr)
+	elem, err = root.FindOne(ctx, ByCSSSelector, "button")
+	assert.Equal(t, elementsErr, err)
+	assert.Nil(t, elem)
+
+	// returns error no such element
+	cli.EXPECT().Do(ctx, http.MethodPost, "/session/123/shadow/"+testShadowRootID+"/element", gomock.Any()).Times(1).Return(&Response{
+		Value: []byte(`{"element":"` + testWebElementID + `"}`),
+	}, nil)
+	elem, err = root.FindOne(ctx, ByCSSSelector, "button")
+	assert.Equal(t, ErrNoSuchElement, err)
+	assert.Nil(t, elem)
+
+	// returns error invalid arguments
+	elem, err = root.FindOne(ctx, ByCSSSelector, "")
+	assert.Equal(t, ErrInvalidArgument, err)
+	assert.Nil(t, elem)
+
+	// returns error unmarshal JSON
+	cli.EXPECT().Do(ctx, http.MethodPost, "/session/123/shadow/"+testShadowRootID+"/element", gomock.Any()).Times(1).Return(&Response{
+		Value: []byte(`{element"}`),
+	}, nil)
+	elem, err = root.FindOne(ctx, ByCSSSelector, "button")
+	assert.Error(t, err)
+	assert.Nil(t, elem)
+}
+
+func TestShadowRoot_Find(t *testing.T) {
+	root, cli, done := newShadowRoot(t, "123")
+	defer done()
+
+	ctx := context.Background()
+
+	// returns success
+	cli.EXPECT().Do(ctx, http.MethodPost, "/session/123/shadow/"+testShadowRootID+"/elements", gomock.Any()).Times(1).Return(&Response{
+		Value: []byte(`[{"element-6066-11e4-a52e-4f735466cecf":"` + testWebElementID + `"}]`),
+	}, nil).Do(func(_ context.Context, _ string, _ string, p Params) {
+		assert.Equal(t, ByTagName, p["using"])
+		assert.Equal(t, "input", p["value"])
+	})
+	elems, err := root.Find(ctx, ByTagName, "input")
+	assert.Nil(t, err)
+	assert.Len(t, elems, 1)
+	assert.Equal(t, testWebElementID, elems[0].ID())
+
+	// returns error
+	cli.EXPECT().Do(ctx, http.MethodPost, "/session/123/shadow/"+testShadowRootID+"/elements", gomock.Any()).Times(1).Return(nil, elementsErr)
+	elems, err = root.Find(ctx, ByTagName, "input")
+	assert.Equal(t, elementsErr, err)
+	assert.Nil(t, elems)
+
+	// returns error no such element
+	cli.EXPECT().Do(ctx, http.MethodPost, "/session/123/shadow/"+testShadowRootID+"/elements", gomock.Any()).Times(1).Return(&Response{
+		Value: []byte(`[{"element":"` + testWebElementID + `"}]`),
+	}, nil)
+	elems, err = root.Find(ctx, ByTagName, "input")
+	assert.Equal(t, ErrNoSuchElement, err)
+	assert.Nil(t, elems)
+
+	// returns error invalid arguments
+	elems, err = root.Find(ctx, ByTagName, "")
+	assert.Equal(t, ErrInvalidArgument, err)
+	assert.Nil(t, elems)
+
+	// returns error unmarshal JSON
+	cli.EXPECT().Do(ctx, http.MethodPost, "/session/123/shadow/"+testShadowRootID+"/elements", gomock.Any()).Times(1).Return(&Response{
+		Value: []byte(`[{element"}]`),
+	}, nil)
+	elems, err = root.Find(ctx, ByTagName, "input")
+	assert.Error(t, err)
+	assert.Nil(t, elems)
+}
@@ -64,6 +64,40 @@ var statusCode = map[int]string{
 	MoveTargetOutOfBoundsStatusCode:      "Target provided for a move action is out of bounds.",
 }
 
+// legacyStatusCode maps the JSON Wire Protocol statuses onto the WebDriver error codes.
+var legacyStatusCode = map[int]ErrorCode{
+	NoSuchDriverStatusCode:               ErrInvalidSessionID,
+	NoSuchElementStatusCode:              ErrNoSuchElement,
+	NoSuchFrameStatusCode:                ErrNoSuchFrame,
+	UnknownCommandStatusCode:             ErrUnknownCommand,
+	StaleElementReferenceStatusCode:      ErrStaleElementReference,
+	ElementNotVisibleStatusCode:          ErrElementNotInteractable,
+	InvalidElementStateStatusCode:        ErrInvalidElementState,
+	UnknownErrorStatusCode:               ErrUnknownError,
+	ElementIsNotSelectableStatusCode:     ErrInvalidElementState,
+	JavaScriptErrorStatusCode:            ErrJavaScriptError,
+	XPathLookupErrorStatusCode:           ErrInvalidSelector,
+	TimeoutStatusCode:                    ErrTimeout,
+	NoSuchWindowStatusCode:               ErrNoSuchWindow,
+	InvalidCookieDomainStatusCode:        ErrInvalidCookieDomain,
+	UnableToSetCookieStatusCode:          ErrUnableToSetCookie,
+	UnexpectedAlertOpenStatusCode:        ErrUnexpectedAlertOpen,
+	NoAlertOpenErrorStatusCode:           ErrNoSuchAlert,
+	ScriptTimeoutStatusCode:              ErrScriptTimeout,
+	InvalidElementCoordinatesStatusCode:  ErrInvalidArgument,
+	IMENotAvailableStatusCode:            ErrUnsupportedOperation,
+	IMEEngineActivationFailedStatusCode:  ErrUnknownError,
+	InvalidSelectorStatusCode:            ErrInvalidSelector,
+	SessionNotCreatedExceptionStatusCode: ErrSessionNotCreated,
+	MoveTargetOutOfBoundsStatusCode:      ErrMoveTargetOutOfBounds,
+}
+
+// StatusErrorCode returns the WebDriver error code of the JSON Wire Protocol status.
+func StatusErrorCode(sc int) (code ErrorCode, ok bool) {
+	code, ok = legacyStatusCode[sc]
+	return
+}
+
 func StatusText(sc int) string {
 	msg, ok := statusCode[sc]
 	if !ok {
@@ -63,9 +63,15 @@ func (c *transport) handleResponse(r *http.Response) (resp *Response, err error)
 		return nil, err
 	}
 	if len(buf) == 0 {
+		if r.StatusCode >= 400 {
+			return nil, &HTTPError{StatusCode: r.StatusCode}
+		}
 		return nil, ErrEmptyResponse
 	}
 	if err := json.Unmarshal(buf, &resp); err != nil {
+		if r.StatusCode >= 400 {
+			return nil, &HTTPError{StatusCode: r.StatusCode, Body: string(buf)}
+		}
 		return nil, fmt.Errorf("w3c: %v, %s", err, string(buf))
 	}
 	if r.StatusCode >= 400 || resp.Status != 0 {
@@ -90,8 +96,21 @@ func (c *transport) Do(ctx context.Context, method string, path string, p Params
 	return resp, err
 }
 
+// headers returns the default headers merged with the headers stored in the context.
+func (c *transport) headers(ctx context.Context) http.Header {
+	extra := HeadersFromContext(ctx)
+	if len(extra) == 0 {
+		return c.Headers
+	}
+	headers := c.Headers.Clone()
+	for key, values := range extra {
+		headers[key] = values
+	}
+	return headers
+}
+
 func (c *transport) deleteRequest(ctx context.Context, path string) (resp *Response, err error) {
-	httpResp, err := c.client.Delete(ctx, path, c.Headers)
+	httpResp, err := c.client.Delete(ctx, path, c.headers(ctx))
 	if err != nil {
 		return nil, err
 	}
@@ -99,7 +118,7 @@ func (c *transport) deleteRequest(ctx context.Context, path string) (resp *Respo
 }
 
 func (c *transport) getRequest(ctx context.Context, path string) (resp *Response, err error) {
-	httpResp, err := c.client.Get(ctx, path, c.Headers)
+	httpResp, err := c.client.Get(ctx, path, c.headers(ctx))
 	if err != nil {
 		return nil, err
 	}
@@ -114,7 +133,7 @@ func (c *transport) postRequest(ctx context.Context, path string, p Params) (res
 	if err != nil {
 		return nil, err
 	}
-	httpResp, err := c.client.Post(ctx, path, bytes.NewReader(payload), c.Headers)
+	httpResp, err := c.client.Post(ctx, path, bytes.NewReader(payload), c.headers(ctx))
 	if err != nil {
 		return nil, err
 	}
@@ -131,6 +150,16 @@ func (r Response) Success() bool {
 	return string(r.Value) == "null"
 }
 
+// stringValue decodes the JSON string value. Returns the raw value
+// if it is not a JSON string.
+func stringValue(value json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(value, &s); err != nil {
+		return string(value)
+	}
+	return s
+}
+
 func httpStatusCode(code int) (s string) {
 	switch code {
 	case 200, 400:
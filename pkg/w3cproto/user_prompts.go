@@ -62,7 +62,7 @@ func (a *alert) Text(ctx context.Context) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	return string(resp.Value), nil
+	return stringValue(resp.Value), nil
 }
 
 func (a *alert) SetText(ctx context.Context, text string) error {
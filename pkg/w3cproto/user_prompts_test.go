@@ -93,6 +93,15 @@ func TestAlert_Text(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, wantMessage, haveMessage)
 
+	// returns success (JSON string)
+	cli.EXPECT().Do(ctx, http.MethodGet, "/session/123/alert/text", nil).Return(
+		&Response{
+			Value: []byte(`"` + wantMessage + `"`),
+		}, nil)
+	haveMessage, err = alert.Text(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, wantMessage, haveMessage)
+
 	// returns error
 	cli.EXPECT().Do(ctx, http.MethodGet, "/session/123/alert/text", nil).Return(nil, alertErr)
 	haveMessage, err = alert.Text(ctx)
@@ -0,0 +1,336 @@
+package w3ctest
+
+import (
+	"html"
+	"sort"
+	"strings"
+
+	"github.com/mediabuyerbot/go-webdriver/pkg/w3cproto"
+)
+
+// Page is the document served for the URL.
+type Page struct {
+	URL   string
+	Title string
+
+	// Source is the page source. The source is rendered from the elements if empty.
+	Source string
+
+	// Body is the content of the document body.
+	Body []*Element
+}
+
+// Element is the element of the DOM fixture. The fixture is copied when the page
+// is loaded, so the changes made by the commands are lost when the page is reloaded.
+type Element struct {
+	Tag   string
+	Attrs Attrs
+
+	// Text is the text of the element preceding the children.
+	Text string
+
+	// Props are the DOM properties. The "value", "checked", "selected", "disabled",
+	// "tagName", "id" and "className" properties are provided by default.
+	Props map[string]interface{}
+
+	// CSS are the computed CSS values.
+	CSS map[string]string
+
+	// Hidden hides the element like the hidden attribute.
+	Hidden   bool
+	Disabled bool
+	Selected bool
+
+	// Rect is the bounding rectangle. The element is 100x20 at the origin if the rect is empty.
+	Rect w3cproto.Rect
+
+	Children []*Element
+
+	// Shadow is the content of the open shadow root attached to the element.
+	Shadow []*Element
+
+	// Frame is the document of the iframe element.
+	Frame *Page
+
+	// OnClick is called after the element is clicked.
+	OnClick func(s *Session)
+
+	parent   *Element
+	host     *Element
+	frameDoc *document
+	value    *string
+}
+
+// Attrs are the attributes of the element.
+type Attrs map[string]string
+
+// El returns the element with the tag, the attributes and the children.
+//
+//	w3ctest.El("form", w3ctest.Attrs{"id": "login"},
+//		w3ctest.El("input", w3ctest.Attrs{"name": "user"}),
+//	)
+func El(tag string, attrs Attrs, children ...*Element) *Element {
+	return &Element{Tag: tag, Attrs: attrs, Children: children}
+}
+
+// WithText sets the text of the element.
+func (e *Element) WithText(text string) *Element {
+	e.Text = text
+	return e
+}
+
+// document is the loaded page.
+type document struct {
+	page   *Page
+	url    string
+	root   *Element
+	body   *Element
+	active *Element
+}
+
+func loadPage(page *Page, url string) *document {
+	body := &Element{Tag: "body", Children: cloneElements(page.Body)}
+	root := &Element{Tag: "html", Children: []*Element{body}}
+	link(root, nil, nil)
+	doc := &document{page: page, url: url, root: root, body: body}
+	walk(root, func(e *Element) {
+		if e.Frame != nil {
+			e.frameDoc = loadPage(e.Frame, e.Frame.URL)
+		}
+	})
+	return doc
+}
+
+func cloneElements(elems []*Element) []*Element {
+	if elems == nil {
+		return nil
+	}
+	clones := make([]*Element, len(elems))
+	for i, e := range elems {
+		c := *e
+		c.Attrs = Attrs(cloneStrings(e.Attrs))
+		c.CSS = cloneStrings(e.CSS)
+		if e.Props != nil {
+			c.Props = make(map[string]interface{}, len(e.Props))
+			for k, v := range e.Props {
+				c.Props[k] = v
+			}
+		}
+		c.Children = cloneElements(e.Children)
+		c.Shadow = cloneElements(e.Shadow)
+		c.value = nil
+		clones[i] = &c
+	}
+	return clones
+}
+
+func cloneStrings(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	c := make(map[string]string, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
+
+func link(e *Element, parent *Element, host *Element) {
+	e.parent, e.host = parent, host
+	for _, child := range e.Children {
+		link(child, e, nil)
+	}
+	for _, child := range e.Shadow {
+		link(child, nil, e)
+	}
+}
+
+// walk visits the element and its descendants in the document order, the shadow trees are not visited.
+func walk(e *Element, fn func(*Element)) {
+	fn(e)
+	for _, child := range e.Children {
+		walk(child, fn)
+	}
+}
+
+// contains reports whether the element is the descendant of the document.
+func (d *document) contains(e *Element) bool {
+	for e != nil {
+		if e == d.root {
+			return true
+		}
+		if e.parent == nil {
+			e = e.host
+		} else {
+			e = e.parent
+		}
+	}
+	return false
+}
+
+func (e *Element) attr(name string) (string, bool) {
+	if name == "value" && e.value != nil {
+		return *e.value, true
+	}
+	v, ok := e.Attrs[name]
+	return v, ok
+}
+
+func (e *Element) tagName() string {
+	return strings.ToLower(e.Tag)
+}
+
+func (e *Element) inputValue() string {
+	if e.value != nil {
+		return *e.value
+	}
+	if e.tagName() == "textarea" {
+		return e.Text
+	}
+	return e.Attrs["value"]
+}
+
+func (e *Element) setValue(v string) {
+	e.value = &v
+}
+
+func (e *Element) isEditable() bool {
+	switch e.tagName() {
+	case "textarea":
+		return true
+	case "input":
+		switch strings.ToLower(e.Attrs["type"]) {
+		case "", "text", "search", "email", "password", "tel", "url", "number", "date", "time":
+			return true
+		}
+	}
+	_, ok := e.Attrs["contenteditable"]
+	return ok
+}
+
+func (e *Element) isCheckable() bool {
+	if e.tagName() == "option" {
+		return true
+	}
+	if e.tagName() != "input" {
+		return false
+	}
+	t := strings.ToLower(e.Attrs["type"])
+	return t == "checkbox" || t == "radio"
+}
+
+// hidden reports whether the element is not rendered.
+func (e *Element) hidden() bool {
+	_, ok := e.Attrs["hidden"]
+	return ok || e.Hidden || e.CSS["display"] == "none"
+}
+
+// isDisplayed reports whether the element and its ancestors are not hidden.
+func (e *Element) isDisplayed() bool {
+	for el := e; el != nil; {
+		if el.hidden() || el.CSS["visibility"] == "hidden" {
+			return false
+		}
+		if el.tagName() == "input" && strings.EqualFold(el.Attrs["type"], "hidden") {
+			return false
+		}
+		if el.parent == nil {
+			el = el.host
+		} else {
+			el = el.parent
+		}
+	}
+	return true
+}
+
+// visibleText returns the text of the displayed element and its displayed descendants
+// with the whitespace collapsed.
+func (e *Element) visibleText() string {
+	if !e.isDisplayed() {
+		return ""
+	}
+	var parts []string
+	var collect func(*Element)
+	collect = func(el *Element) {
+		if el.hidden() {
+			return
+		}
+		parts = append(parts, el.Text)
+		for _, child := range el.Children {
+			collect(child)
+		}
+	}
+	collect(e)
+	return strings.Join(strings.Fields(strings.Join(parts, " ")), " ")
+}
+
+func (e *Element) property(name string) (interface{}, bool) {
+	if v, ok := e.Props[name]; ok {
+		return v, true
+	}
+	switch name {
+	case "value":
+		return e.inputValue(), true
+	case "checked", "selected":
+		return e.Selected, true
+	case "disabled":
+		return e.Disabled, true
+	case "tagName":
+		return strings.ToUpper(e.Tag), true
+	case "id":
+		return e.Attrs["id"], true
+	case "className":
+		return e.Attrs["class"], true
+	case "textContent", "innerText":
+		return e.visibleText(), true
+	}
+	return nil, false
+}
+
+func (e *Element) rect() w3cproto.Rect {
+	if e.Rect == (w3cproto.Rect{}) {
+		return w3cproto.Rect{Width: 100, Height: 20}
+	}
+	return e.Rect
+}
+
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true,
+	"input": true, "link": true, "meta": true, "source": true, "track": true, "wbr": true,
+}
+
+// source renders the document to HTML.
+func (d *document) source() string {
+	if len(d.page.Source) > 0 {
+		return d.page.Source
+	}
+	var b strings.Builder
+	b.WriteString("<html><head><title>")
+	b.WriteString(html.EscapeString(d.page.Title))
+	b.WriteString("</title></head>")
+	render(&b, d.body)
+	b.WriteString("</html>")
+	return b.String()
+}
+
+func render(b *strings.Builder, e *Element) {
+	tag := e.tagName()
+	b.WriteString("<" + tag)
+	names := make([]string, 0, len(e.Attrs))
+	for name := range e.Attrs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		b.WriteString(" " + name + `="` + html.EscapeString(e.Attrs[name]) + `"`)
+	}
+	b.WriteString(">")
+	if voidElements[tag] {
+		return
+	}
+	b.WriteString(html.EscapeString(e.Text))
+	for _, child := range e.Children {
+		render(b, child)
+	}
+	b.WriteString("</" + tag + ">")
+}
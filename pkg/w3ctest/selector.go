@@ -0,0 +1,352 @@
+package w3ctest
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mediabuyerbot/go-webdriver/pkg/w3cproto"
+)
+
+// selector is the list of the complex selectors separated by the commas.
+type selector [][]compound
+
+// compound is the simple selectors of the element, e.g. input.large[name="user"],
+// and the combinator with the compound on the left.
+type compound struct {
+	tag        string
+	id         string
+	classes    []string
+	attrs      []attrSelector
+	combinator byte // ' ' descendant, '>' child, 0 the leftmost compound
+}
+
+type attrSelector struct {
+	name  string
+	op    string
+	value string
+}
+
+// parseSelector parses the subset of the CSS selectors: the type, universal, id, class and
+// attribute selectors combined with the descendant and child combinators.
+func parseSelector(s string) (selector, error) {
+	p := &selectorParser{s: s}
+	var sel selector
+	for {
+		complex, err := p.complex()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q: %v", w3cproto.ErrInvalidSelector, s, err)
+		}
+		sel = append(sel, complex)
+		p.spaces()
+		if p.eof() {
+			return sel, nil
+		}
+		if p.s[p.pos] != ',' {
+			return nil, fmt.Errorf("%w: %q: unexpected %q", w3cproto.ErrInvalidSelector, s, p.s[p.pos])
+		}
+		p.pos++
+	}
+}
+
+type selectorParser struct {
+	s   string
+	pos int
+}
+
+func (p *selectorParser) eof() bool {
+	return p.pos >= len(p.s)
+}
+
+func (p *selectorParser) spaces() bool {
+	start := p.pos
+	for !p.eof() && strings.IndexByte(" \t\n\r\f", p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+	return p.pos > start
+}
+
+func (p *selectorParser) complex() ([]compound, error) {
+	var (
+		complex    []compound
+		combinator byte
+	)
+	p.spaces()
+	for {
+		c, err := p.compound()
+		if err != nil {
+			return nil, err
+		}
+		c.combinator = combinator
+		complex = append(complex, c)
+
+		hasSpace := p.spaces()
+		if p.eof() || p.s[p.pos] == ',' {
+			return complex, nil
+		}
+		combinator = ' '
+		if p.s[p.pos] == '>' {
+			combinator = '>'
+			p.pos++
+			p.spaces()
+		} else if !hasSpace {
+			return nil, fmt.Errorf("unexpected %q", p.s[p.pos])
+		}
+	}
+}
+
+func (p *selectorParser) compound() (c compound, err error) {
+	start := p.pos
+	if !p.eof() && p.s[p.pos] == '*' {
+		p.pos++
+	} else if ident := p.ident(); len(ident) > 0 {
+		c.tag = strings.ToLower(ident)
+	}
+	for !p.eof() {
+		switch p.s[p.pos] {
+		case '#':
+			p.pos++
+			if c.id = p.ident(); len(c.id) == 0 {
+				return c, fmt.Errorf("expected id at %d", p.pos)
+			}
+		case '.':
+			p.pos++
+			class := p.ident()
+			if len(class) == 0 {
+				return c, fmt.Errorf("expected class name at %d", p.pos)
+			}
+			c.classes = append(c.classes, class)
+		case '[':
+			p.pos++
+			attr, err := p.attr()
+			if err != nil {
+				return c, err
+			}
+			c.attrs = append(c.attrs, attr)
+		case ':':
+			return c, fmt.Errorf("pseudo-classes are not supported")
+		default:
+			if p.pos == start {
+				return c, fmt.Errorf("unexpected %q", p.s[p.pos])
+			}
+			return c, nil
+		}
+	}
+	if p.pos == start {
+		return c, fmt.Errorf("empty selector")
+	}
+	return c, nil
+}
+
+func (p *selectorParser) attr() (a attrSelector, err error) {
+	p.spaces()
+	if a.name = strings.ToLower(p.ident()); len(a.name) == 0 {
+		return a, fmt.Errorf("expected attribute name at %d", p.pos)
+	}
+	p.spaces()
+	if p.eof() {
+		return a, fmt.Errorf("unterminated attribute selector")
+	}
+	if p.s[p.pos] == ']' {
+		p.pos++
+		return a, nil
+	}
+	for _, op := range []string{"=", "~=", "|=", "^=", "$=", "*="} {
+		if strings.HasPrefix(p.s[p.pos:], op) {
+			a.op = op
+			p.pos += len(op)
+			break
+		}
+	}
+	if len(a.op) == 0 {
+		return a, fmt.Errorf("unexpected %q", p.s[p.pos])
+	}
+	p.spaces()
+	if !p.eof() && (p.s[p.pos] == '"' || p.s[p.pos] == '\'') {
+		if a.value, err = p.quoted(); err != nil {
+			return a, err
+		}
+	} else {
+		a.value = p.ident()
+	}
+	p.spaces()
+	if p.eof() || p.s[p.pos] != ']' {
+		return a, fmt.Errorf("unterminated attribute selector")
+	}
+	p.pos++
+	return a, nil
+}
+
+func (p *selectorParser) ident() string {
+	var b strings.Builder
+	for !p.eof() {
+		ch := p.s[p.pos]
+		switch {
+		case ch == '\\':
+			b.WriteString(p.escape())
+		case ch == '-' || ch == '_' || ch >= 0x80 ||
+			(ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') || (ch >= '0' && ch <= '9'):
+			b.WriteByte(ch)
+			p.pos++
+		default:
+			return b.String()
+		}
+	}
+	return b.String()
+}
+
+func (p *selectorParser) quoted() (string, error) {
+	quote := p.s[p.pos]
+	p.pos++
+	var b strings.Builder
+	for !p.eof() {
+		ch := p.s[p.pos]
+		switch ch {
+		case quote:
+			p.pos++
+			return b.String(), nil
+		case '\\':
+			b.WriteString(p.escape())
+		default:
+			b.WriteByte(ch)
+			p.pos++
+		}
+	}
+	return "", fmt.Errorf("unterminated string")
+}
+
+// escape decodes the escape sequence: the backslash followed by up to 6 hex digits
+// and an optional space, or by any other character.
+func (p *selectorParser) escape() string {
+	p.pos++ // backslash
+	start := p.pos
+	for p.pos < len(p.s) && p.pos-start < 6 && strings.IndexByte("0123456789abcdefABCDEF", p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+	if p.pos > start {
+		code, _ := strconv.ParseUint(p.s[start:p.pos], 16, 32)
+		if !p.eof() && p.s[p.pos] == ' ' {
+			p.pos++
+		}
+		return string(rune(code))
+	}
+	if p.eof() {
+		return ""
+	}
+	p.pos++
+	return p.s[start:p.pos]
+}
+
+func (sel selector) match(e *Element) bool {
+	for _, complex := range sel {
+		if matchComplex(e, complex) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchComplex(e *Element, complex []compound) bool {
+	last := len(complex) - 1
+	if !complex[last].match(e) {
+		return false
+	}
+	if last == 0 {
+		return true
+	}
+	rest := complex[:last]
+	switch complex[last].combinator {
+	case '>':
+		return e.parent != nil && matchComplex(e.parent, rest)
+	default:
+		for p := e.parent; p != nil; p = p.parent {
+			if matchComplex(p, rest) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func (c compound) match(e *Element) bool {
+	if len(c.tag) > 0 && c.tag != e.tagName() {
+		return false
+	}
+	if len(c.id) > 0 && e.Attrs["id"] != c.id {
+		return false
+	}
+	classes := strings.Fields(e.Attrs["class"])
+	for _, class := range c.classes {
+		if !containsString(classes, class) {
+			return false
+		}
+	}
+	for _, a := range c.attrs {
+		if !a.match(e) {
+			return false
+		}
+	}
+	return true
+}
+
+func (a attrSelector) match(e *Element) bool {
+	v, ok := e.attr(a.name)
+	if !ok {
+		return false
+	}
+	switch a.op {
+	case "":
+		return true
+	case "=":
+		return v == a.value
+	case "~=":
+		return containsString(strings.Fields(v), a.value)
+	case "|=":
+		return v == a.value || strings.HasPrefix(v, a.value+"-")
+	case "^=":
+		return len(a.value) > 0 && strings.HasPrefix(v, a.value)
+	case "$=":
+		return len(a.value) > 0 && strings.HasSuffix(v, a.value)
+	case "*=":
+		return len(a.value) > 0 && strings.Contains(v, a.value)
+	}
+	return false
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// matcher returns the function matching the elements by the location strategy.
+// The XPath expressions are not supported.
+func matcher(using w3cproto.FindElementStrategy, value string) (func(*Element) bool, error) {
+	switch using {
+	case w3cproto.ByCSSSelector:
+		sel, err := parseSelector(value)
+		if err != nil {
+			return nil, err
+		}
+		return sel.match, nil
+	case w3cproto.ByTagName:
+		tag := strings.ToLower(value)
+		return func(e *Element) bool { return e.tagName() == tag }, nil
+	case w3cproto.ByID:
+		return func(e *Element) bool { return e.Attrs["id"] == value }, nil
+	case w3cproto.ByClassName:
+		return func(e *Element) bool { return containsString(strings.Fields(e.Attrs["class"]), value) }, nil
+	case w3cproto.ByName:
+		return func(e *Element) bool { return e.Attrs["name"] == value }, nil
+	case w3cproto.ByLinkText:
+		return func(e *Element) bool { return e.tagName() == "a" && e.visibleText() == strings.TrimSpace(value) }, nil
+	case w3cproto.ByPartialLinkText:
+		return func(e *Element) bool { return e.tagName() == "a" && strings.Contains(e.visibleText(), value) }, nil
+	case w3cproto.ByXPATH:
+		return nil, fmt.Errorf("%w: xpath is not supported by w3ctest: %s", w3cproto.ErrInvalidSelector, value)
+	}
+	return nil, fmt.Errorf("%w: unknown location strategy %q", w3cproto.ErrInvalidArgument, using)
+}
@@ -0,0 +1,316 @@
+// Package w3ctest provides the in-process fake W3C WebDriver remote end for the tests.
+//
+// The server implements the endpoints used by the w3cproto package on top of the programmable
+// in-memory model: the pages with the DOM fixtures, the scripted responses and the injected faults.
+//
+//	srv := w3ctest.NewServer(&w3ctest.Page{
+//		URL:   "https://example.com/",
+//		Title: "Example",
+//		Body:  []*w3ctest.Element{w3ctest.El("a", w3ctest.Attrs{"href": "/next"}).WithText("Next")},
+//	})
+//	defer srv.Close()
+//	browser, err := webdriver.OpenRemoteBrowser(ctx, srv.URL, webdriver.ChromeOptions().Build())
+package w3ctest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/mediabuyerbot/go-webdriver/pkg/w3cproto"
+)
+
+// Command is the command received by the server.
+type Command struct {
+	Method string
+	Path   string
+
+	// SessionID is the id of the session, empty for the commands without a session.
+	SessionID string
+
+	Params map[string]interface{}
+}
+
+// HandlerFunc responds to the command with the value encoded to JSON or the error.
+// The *w3cproto.Error errors are sent as the protocol errors.
+type HandlerFunc func(s *Session, cmd *Command) (interface{}, error)
+
+// Fault makes the matching commands fail.
+type Fault struct {
+	// Method is the HTTP method of the commands, any method if empty.
+	Method string
+
+	// Path is the pattern of the command path, any path if empty. See Server.Handle.
+	Path string
+
+	// Code is the WebDriver error code, ErrUnknownError if empty and Status is zero.
+	Code    w3cproto.ErrorCode
+	Message string
+
+	// Status is the HTTP status sent with the non-JSON body if Code is empty,
+	// e.g. 502 Bad Gateway of a proxy.
+	Status int
+
+	// Times is the number of the commands that fail, all the commands if zero.
+	Times int
+}
+
+type handler struct {
+	method  string
+	pattern string
+	fn      HandlerFunc
+}
+
+// Server is the fake W3C remote end.
+type Server struct {
+	*httptest.Server
+
+	lock     sync.Mutex
+	pages    map[string]*Page
+	sessions map[string]*Session
+	handlers []handler
+	scripts  []ScriptFunc
+	faults   []*Fault
+	commands []Command
+	seq      int
+}
+
+// NewServer starts the server serving the pages.
+func NewServer(pages ...*Page) *Server {
+	s := &Server{
+		pages:    make(map[string]*Page),
+		sessions: make(map[string]*Session),
+	}
+	for _, page := range pages {
+		s.pages[page.URL] = page
+	}
+	s.Server = httptest.NewServer(s)
+	return s
+}
+
+// AddPage adds or replaces the page served for the page URL.
+func (s *Server) AddPage(page *Page) {
+	s.lock.Lock()
+	s.pages[page.URL] = page
+	s.lock.Unlock()
+}
+
+// Handle overrides the response to the commands matching the method and the path pattern.
+// The pattern is matched against the command path without the /session/{session id} prefix,
+// e.g. "/url" or "/element/*/text", where "*" matches any path segment. The commands without
+// a session are matched by the full path, e.g. "/status". The latest handler wins.
+func (s *Server) Handle(method string, pattern string, fn HandlerFunc) {
+	s.lock.Lock()
+	s.handlers = append(s.handlers, handler{method: method, pattern: pattern, fn: fn})
+	s.lock.Unlock()
+}
+
+// InjectFault makes the matching commands fail. The faults are checked before the handlers.
+func (s *Server) InjectFault(f Fault) {
+	s.lock.Lock()
+	fault := f
+	s.faults = append(s.faults, &fault)
+	s.lock.Unlock()
+}
+
+// Session returns the session by the id.
+func (s *Server) Session(id string) (*Session, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	sess, ok := s.sessions[id]
+	return sess, ok
+}
+
+// Commands returns the commands received by the server.
+func (s *Server) Commands() []Command {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]Command(nil), s.commands...)
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	cmd := &Command{Method: r.Method, Path: r.URL.Path}
+	if r.Method == http.MethodPost {
+		if err := json.NewDecoder(r.Body).Decode(&cmd.Params); err != nil {
+			writeError(w, fmt.Errorf("%w: %v", w3cproto.ErrInvalidArgument, err))
+			return
+		}
+	}
+	value, err := s.do(cmd)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"value": value})
+}
+
+func (s *Server) do(cmd *Command) (interface{}, error) {
+	segments := strings.Split(strings.Trim(cmd.Path, "/"), "/")
+	path := cmd.Path
+	if len(segments) >= 2 && segments[0] == "session" {
+		cmd.SessionID = segments[1]
+		path = strings.TrimPrefix(cmd.Path, "/session/"+cmd.SessionID)
+	}
+
+	s.lock.Lock()
+	s.commands = append(s.commands, *cmd)
+	if err := s.fault(cmd.Method, path); err != nil {
+		s.lock.Unlock()
+		return nil, err
+	}
+	sess := s.sessions[cmd.SessionID]
+	fn := s.handler(cmd.Method, path)
+	s.lock.Unlock()
+
+	if fn != nil {
+		return fn(sess, cmd)
+	}
+	switch {
+	case cmd.Method == http.MethodGet && cmd.Path == "/status":
+		return map[string]interface{}{"ready": true, "message": "w3ctest is ready"}, nil
+	case cmd.Method == http.MethodPost && cmd.Path == "/session":
+		return s.newSession(cmd.Params)
+	case len(cmd.SessionID) == 0:
+		return nil, newError(w3cproto.ErrUnknownCommand, "%s %s", cmd.Method, cmd.Path)
+	case sess == nil:
+		return nil, newError(w3cproto.ErrInvalidSessionID, "session %s does not exist", cmd.SessionID)
+	case cmd.Method == http.MethodDelete && len(path) == 0:
+		s.lock.Lock()
+		delete(s.sessions, cmd.SessionID)
+		s.lock.Unlock()
+		return nil, nil
+	}
+	return sess.do(cmd.Method, path, cmd.Params)
+}
+
+func (s *Server) fault(method string, path string) error {
+	for i, f := range s.faults {
+		if (len(f.Method) > 0 && f.Method != method) || (len(f.Path) > 0 && !matchPath(f.Path, path)) {
+			continue
+		}
+		if f.Times > 0 {
+			if f.Times--; f.Times == 0 {
+				s.faults = append(s.faults[:i:i], s.faults[i+1:]...)
+			}
+		}
+		if len(f.Code) == 0 && f.Status > 0 {
+			return &w3cproto.HTTPError{StatusCode: f.Status, Body: http.StatusText(f.Status)}
+		}
+		code := f.Code
+		if len(code) == 0 {
+			code = w3cproto.ErrUnknownError
+		}
+		return &w3cproto.Error{Code: code.String(), Message: f.Message}
+	}
+	return nil
+}
+
+func (s *Server) handler(method string, path string) HandlerFunc {
+	for i := len(s.handlers) - 1; i >= 0; i-- {
+		h := s.handlers[i]
+		if h.method == method && matchPath(h.pattern, path) {
+			return h.fn
+		}
+	}
+	return nil
+}
+
+func (s *Server) newSession(params map[string]interface{}) (interface{}, error) {
+	caps := w3cproto.Capabilities{
+		w3cproto.CapabilityBrowserName:             "w3ctest",
+		w3cproto.CapabilityBrowserVersion:          "1.0",
+		w3cproto.CapabilityPlatformName:            "any",
+		w3cproto.CapabilityUnhandledPromptBehavior: "dismiss and notify",
+		w3cproto.CapabilitySetWindowRect:           true,
+	}
+	if requested, ok := params["capabilities"].(map[string]interface{}); ok {
+		if always, ok := requested["alwaysMatch"].(map[string]interface{}); ok {
+			for k, v := range always {
+				caps[k] = v
+			}
+		}
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.seq++
+	sess := newSession(s, strconv.Itoa(s.seq), caps)
+	s.sessions[sess.id] = sess
+	return map[string]interface{}{"sessionId": sess.id, "capabilities": caps}, nil
+}
+
+// page returns the page served for the URL, the empty page if the URL is unknown.
+// The lock must be held.
+func (s *Server) page(url string) *Page {
+	if page, ok := s.pages[url]; ok {
+		return page
+	}
+	return &Page{URL: url}
+}
+
+// matchPath reports whether the path matches the pattern, "*" matches any path segment.
+func matchPath(pattern string, path string) bool {
+	a, b := strings.Split(pattern, "/"), strings.Split(path, "/")
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != "*" && a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func newError(code w3cproto.ErrorCode, format string, args ...interface{}) error {
+	return &w3cproto.Error{Code: code.String(), Message: fmt.Sprintf(format, args...)}
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	var httpErr *w3cproto.HTTPError
+	if errors.As(err, &httpErr) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(httpErr.StatusCode)
+		_, _ = w.Write([]byte(httpErr.Body))
+		return
+	}
+	var protoErr *w3cproto.Error
+	if !errors.As(err, &protoErr) {
+		code, ok := w3cproto.ErrorCodeOf(err)
+		if !ok {
+			code = w3cproto.ErrUnknownError
+		}
+		protoErr = &w3cproto.Error{Code: code.String(), Message: err.Error()}
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(errorStatus(w3cproto.ErrorCode(protoErr.Code)))
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"value": map[string]interface{}{
+		"error":      protoErr.Code,
+		"message":    protoErr.Message,
+		"stacktrace": protoErr.RawStacktrace,
+		"data":       protoErr.Data,
+	}})
+}
+
+// errorStatus returns the HTTP status of the error code defined by the specification.
+func errorStatus(code w3cproto.ErrorCode) int {
+	switch code {
+	case w3cproto.ErrElementClickIntercepted, w3cproto.ErrElementNotInteractable, w3cproto.ErrInsecureCertificate,
+		w3cproto.ErrInvalidArgument, w3cproto.ErrInvalidCookieDomain, w3cproto.ErrInvalidElementState,
+		w3cproto.ErrInvalidSelector:
+		return http.StatusBadRequest
+	case w3cproto.ErrInvalidSessionID, w3cproto.ErrNoSuchAlert, w3cproto.ErrNoSuchCookie, w3cproto.ErrNoSuchElement,
+		w3cproto.ErrNoSuchFrame, w3cproto.ErrNoSuchWindow, w3cproto.ErrNoSuchShadowRoot,
+		w3cproto.ErrStaleElementReference, w3cproto.ErrDetachedShadowRoot, w3cproto.ErrUnknownCommand:
+		return http.StatusNotFound
+	case w3cproto.ErrUnknownMethod:
+		return http.StatusMethodNotAllowed
+	}
+	return http.StatusInternalServerError
+}
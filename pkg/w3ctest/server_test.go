@@ -0,0 +1,267 @@
+package w3ctest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/mediabuyerbot/httpclient"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mediabuyerbot/go-webdriver/pkg/w3cproto"
+)
+
+var testPage = &Page{
+	URL:   "https://example.com/",
+	Title: "Example",
+	Body: []*Element{
+		El("form", Attrs{"id": "login", "class": "form large"},
+			El("input", Attrs{"name": "user", "value": "guest"}),
+			El("input", Attrs{"name": "remember", "type": "checkbox"}),
+			El("button", Attrs{"type": "submit"}).WithText("Log in"),
+		),
+		El("div", Attrs{"id": "hidden"}, El("span", nil).WithText("secret")),
+		El("a", Attrs{"href": "/next"}).WithText("Next page"),
+		El("iframe", Attrs{"name": "ads"}),
+	},
+}
+
+func init() {
+	testPage.Body[1].Hidden = true
+	testPage.Body[3].Frame = &Page{
+		URL:  "https://ads.example.com/",
+		Body: []*Element{El("p", Attrs{"class": "ad"}).WithText("Buy now")},
+	}
+}
+
+func newTestSession(t *testing.T, srv *Server) (w3cproto.Doer, w3cproto.Session) {
+	client, err := httpclient.New(httpclient.WithBaseURL(srv.URL))
+	assert.Nil(t, err)
+	doer := w3cproto.WithClient(client)
+	sess, err := w3cproto.NewSession(context.Background(), doer, w3cproto.NewBrowserOptions(nil, nil))
+	assert.Nil(t, err)
+	return doer, sess
+}
+
+func TestServer_Elements(t *testing.T) {
+	ctx := context.Background()
+	srv := NewServer(testPage)
+	defer srv.Close()
+	doer, sess := newTestSession(t, srv)
+	assert.Equal(t, "w3ctest", sess.Capabilities().GetString(w3cproto.CapabilityBrowserName))
+
+	nav := w3cproto.NewNavigation(doer, sess.ID())
+	assert.Nil(t, nav.NavigateTo(ctx, testPage.URL))
+	title, err := nav.GetTitle(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, "Example", title)
+
+	elements := w3cproto.NewElements(doer, sess.ID())
+	form, err := elements.FindOne(ctx, w3cproto.ByCSSSelector, "form.large#login")
+	assert.Nil(t, err)
+	inputs, err := form.Find(ctx, w3cproto.ByCSSSelector, "form > input[name]")
+	assert.Nil(t, err)
+	assert.Len(t, inputs, 2)
+
+	// types into the input
+	assert.Nil(t, inputs[0].Clear(ctx))
+	assert.Nil(t, inputs[0].SendKeys(ctx, "admin"))
+	value, err := inputs[0].GetProperty(ctx, "value")
+	assert.Nil(t, err)
+	assert.Equal(t, "admin", value)
+
+	// toggles the checkbox
+	assert.Nil(t, inputs[1].Click(ctx))
+	selected, err := inputs[1].IsSelected(ctx)
+	assert.Nil(t, err)
+	assert.True(t, selected)
+
+	// returns error if the element is hidden
+	span, err := elements.FindOne(ctx, w3cproto.ByTagName, "span")
+	assert.Nil(t, err)
+	text, err := span.Text(ctx)
+	assert.Nil(t, err)
+	assert.Empty(t, text)
+	assert.True(t, errors.Is(span.Click(ctx), w3cproto.ErrElementNotInteractable))
+
+	// returns error if the element is not found
+	_, err = elements.FindOne(ctx, w3cproto.ByCSSSelector, "#logout")
+	assert.True(t, errors.Is(err, w3cproto.ErrNoSuchElement))
+	_, err = elements.FindOne(ctx, w3cproto.ByCSSSelector, "a:hover")
+	assert.True(t, errors.Is(err, w3cproto.ErrInvalidSelector))
+
+	// navigates by the link and makes the references stale
+	link, err := elements.FindOne(ctx, w3cproto.ByLinkText, "Next page")
+	assert.Nil(t, err)
+	assert.Nil(t, link.Click(ctx))
+	url, err := nav.GetCurrentURL(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, "https://example.com/next", url)
+	_, err = form.Text(ctx)
+	assert.True(t, errors.Is(err, w3cproto.ErrStaleElementReference))
+	assert.Nil(t, nav.Back(ctx))
+	url, err = nav.GetCurrentURL(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, testPage.URL, url)
+}
+
+func TestServer_Frames(t *testing.T) {
+	ctx := context.Background()
+	srv := NewServer(testPage)
+	defer srv.Close()
+	doer, sess := newTestSession(t, srv)
+	assert.Nil(t, w3cproto.NewNavigation(doer, sess.ID()).NavigateTo(ctx, testPage.URL))
+
+	browsing := w3cproto.NewContext(doer, sess.ID())
+	elements := w3cproto.NewElements(doer, sess.ID())
+	assert.Nil(t, browsing.SwitchToFrame(ctx, "ads"))
+	ad, err := elements.FindOne(ctx, w3cproto.ByClassName, "ad")
+	assert.Nil(t, err)
+	text, err := ad.Text(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, "Buy now", text)
+
+	assert.Nil(t, browsing.SwitchToParentFrame(ctx))
+	_, err = ad.Text(ctx)
+	assert.True(t, errors.Is(err, w3cproto.ErrStaleElementReference))
+	assert.True(t, errors.Is(browsing.SwitchToFrame(ctx, "video"), w3cproto.ErrNoSuchFrame))
+}
+
+func TestServer_Cookies(t *testing.T) {
+	ctx := context.Background()
+	srv := NewServer(testPage)
+	defer srv.Close()
+	doer, sess := newTestSession(t, srv)
+	cookies := w3cproto.NewCookies(doer, sess.ID())
+
+	// returns error if the document is cookie-averse
+	err := cookies.Add(ctx, w3cproto.Cookie{"name": "token", "value": "abc"})
+	assert.True(t, errors.Is(err, w3cproto.ErrInvalidCookieDomain))
+
+	assert.Nil(t, w3cproto.NewNavigation(doer, sess.ID()).NavigateTo(ctx, testPage.URL))
+	assert.Nil(t, cookies.Add(ctx, w3cproto.Cookie{"name": "token", "value": "abc"}))
+	cookie, err := cookies.Get(ctx, "token")
+	assert.Nil(t, err)
+	assert.Equal(t, "abc", cookie.Value())
+	assert.Equal(t, "example.com", cookie.Domain())
+
+	assert.Nil(t, cookies.Delete(ctx, "token"))
+	_, err = cookies.Get(ctx, "token")
+	assert.True(t, errors.Is(err, w3cproto.ErrNoSuchCookie))
+}
+
+func TestServer_Windows(t *testing.T) {
+	ctx := context.Background()
+	srv := NewServer(testPage)
+	defer srv.Close()
+	doer, sess := newTestSession(t, srv)
+	browsing := w3cproto.NewContext(doer, sess.ID())
+
+	first, err := browsing.GetWindowHandle(ctx)
+	assert.Nil(t, err)
+	tab, err := browsing.NewWindow(ctx, w3cproto.Tab)
+	assert.Nil(t, err)
+	assert.Nil(t, browsing.SwitchToWindow(ctx, tab.Handle))
+	handles, err := browsing.CloseWindow(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, []w3cproto.WindowHandle{first}, handles)
+
+	// the window commands fail until another window is selected
+	_, err = browsing.GetWindowHandle(ctx)
+	assert.True(t, errors.Is(err, w3cproto.ErrNoSuchWindow))
+	_, err = browsing.NewWindow(ctx, w3cproto.Tab)
+	assert.True(t, errors.Is(err, w3cproto.ErrNoSuchWindow))
+
+	assert.Nil(t, browsing.SwitchToWindow(ctx, first))
+	_, err = browsing.NewWindow(ctx, w3cproto.Tab)
+	assert.Nil(t, err)
+}
+
+func TestServer_Alerts(t *testing.T) {
+	ctx := context.Background()
+	srv := NewServer(testPage)
+	defer srv.Close()
+	doer, sess := newTestSession(t, srv)
+	s, ok := srv.Session(sess.ID())
+	assert.True(t, ok)
+
+	s.OpenAlert("Are you sure?")
+	alert := w3cproto.NewAlert(doer, sess.ID())
+	text, err := alert.Text(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, "Are you sure?", text)
+
+	// the prompt is dismissed and reported
+	_, err = w3cproto.NewNavigation(doer, sess.ID()).GetTitle(ctx)
+	assert.True(t, errors.Is(err, w3cproto.ErrUnexpectedAlertOpen))
+	assert.True(t, errors.Is(alert.Dismiss(ctx), w3cproto.ErrNoSuchAlert))
+}
+
+func TestServer_ScriptsAndHandlers(t *testing.T) {
+	ctx := context.Background()
+	srv := NewServer(testPage)
+	defer srv.Close()
+	doer, sess := newTestSession(t, srv)
+	doc := w3cproto.NewDocument(doer, sess.ID())
+
+	// returns error if the script is not handled
+	_, err := doc.ExecuteScript(ctx, "return 1", nil)
+	assert.True(t, errors.Is(err, w3cproto.ErrJavaScriptError))
+
+	srv.HandleScript(func(s *Session, script string, args []interface{}) (interface{}, bool, error) {
+		if script != "return arguments[0] + 1" {
+			return nil, false, nil
+		}
+		return args[0].(float64) + 1, true, nil
+	})
+	result, err := doc.ExecuteScript(ctx, "return arguments[0] + 1", []interface{}{1})
+	assert.Nil(t, err)
+	assert.Equal(t, "2", string(result))
+
+	srv.Handle(http.MethodGet, "/title", func(s *Session, cmd *Command) (interface{}, error) {
+		return "Handled " + s.ID(), nil
+	})
+	title, err := w3cproto.NewNavigation(doer, sess.ID()).GetTitle(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, "Handled "+sess.ID(), title)
+
+	// the faults are checked before the handlers
+	srv.InjectFault(Fault{Path: "/title", Code: w3cproto.ErrTimeout, Times: 1})
+	_, err = w3cproto.NewNavigation(doer, sess.ID()).GetTitle(ctx)
+	assert.True(t, errors.Is(err, w3cproto.ErrTimeout))
+	srv.InjectFault(Fault{Path: "/title", Status: http.StatusBadGateway, Times: 1})
+	_, err = w3cproto.NewNavigation(doer, sess.ID()).GetTitle(ctx)
+	var httpErr *w3cproto.HTTPError
+	assert.True(t, errors.As(err, &httpErr))
+	assert.Equal(t, http.StatusBadGateway, httpErr.StatusCode)
+	_, err = w3cproto.NewNavigation(doer, sess.ID()).GetTitle(ctx)
+	assert.Nil(t, err)
+
+	assert.Nil(t, sess.Delete(ctx))
+	_, err = w3cproto.NewNavigation(doer, sess.ID()).GetCurrentURL(ctx)
+	assert.True(t, errors.Is(err, w3cproto.ErrInvalidSessionID))
+	assert.Equal(t, "/session", srv.Commands()[0].Path)
+}
+
+func TestParseSelector(t *testing.T) {
+	elem := El("input", Attrs{"id": "a:b", "class": "x y", "lang": "en-US", "data-v": "foo bar"})
+	link(elem, El("form", Attrs{"class": "f"}), nil)
+	for _, sel := range []string{
+		"input", "*", "#a\\:b", ".x.y", "[lang|=en]", "[data-v~='bar']", "[data-v^=fo]",
+		`[data-v$="ar"]`, "[data-v*='o b']", "form input", "form > .x", ".none, input",
+	} {
+		s, err := parseSelector(sel)
+		assert.Nil(t, err, sel)
+		assert.True(t, s.match(elem), sel)
+	}
+	for _, sel := range []string{"div", ".z", "[lang=en]", "div > input"} {
+		s, err := parseSelector(sel)
+		assert.Nil(t, err, sel)
+		assert.False(t, s.match(elem), sel)
+	}
+	for _, sel := range []string{"", "input:checked", "[lang", "a >", "a,"} {
+		_, err := parseSelector(sel)
+		assert.True(t, errors.Is(err, w3cproto.ErrInvalidSelector), sel)
+	}
+}
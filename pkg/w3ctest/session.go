@@ -0,0 +1,1215 @@
+package w3ctest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/png"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mediabuyerbot/go-webdriver/pkg/w3cproto"
+)
+
+const blankURL = "about:blank"
+
+// ScriptFunc executes the script. The web element references in the arguments are replaced
+// with the *Element values, and the *Element values of the result are sent as the references.
+// The function returns false if it does not handle the script.
+type ScriptFunc func(s *Session, script string, args []interface{}) (result interface{}, ok bool, err error)
+
+// HandleScript adds the script handler. The latest handler is tried first.
+// The scripts not handled fail with the "javascript error".
+func (s *Server) HandleScript(fn ScriptFunc) {
+	s.lock.Lock()
+	s.scripts = append(s.scripts, fn)
+	s.lock.Unlock()
+}
+
+// Session is the state of the fake browser session.
+type Session struct {
+	server   *Server
+	id       string
+	caps     w3cproto.Capabilities
+	windows  []*window
+	current  *window
+	cookies  []w3cproto.Cookie
+	timeouts w3cproto.Timeout
+	alert    *string
+	prompt   *string
+	ids      map[*Element]string
+	elements map[string]*Element
+	shadows  map[string]*Element
+	seq      int
+}
+
+type window struct {
+	handle  string
+	history []string
+	pos     int
+	doc     *document
+	frames  []*document
+	rect    w3cproto.Rect
+}
+
+func newSession(server *Server, id string, caps w3cproto.Capabilities) *Session {
+	s := &Session{
+		server:   server,
+		id:       id,
+		caps:     caps,
+		timeouts: w3cproto.Timeout{PageLoad: 300000, Script: 30000},
+		ids:      make(map[*Element]string),
+		elements: make(map[string]*Element),
+		shadows:  make(map[string]*Element),
+	}
+	s.current = s.newWindow()
+	return s
+}
+
+// ID returns the session id.
+func (s *Session) ID() string {
+	return s.id
+}
+
+// Capabilities returns the capabilities of the session.
+func (s *Session) Capabilities() w3cproto.Capabilities {
+	return s.caps
+}
+
+// Navigate loads the page in the current window.
+func (s *Session) Navigate(url string) {
+	s.server.lock.Lock()
+	defer s.server.lock.Unlock()
+	if s.current != nil {
+		s.navigate(s.current, url)
+	}
+}
+
+// CurrentURL returns the URL of the current window.
+func (s *Session) CurrentURL() string {
+	s.server.lock.Lock()
+	defer s.server.lock.Unlock()
+	if s.current == nil {
+		return ""
+	}
+	return s.current.doc.url
+}
+
+// OpenAlert opens the user prompt with the text.
+func (s *Session) OpenAlert(text string) {
+	s.server.lock.Lock()
+	s.alert, s.prompt = &text, nil
+	s.server.lock.Unlock()
+}
+
+// Alert returns the text of the open user prompt.
+func (s *Session) Alert() (string, bool) {
+	s.server.lock.Lock()
+	defer s.server.lock.Unlock()
+	if s.alert == nil {
+		return "", false
+	}
+	return *s.alert, true
+}
+
+// PromptText returns the text sent to the last user prompt.
+func (s *Session) PromptText() (string, bool) {
+	s.server.lock.Lock()
+	defer s.server.lock.Unlock()
+	if s.prompt == nil {
+		return "", false
+	}
+	return *s.prompt, true
+}
+
+// Cookies returns all the cookies of the session.
+func (s *Session) Cookies() []w3cproto.Cookie {
+	s.server.lock.Lock()
+	defer s.server.lock.Unlock()
+	cookies := make([]w3cproto.Cookie, len(s.cookies))
+	for i, c := range s.cookies {
+		cookies[i] = copyCookie(c)
+	}
+	return cookies
+}
+
+// call is the command to the session.
+type call struct {
+	vars   []string
+	params map[string]interface{}
+	after  []func()
+}
+
+func (c *call) string(key string) string {
+	s, _ := c.params[key].(string)
+	return s
+}
+
+type route struct {
+	method string
+	path   string
+	fn     func(s *Session, c *call) (interface{}, error)
+
+	// ignorePrompt is set for the commands that are not blocked by the user prompts.
+	ignorePrompt bool
+}
+
+var routes = []route{
+	{method: http.MethodGet, path: "/timeouts", fn: (*Session).getTimeouts, ignorePrompt: true},
+	{method: http.MethodPost, path: "/timeouts", fn: (*Session).setTimeouts, ignorePrompt: true},
+	{method: http.MethodPost, path: "/url", fn: (*Session).navigateTo},
+	{method: http.MethodGet, path: "/url", fn: (*Session).currentURL},
+	{method: http.MethodPost, path: "/back", fn: (*Session).back},
+	{method: http.MethodPost, path: "/forward", fn: (*Session).forward},
+	{method: http.MethodPost, path: "/refresh", fn: (*Session).refresh},
+	{method: http.MethodGet, path: "/title", fn: (*Session).title},
+	{method: http.MethodGet, path: "/window", fn: (*Session).windowHandle, ignorePrompt: true},
+	{method: http.MethodDelete, path: "/window", fn: (*Session).closeWindow},
+	{method: http.MethodPost, path: "/window", fn: (*Session).switchToWindow, ignorePrompt: true},
+	{method: http.MethodGet, path: "/window/handles", fn: (*Session).windowHandles, ignorePrompt: true},
+	{method: http.MethodPost, path: "/window/new", fn: (*Session).openWindow},
+	{method: http.MethodPost, path: "/frame", fn: (*Session).switchToFrame},
+	{method: http.MethodPost, path: "/frame/parent", fn: (*Session).switchToParentFrame},
+	{method: http.MethodGet, path: "/window/rect", fn: (*Session).windowRect},
+	{method: http.MethodPost, path: "/window/rect", fn: (*Session).setWindowRect},
+	{method: http.MethodPost, path: "/window/maximize", fn: (*Session).maximizeWindow},
+	{method: http.MethodPost, path: "/window/minimize", fn: (*Session).windowRect},
+	{method: http.MethodPost, path: "/window/fullscreen", fn: (*Session).maximizeWindow},
+	{method: http.MethodGet, path: "/element/active", fn: (*Session).activeElement},
+	{method: http.MethodPost, path: "/element", fn: (*Session).findElement},
+	{method: http.MethodPost, path: "/elements", fn: (*Session).findElements},
+	{method: http.MethodPost, path: "/element/*/element", fn: (*Session).findElement},
+	{method: http.MethodPost, path: "/element/*/elements", fn: (*Session).findElements},
+	{method: http.MethodPost, path: "/shadow/*/element", fn: (*Session).findElement},
+	{method: http.MethodPost, path: "/shadow/*/elements", fn: (*Session).findElements},
+	{method: http.MethodGet, path: "/element/*/shadow", fn: (*Session).shadowRoot},
+	{method: http.MethodGet, path: "/element/*/selected", fn: (*Session).elementSelected},
+	{method: http.MethodGet, path: "/element/*/enabled", fn: (*Session).elementEnabled},
+	{method: http.MethodGet, path: "/element/*/displayed", fn: (*Session).elementDisplayed},
+	{method: http.MethodGet, path: "/element/*/attribute/*", fn: (*Session).elementAttribute},
+	{method: http.MethodGet, path: "/element/*/property/*", fn: (*Session).elementProperty},
+	{method: http.MethodGet, path: "/element/*/css/*", fn: (*Session).elementCSSValue},
+	{method: http.MethodGet, path: "/element/*/text", fn: (*Session).elementText},
+	{method: http.MethodGet, path: "/element/*/name", fn: (*Session).elementTagName},
+	{method: http.MethodGet, path: "/element/*/rect", fn: (*Session).elementRect},
+	{method: http.MethodPost, path: "/element/*/click", fn: (*Session).elementClick},
+	{method: http.MethodPost, path: "/element/*/clear", fn: (*Session).elementClear},
+	{method: http.MethodPost, path: "/element/*/value", fn: (*Session).elementSendKeys},
+	{method: http.MethodGet, path: "/element/*/screenshot", fn: (*Session).elementScreenshot},
+	{method: http.MethodGet, path: "/source", fn: (*Session).pageSource},
+	{method: http.MethodGet, path: "/cookie", fn: (*Session).allCookies},
+	{method: http.MethodGet, path: "/cookie/*", fn: (*Session).namedCookie},
+	{method: http.MethodPost, path: "/cookie", fn: (*Session).addCookie},
+	{method: http.MethodDelete, path: "/cookie/*", fn: (*Session).deleteCookie},
+	{method: http.MethodDelete, path: "/cookie", fn: (*Session).deleteAllCookies},
+	{method: http.MethodPost, path: "/actions", fn: (*Session).performActions},
+	{method: http.MethodDelete, path: "/actions", fn: (*Session).releaseActions},
+	{method: http.MethodPost, path: "/alert/dismiss", fn: (*Session).closeAlert, ignorePrompt: true},
+	{method: http.MethodPost, path: "/alert/accept", fn: (*Session).closeAlert, ignorePrompt: true},
+	{method: http.MethodGet, path: "/alert/text", fn: (*Session).alertText, ignorePrompt: true},
+	{method: http.MethodPost, path: "/alert/text", fn: (*Session).sendAlertText, ignorePrompt: true},
+	{method: http.MethodGet, path: "/screenshot", fn: (*Session).screenshot},
+	{method: http.MethodPost, path: "/print", fn: (*Session).print},
+}
+
+func (s *Session) do(method string, path string, params map[string]interface{}) (interface{}, error) {
+	if method == http.MethodPost && (path == "/execute/sync" || path == "/execute/async") {
+		return s.execute(params)
+	}
+	for _, r := range routes {
+		if r.method != method || !matchPath(r.path, path) {
+			continue
+		}
+		c := &call{vars: pathVars(r.path, path), params: params}
+		s.server.lock.Lock()
+		var (
+			value interface{}
+			err   error
+		)
+		if r.ignorePrompt {
+			value, err = r.fn(s, c)
+		} else if err = s.handlePrompt(); err == nil {
+			value, err = r.fn(s, c)
+		}
+		s.server.lock.Unlock()
+		for _, fn := range c.after {
+			fn()
+		}
+		return value, err
+	}
+	return nil, newError(w3cproto.ErrUnknownCommand, "%s %s", method, path)
+}
+
+func pathVars(pattern string, path string) []string {
+	var vars []string
+	segments := strings.Split(path, "/")
+	for i, segment := range strings.Split(pattern, "/") {
+		if segment == "*" {
+			vars = append(vars, segments[i])
+		}
+	}
+	return vars
+}
+
+// handlePrompt handles the open user prompt according to the unhandledPromptBehavior capability.
+func (s *Session) handlePrompt() error {
+	if s.alert == nil {
+		return nil
+	}
+	text := *s.alert
+	behavior := s.caps.GetString(w3cproto.CapabilityUnhandledPromptBehavior)
+	switch behavior {
+	case "accept", "dismiss":
+		s.alert = nil
+		return nil
+	case "ignore":
+	default:
+		s.alert = nil
+	}
+	err := newError(w3cproto.ErrUnexpectedAlertOpen, "unexpected alert open: {Alert text : %s}", text).(*w3cproto.Error)
+	err.Data = map[string]interface{}{"text": text}
+	return err
+}
+
+func (s *Session) newWindow() *window {
+	s.seq++
+	w := &window{
+		handle: "window-" + strconv.Itoa(s.seq),
+		rect:   w3cproto.Rect{Width: 1280, Height: 720},
+	}
+	s.navigate(w, blankURL)
+	s.windows = append(s.windows, w)
+	return w
+}
+
+func (s *Session) window() (*window, error) {
+	if s.current == nil {
+		return nil, newError(w3cproto.ErrNoSuchWindow, "no such window: target window already closed")
+	}
+	return s.current, nil
+}
+
+// context returns the document of the current browsing context.
+func (s *Session) context() (*document, error) {
+	w, err := s.window()
+	if err != nil {
+		return nil, err
+	}
+	if len(w.frames) > 0 {
+		return w.frames[len(w.frames)-1], nil
+	}
+	return w.doc, nil
+}
+
+func (s *Session) navigate(w *window, rawURL string) {
+	w.history = append(w.history[:w.pos], rawURL)
+	w.pos = len(w.history)
+	s.load(w)
+}
+
+func (s *Session) load(w *window) {
+	rawURL := w.history[w.pos-1]
+	w.doc = loadPage(s.server.page(rawURL), rawURL)
+	w.frames = nil
+}
+
+func (s *Session) getTimeouts(*call) (interface{}, error) {
+	return s.timeouts, nil
+}
+
+func (s *Session) setTimeouts(c *call) (interface{}, error) {
+	for key, v := range c.params {
+		ms, ok := v.(float64)
+		if !ok || ms < 0 {
+			return nil, newError(w3cproto.ErrInvalidArgument, "invalid timeout %s: %v", key, v)
+		}
+		switch key {
+		case "implicit":
+			s.timeouts.Implicit = uint(ms)
+		case "pageLoad":
+			s.timeouts.PageLoad = uint(ms)
+		case "script":
+			s.timeouts.Script = uint(ms)
+		default:
+			return nil, newError(w3cproto.ErrInvalidArgument, "unknown timeout %s", key)
+		}
+	}
+	return nil, nil
+}
+
+func (s *Session) navigateTo(c *call) (interface{}, error) {
+	w, err := s.window()
+	if err != nil {
+		return nil, err
+	}
+	rawURL := c.string("url")
+	if _, err := url.Parse(rawURL); err != nil || len(rawURL) == 0 {
+		return nil, newError(w3cproto.ErrInvalidArgument, "invalid url %q", rawURL)
+	}
+	s.navigate(w, rawURL)
+	return nil, nil
+}
+
+func (s *Session) currentURL(*call) (interface{}, error) {
+	w, err := s.window()
+	if err != nil {
+		return nil, err
+	}
+	return w.doc.url, nil
+}
+
+func (s *Session) back(*call) (interface{}, error) {
+	w, err := s.window()
+	if err != nil {
+		return nil, err
+	}
+	if w.pos > 1 {
+		w.pos--
+		s.load(w)
+	}
+	return nil, nil
+}
+
+func (s *Session) forward(*call) (interface{}, error) {
+	w, err := s.window()
+	if err != nil {
+		return nil, err
+	}
+	if w.pos < len(w.history) {
+		w.pos++
+		s.load(w)
+	}
+	return nil, nil
+}
+
+func (s *Session) refresh(*call) (interface{}, error) {
+	w, err := s.window()
+	if err != nil {
+		return nil, err
+	}
+	s.load(w)
+	return nil, nil
+}
+
+func (s *Session) title(*call) (interface{}, error) {
+	w, err := s.window()
+	if err != nil {
+		return nil, err
+	}
+	return w.doc.page.Title, nil
+}
+
+func (s *Session) windowHandle(*call) (interface{}, error) {
+	w, err := s.window()
+	if err != nil {
+		return nil, err
+	}
+	return w.handle, nil
+}
+
+func (s *Session) windowHandles(*call) (interface{}, error) {
+	handles := make([]string, len(s.windows))
+	for i, w := range s.windows {
+		handles[i] = w.handle
+	}
+	return handles, nil
+}
+
+func (s *Session) closeWindow(c *call) (interface{}, error) {
+	w, err := s.window()
+	if err != nil {
+		return nil, err
+	}
+	for i, win := range s.windows {
+		if win == w {
+			s.windows = append(s.windows[:i:i], s.windows[i+1:]...)
+			break
+		}
+	}
+	s.current = nil
+	if len(s.windows) == 0 {
+		// the session ends with the last window
+		delete(s.server.sessions, s.id)
+	}
+	return s.windowHandles(c)
+}
+
+func (s *Session) switchToWindow(c *call) (interface{}, error) {
+	handle := c.string("handle")
+	for _, w := range s.windows {
+		if w.handle == handle {
+			s.current = w
+			w.frames = nil
+			return nil, nil
+		}
+	}
+	return nil, newError(w3cproto.ErrNoSuchWindow, "no such window: %s", handle)
+}
+
+func (s *Session) openWindow(c *call) (interface{}, error) {
+	if _, err := s.window(); err != nil {
+		return nil, err
+	}
+	typ := c.string("type")
+	if typ != "window" {
+		typ = "tab"
+	}
+	w := s.newWindow()
+	return map[string]interface{}{"handle": w.handle, "type": typ}, nil
+}
+
+func (s *Session) switchToFrame(c *call) (interface{}, error) {
+	w, err := s.window()
+	if err != nil {
+		return nil, err
+	}
+	doc, err := s.context()
+	if err != nil {
+		return nil, err
+	}
+	var frames []*Element
+	walk(doc.root, func(e *Element) {
+		if e.frameDoc != nil {
+			frames = append(frames, e)
+		}
+	})
+	var frame *Element
+	switch id := c.params["id"].(type) {
+	case nil:
+		w.frames = nil
+		return nil, nil
+	case float64:
+		if i := int(id); i >= 0 && i < len(frames) && float64(i) == id {
+			frame = frames[i]
+		}
+	case string:
+		// the frames are also located by the id or the name attribute
+		if i, err := strconv.Atoi(id); err == nil && i >= 0 && i < len(frames) {
+			frame = frames[i]
+		}
+		for _, f := range frames {
+			if frame == nil && (f.Attrs["id"] == id || f.Attrs["name"] == id) {
+				frame = f
+			}
+		}
+	case map[string]interface{}:
+		elemID, _ := id[w3cproto.WebElementIdentifier].(string)
+		e, err := s.element(elemID)
+		if err != nil {
+			return nil, err
+		}
+		if e.frameDoc == nil {
+			return nil, newError(w3cproto.ErrNoSuchFrame, "element is not a frame")
+		}
+		frame = e
+	default:
+		return nil, newError(w3cproto.ErrInvalidArgument, "invalid frame id %v", id)
+	}
+	if frame == nil {
+		return nil, newError(w3cproto.ErrNoSuchFrame, "no such frame: %v", c.params["id"])
+	}
+	w.frames = append(w.frames, frame.frameDoc)
+	return nil, nil
+}
+
+func (s *Session) switchToParentFrame(*call) (interface{}, error) {
+	w, err := s.window()
+	if err != nil {
+		return nil, err
+	}
+	if len(w.frames) > 0 {
+		w.frames = w.frames[:len(w.frames)-1]
+	}
+	return nil, nil
+}
+
+func (s *Session) windowRect(*call) (interface{}, error) {
+	w, err := s.window()
+	if err != nil {
+		return nil, err
+	}
+	return w.rect, nil
+}
+
+func (s *Session) setWindowRect(c *call) (interface{}, error) {
+	w, err := s.window()
+	if err != nil {
+		return nil, err
+	}
+	for key, field := range map[string]*int{"x": &w.rect.X, "y": &w.rect.Y, "width": &w.rect.Width, "height": &w.rect.Height} {
+		switch v := c.params[key].(type) {
+		case nil:
+		case float64:
+			*field = int(v)
+		default:
+			return nil, newError(w3cproto.ErrInvalidArgument, "invalid %s: %v", key, v)
+		}
+	}
+	return w.rect, nil
+}
+
+func (s *Session) maximizeWindow(*call) (interface{}, error) {
+	w, err := s.window()
+	if err != nil {
+		return nil, err
+	}
+	w.rect = w3cproto.Rect{Width: 1920, Height: 1080}
+	return w.rect, nil
+}
+
+// reference returns the web element reference of the element.
+func (s *Session) reference(e *Element) map[string]string {
+	id, ok := s.ids[e]
+	if !ok {
+		s.seq++
+		id = "element-" + strconv.Itoa(s.seq)
+		s.ids[e] = id
+		s.elements[id] = e
+	}
+	return map[string]string{w3cproto.WebElementIdentifier: id}
+}
+
+// element returns the element of the current browsing context by the id.
+func (s *Session) element(id string) (*Element, error) {
+	e, ok := s.elements[id]
+	if !ok {
+		return nil, newError(w3cproto.ErrNoSuchElement, "no such element: element %s is unknown", id)
+	}
+	doc, err := s.context()
+	if err != nil {
+		return nil, err
+	}
+	if !doc.contains(e) {
+		return nil, newError(w3cproto.ErrStaleElementReference, "stale element reference: element %s is not attached to the page document", id)
+	}
+	return e, nil
+}
+
+func (s *Session) activeElement(*call) (interface{}, error) {
+	doc, err := s.context()
+	if err != nil {
+		return nil, err
+	}
+	if doc.active != nil && doc.contains(doc.active) {
+		return s.reference(doc.active), nil
+	}
+	return s.reference(doc.body), nil
+}
+
+// search returns the elements of the search scope of the command matching the locator.
+func (s *Session) search(c *call) ([]*Element, error) {
+	using := w3cproto.FindElementStrategy(c.string("using"))
+	match, err := matcher(using, c.string("value"))
+	if err != nil {
+		return nil, err
+	}
+	var roots []*Element
+	switch {
+	case len(c.vars) == 0:
+		doc, err := s.context()
+		if err != nil {
+			return nil, err
+		}
+		roots = []*Element{doc.root}
+	case strings.HasPrefix(c.vars[0], "shadow-"):
+		host, ok := s.shadows[c.vars[0]]
+		if !ok {
+			return nil, newError(w3cproto.ErrNoSuchShadowRoot, "no such shadow root: %s", c.vars[0])
+		}
+		if _, err := s.element(s.ids[host]); err != nil {
+			return nil, newError(w3cproto.ErrDetachedShadowRoot, "detached shadow root: %s", c.vars[0])
+		}
+		roots = host.Shadow
+	default:
+		e, err := s.element(c.vars[0])
+		if err != nil {
+			return nil, err
+		}
+		roots = e.Children
+	}
+	var found []*Element
+	for _, root := range roots {
+		walk(root, func(e *Element) {
+			if match(e) {
+				found = append(found, e)
+			}
+		})
+	}
+	return found, nil
+}
+
+func (s *Session) findElement(c *call) (interface{}, error) {
+	found, err := s.search(c)
+	if err != nil {
+		return nil, err
+	}
+	if len(found) == 0 {
+		return nil, newError(w3cproto.ErrNoSuchElement, "no such element: unable to locate element: {\"method\":%q,\"selector\":%q}",
+			c.string("using"), c.string("value"))
+	}
+	return s.reference(found[0]), nil
+}
+
+func (s *Session) findElements(c *call) (interface{}, error) {
+	found, err := s.search(c)
+	if err != nil {
+		return nil, err
+	}
+	refs := make([]map[string]string, len(found))
+	for i, e := range found {
+		refs[i] = s.reference(e)
+	}
+	return refs, nil
+}
+
+func (s *Session) shadowRoot(c *call) (interface{}, error) {
+	e, err := s.element(c.vars[0])
+	if err != nil {
+		return nil, err
+	}
+	if e.Shadow == nil {
+		return nil, newError(w3cproto.ErrNoSuchShadowRoot, "no such shadow root: element has no shadow root")
+	}
+	id := "shadow-" + strings.TrimPrefix(s.ids[e], "element-")
+	s.shadows[id] = e
+	return map[string]string{w3cproto.ShadowRootIdentifier: id}, nil
+}
+
+func (s *Session) elementSelected(c *call) (interface{}, error) {
+	e, err := s.element(c.vars[0])
+	if err != nil {
+		return nil, err
+	}
+	return e.Selected, nil
+}
+
+func (s *Session) elementEnabled(c *call) (interface{}, error) {
+	e, err := s.element(c.vars[0])
+	if err != nil {
+		return nil, err
+	}
+	return !e.Disabled, nil
+}
+
+func (s *Session) elementDisplayed(c *call) (interface{}, error) {
+	e, err := s.element(c.vars[0])
+	if err != nil {
+		return nil, err
+	}
+	return e.isDisplayed(), nil
+}
+
+func (s *Session) elementAttribute(c *call) (interface{}, error) {
+	e, err := s.element(c.vars[0])
+	if err != nil {
+		return nil, err
+	}
+	switch name := c.vars[1]; name {
+	case "checked", "selected":
+		if e.Selected {
+			return "true", nil
+		}
+		return nil, nil
+	case "disabled":
+		if e.Disabled {
+			return "true", nil
+		}
+		return nil, nil
+	default:
+		if v, ok := e.attr(name); ok {
+			return v, nil
+		}
+		return nil, nil
+	}
+}
+
+func (s *Session) elementProperty(c *call) (interface{}, error) {
+	e, err := s.element(c.vars[0])
+	if err != nil {
+		return nil, err
+	}
+	v, _ := e.property(c.vars[1])
+	return v, nil
+}
+
+func (s *Session) elementCSSValue(c *call) (interface{}, error) {
+	e, err := s.element(c.vars[0])
+	if err != nil {
+		return nil, err
+	}
+	if v, ok := e.CSS[c.vars[1]]; ok {
+		return v, nil
+	}
+	if c.vars[1] == "display" {
+		if e.hidden() {
+			return "none", nil
+		}
+		return "block", nil
+	}
+	return "", nil
+}
+
+func (s *Session) elementText(c *call) (interface{}, error) {
+	e, err := s.element(c.vars[0])
+	if err != nil {
+		return nil, err
+	}
+	return e.visibleText(), nil
+}
+
+func (s *Session) elementTagName(c *call) (interface{}, error) {
+	e, err := s.element(c.vars[0])
+	if err != nil {
+		return nil, err
+	}
+	return e.tagName(), nil
+}
+
+func (s *Session) elementRect(c *call) (interface{}, error) {
+	e, err := s.element(c.vars[0])
+	if err != nil {
+		return nil, err
+	}
+	return e.rect(), nil
+}
+
+func (s *Session) elementClick(c *call) (interface{}, error) {
+	e, err := s.element(c.vars[0])
+	if err != nil {
+		return nil, err
+	}
+	if !e.isDisplayed() {
+		return nil, newError(w3cproto.ErrElementNotInteractable, "element not interactable")
+	}
+	doc, _ := s.context()
+	doc.active = e
+	if e.Disabled {
+		return nil, nil
+	}
+	switch {
+	case e.tagName() == "option":
+		s.selectOption(e)
+	case e.isCheckable() && strings.EqualFold(e.Attrs["type"], "radio"):
+		walk(doc.root, func(other *Element) {
+			if other.isCheckable() && strings.EqualFold(other.Attrs["type"], "radio") && other.Attrs["name"] == e.Attrs["name"] {
+				other.Selected = false
+			}
+		})
+		e.Selected = true
+	case e.isCheckable():
+		e.Selected = !e.Selected
+	case e.tagName() == "a" && len(e.Attrs["href"]) > 0:
+		w, _ := s.window()
+		s.navigate(w, resolveURL(w.doc.url, e.Attrs["href"]))
+	}
+	if e.OnClick != nil {
+		c.after = append(c.after, func() { e.OnClick(s) })
+	}
+	return nil, nil
+}
+
+func (s *Session) selectOption(option *Element) {
+	var selectElem *Element
+	for p := option.parent; p != nil; p = p.parent {
+		if p.tagName() == "select" {
+			selectElem = p
+			break
+		}
+	}
+	if selectElem == nil {
+		option.Selected = true
+		return
+	}
+	if _, multiple := selectElem.Attrs["multiple"]; multiple {
+		option.Selected = !option.Selected
+		return
+	}
+	walk(selectElem, func(e *Element) {
+		if e.tagName() == "option" {
+			e.Selected = e == option
+		}
+	})
+}
+
+func resolveURL(base string, ref string) string {
+	b, err := url.Parse(base)
+	if err != nil {
+		return ref
+	}
+	r, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return b.ResolveReference(r).String()
+}
+
+func (s *Session) elementClear(c *call) (interface{}, error) {
+	e, err := s.element(c.vars[0])
+	if err != nil {
+		return nil, err
+	}
+	if !e.isEditable() || e.Disabled {
+		return nil, newError(w3cproto.ErrInvalidElementState, "invalid element state: element must be user-editable in order to clear it")
+	}
+	e.setValue("")
+	return nil, nil
+}
+
+func (s *Session) elementSendKeys(c *call) (interface{}, error) {
+	e, err := s.element(c.vars[0])
+	if err != nil {
+		return nil, err
+	}
+	if !e.isDisplayed() || e.Disabled {
+		return nil, newError(w3cproto.ErrElementNotInteractable, "element not interactable")
+	}
+	if !e.isEditable() {
+		return nil, nil
+	}
+	value := []rune(e.inputValue())
+	for _, r := range c.string("text") {
+		switch {
+		case r == '\ue003': // backspace
+			if len(value) > 0 {
+				value = value[:len(value)-1]
+			}
+		case r >= '\ue000' && r <= '\uf8ff':
+			// the other special keys do not change the value
+		default:
+			value = append(value, r)
+		}
+	}
+	e.setValue(string(value))
+	doc, _ := s.context()
+	doc.active = e
+	return nil, nil
+}
+
+func (s *Session) pageSource(*call) (interface{}, error) {
+	doc, err := s.context()
+	if err != nil {
+		return nil, err
+	}
+	return doc.source(), nil
+}
+
+// execute runs the script handlers without the lock, so that they can use the session.
+func (s *Session) execute(params map[string]interface{}) (interface{}, error) {
+	script, _ := params["script"].(string)
+	rawArgs, _ := params["args"].([]interface{})
+
+	s.server.lock.Lock()
+	if err := s.handlePrompt(); err != nil {
+		s.server.lock.Unlock()
+		return nil, err
+	}
+	args := make([]interface{}, len(rawArgs))
+	for i, arg := range rawArgs {
+		v, err := s.decodeArg(arg)
+		if err != nil {
+			s.server.lock.Unlock()
+			return nil, err
+		}
+		args[i] = v
+	}
+	scripts := append([]ScriptFunc(nil), s.server.scripts...)
+	s.server.lock.Unlock()
+
+	for i := len(scripts) - 1; i >= 0; i-- {
+		result, ok, err := scripts[i](s, script, args)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			s.server.lock.Lock()
+			defer s.server.lock.Unlock()
+			return s.encodeResult(result), nil
+		}
+	}
+	return nil, newError(w3cproto.ErrJavaScriptError, "javascript error: w3ctest has no handler of the script: %s", script)
+}
+
+func (s *Session) decodeArg(arg interface{}) (interface{}, error) {
+	switch v := arg.(type) {
+	case map[string]interface{}:
+		if id, ok := v[w3cproto.WebElementIdentifier].(string); ok {
+			return s.element(id)
+		}
+		m := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			decoded, err := s.decodeArg(value)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = decoded
+		}
+		return m, nil
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, value := range v {
+			decoded, err := s.decodeArg(value)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = decoded
+		}
+		return list, nil
+	}
+	return arg, nil
+}
+
+func (s *Session) encodeResult(result interface{}) interface{} {
+	switch v := result.(type) {
+	case *Element:
+		return s.reference(v)
+	case []*Element:
+		refs := make([]interface{}, len(v))
+		for i, e := range v {
+			refs[i] = s.reference(e)
+		}
+		return refs
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, value := range v {
+			list[i] = s.encodeResult(value)
+		}
+		return list
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			m[key] = s.encodeResult(value)
+		}
+		return m
+	}
+	return result
+}
+
+// visibleCookies returns the indexes of the cookies visible to the current page.
+func (s *Session) visibleCookies() ([]int, error) {
+	w, err := s.window()
+	if err != nil {
+		return nil, err
+	}
+	u, err := url.Parse(w.doc.url)
+	if err != nil {
+		return nil, nil
+	}
+	now := time.Now().Unix()
+	var visible []int
+	for i, c := range s.cookies {
+		if expiry, ok := c[w3cproto.CookieExpiryKey].(float64); ok && int64(expiry) <= now {
+			continue
+		}
+		domain := strings.TrimPrefix(fmt.Sprint(c[w3cproto.CookieDomainKey]), ".")
+		host := u.Hostname()
+		if host != domain && !strings.HasSuffix(host, "."+domain) {
+			continue
+		}
+		path := fmt.Sprint(c[w3cproto.CookiePathKey])
+		urlPath := u.Path
+		if len(urlPath) == 0 {
+			urlPath = "/"
+		}
+		if !strings.HasPrefix(urlPath, path) {
+			continue
+		}
+		if secure, _ := c[w3cproto.CookieSecureKey].(bool); secure && u.Scheme != "https" {
+			continue
+		}
+		visible = append(visible, i)
+	}
+	return visible, nil
+}
+
+func (s *Session) allCookies(*call) (interface{}, error) {
+	visible, err := s.visibleCookies()
+	if err != nil {
+		return nil, err
+	}
+	cookies := make([]w3cproto.Cookie, len(visible))
+	for i, idx := range visible {
+		cookies[i] = copyCookie(s.cookies[idx])
+	}
+	return cookies, nil
+}
+
+func (s *Session) namedCookie(c *call) (interface{}, error) {
+	visible, err := s.visibleCookies()
+	if err != nil {
+		return nil, err
+	}
+	for _, idx := range visible {
+		if s.cookies[idx].Name() == c.vars[0] {
+			return copyCookie(s.cookies[idx]), nil
+		}
+	}
+	return nil, newError(w3cproto.ErrNoSuchCookie, "no such cookie: %s", c.vars[0])
+}
+
+func (s *Session) addCookie(c *call) (interface{}, error) {
+	w, err := s.window()
+	if err != nil {
+		return nil, err
+	}
+	params, ok := c.params["cookie"].(map[string]interface{})
+	if !ok {
+		return nil, newError(w3cproto.ErrInvalidArgument, "invalid argument: missing cookie")
+	}
+	cookie := w3cproto.Cookie(params)
+	if len(cookie.Name()) == 0 {
+		return nil, newError(w3cproto.ErrInvalidArgument, "invalid argument: missing cookie name")
+	}
+	if _, ok := cookie[w3cproto.CookieValueKey].(string); !ok {
+		return nil, newError(w3cproto.ErrInvalidArgument, "invalid argument: missing cookie value")
+	}
+	u, err := url.Parse(w.doc.url)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, newError(w3cproto.ErrInvalidCookieDomain, "invalid cookie domain: document is cookie-averse")
+	}
+	host := u.Hostname()
+	if domain, ok := cookie[w3cproto.CookieDomainKey].(string); ok && len(domain) > 0 {
+		d := strings.TrimPrefix(domain, ".")
+		if host != d && !strings.HasSuffix(host, "."+d) {
+			return nil, newError(w3cproto.ErrInvalidCookieDomain, "invalid cookie domain: %s", domain)
+		}
+	} else {
+		cookie[w3cproto.CookieDomainKey] = host
+	}
+	if path, ok := cookie[w3cproto.CookiePathKey].(string); !ok || len(path) == 0 {
+		cookie[w3cproto.CookiePathKey] = "/"
+	}
+	for _, key := range []string{w3cproto.CookieSecureKey, w3cproto.CookieHttpOnlyKey} {
+		if _, ok := cookie[key].(bool); !ok {
+			cookie[key] = false
+		}
+	}
+	for i, existing := range s.cookies {
+		if existing.Name() == cookie.Name() && existing[w3cproto.CookieDomainKey] == cookie[w3cproto.CookieDomainKey] &&
+			existing[w3cproto.CookiePathKey] == cookie[w3cproto.CookiePathKey] {
+			s.cookies = append(s.cookies[:i:i], s.cookies[i+1:]...)
+			break
+		}
+	}
+	s.cookies = append(s.cookies, copyCookie(cookie))
+	return nil, nil
+}
+
+func (s *Session) deleteCookie(c *call) (interface{}, error) {
+	return s.deleteCookies(func(cookie w3cproto.Cookie) bool {
+		return cookie.Name() == c.vars[0]
+	})
+}
+
+func (s *Session) deleteAllCookies(*call) (interface{}, error) {
+	return s.deleteCookies(func(w3cproto.Cookie) bool {
+		return true
+	})
+}
+
+func (s *Session) deleteCookies(match func(w3cproto.Cookie) bool) (interface{}, error) {
+	visible, err := s.visibleCookies()
+	if err != nil {
+		return nil, err
+	}
+	remove := make(map[int]bool)
+	for _, idx := range visible {
+		remove[idx] = match(s.cookies[idx])
+	}
+	cookies := s.cookies[:0]
+	for i, cookie := range s.cookies {
+		if !remove[i] {
+			cookies = append(cookies, cookie)
+		}
+	}
+	s.cookies = cookies
+	return nil, nil
+}
+
+func copyCookie(c w3cproto.Cookie) w3cproto.Cookie {
+	cp := make(w3cproto.Cookie, len(c))
+	for k, v := range c {
+		cp[k] = v
+	}
+	return cp
+}
+
+// performActions accepts the actions, the actions do not change the model.
+func (s *Session) performActions(c *call) (interface{}, error) {
+	if _, ok := c.params["actions"].([]interface{}); !ok {
+		return nil, newError(w3cproto.ErrInvalidArgument, "invalid argument: actions must be an array")
+	}
+	return nil, nil
+}
+
+func (s *Session) releaseActions(*call) (interface{}, error) {
+	return nil, nil
+}
+
+func (s *Session) closeAlert(*call) (interface{}, error) {
+	if s.alert == nil {
+		return nil, newError(w3cproto.ErrNoSuchAlert, "no such alert")
+	}
+	s.alert = nil
+	return nil, nil
+}
+
+func (s *Session) alertText(*call) (interface{}, error) {
+	if s.alert == nil {
+		return nil, newError(w3cproto.ErrNoSuchAlert, "no such alert")
+	}
+	return *s.alert, nil
+}
+
+func (s *Session) sendAlertText(c *call) (interface{}, error) {
+	if s.alert == nil {
+		return nil, newError(w3cproto.ErrNoSuchAlert, "no such alert")
+	}
+	text := c.string("text")
+	s.prompt = &text
+	return nil, nil
+}
+
+func (s *Session) screenshot(*call) (interface{}, error) {
+	w, err := s.window()
+	if err != nil {
+		return nil, err
+	}
+	return encodePNG(w.rect.Width, w.rect.Height)
+}
+
+func (s *Session) elementScreenshot(c *call) (interface{}, error) {
+	e, err := s.element(c.vars[0])
+	if err != nil {
+		return nil, err
+	}
+	r := e.rect()
+	return encodePNG(r.Width, r.Height)
+}
+
+// encodePNG returns the base64 encoded white PNG image of the size.
+func encodePNG(width int, height int) (interface{}, error) {
+	if width <= 0 || height <= 0 {
+		return nil, newError(w3cproto.ErrUnableToCaptureScreen, "unable to capture screen: empty area")
+	}
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// print returns the base64 encoded stub of the PDF document.
+func (s *Session) print(*call) (interface{}, error) {
+	if _, err := s.window(); err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.EncodeToString([]byte("%PDF-1.4\n%w3ctest\n%%EOF\n")), nil
+}
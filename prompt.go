@@ -0,0 +1,107 @@
+package webdriver
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mediabuyerbot/go-webdriver/pkg/w3cproto"
+)
+
+const (
+	// PromptIgnore leaves the user prompt open and returns the original error.
+	PromptIgnore PromptAction = iota
+
+	// PromptAccept accepts the user prompt and retries the command.
+	PromptAccept
+
+	// PromptDismiss dismisses the user prompt and retries the command.
+	PromptDismiss
+)
+
+// PromptAction represents an action that handles the user prompt.
+type PromptAction int
+
+// PromptHandler decides how to handle the user prompt with the given text
+// that blocks the command with an "unexpected alert open" error.
+type PromptHandler func(text string) PromptAction
+
+// AcceptPrompts returns a handler that accepts all user prompts.
+func AcceptPrompts() PromptHandler {
+	return func(string) PromptAction {
+		return PromptAccept
+	}
+}
+
+// DismissPrompts returns a handler that dismisses all user prompts.
+func DismissPrompts() PromptHandler {
+	return func(string) PromptAction {
+		return PromptDismiss
+	}
+}
+
+// promptDoer handles the user prompts that block commands according to the prompt handler
+// and retries the blocked command once.
+type promptDoer struct {
+	doer    w3cproto.Doer
+	alert   w3cproto.Alert
+	handler PromptHandler
+	lock    sync.RWMutex
+}
+
+func newPromptDoer(doer w3cproto.Doer, sessID string) *promptDoer {
+	return &promptDoer{
+		doer:  doer,
+		alert: w3cproto.NewAlert(doer, sessID),
+	}
+}
+
+func (d *promptDoer) setHandler(h PromptHandler) {
+	d.lock.Lock()
+	d.handler = h
+	d.lock.Unlock()
+}
+
+func (d *promptDoer) getHandler() PromptHandler {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+	return d.handler
+}
+
+func (d *promptDoer) Do(ctx context.Context, method string, path string, p w3cproto.Params) (*w3cproto.Response, error) {
+	resp, err := d.doer.Do(ctx, method, path, p)
+	if err == nil || !w3cproto.IsUnexpectedAlertOpen(err) {
+		return resp, err
+	}
+	handler := d.getHandler()
+	if handler == nil {
+		return resp, err
+	}
+	handled, herr := d.handle(ctx, handler)
+	if herr != nil || !handled {
+		return resp, err
+	}
+	return d.doer.Do(ctx, method, path, p)
+}
+
+func (d *promptDoer) handle(ctx context.Context, handler PromptHandler) (bool, error) {
+	text, err := d.alert.Text(ctx)
+	if err != nil {
+		// the remote end has already closed the prompt (e.g. "dismiss and notify" behavior)
+		if w3cproto.IsNoSuchAlert(err) {
+			return true, nil
+		}
+		return false, err
+	}
+	switch handler(text) {
+	case PromptAccept:
+		err = d.alert.Accept(ctx)
+	case PromptDismiss:
+		err = d.alert.Dismiss(ctx)
+	default:
+		return false, nil
+	}
+	if err != nil && !w3cproto.IsNoSuchAlert(err) {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,115 @@
+package webdriver
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mediabuyerbot/go-webdriver/pkg/w3cproto"
+)
+
+var (
+	unexpectedAlertErr = &w3cproto.Error{Code: "unexpected alert open"}
+	noSuchAlertErr     = &w3cproto.Error{Code: "no such alert"}
+)
+
+func newPromptDoerWithMock(t *testing.T) (*promptDoer, *w3cproto.MockDoer, func()) {
+	ctrl := gomock.NewController(t)
+	cli := w3cproto.NewMockDoer(ctrl)
+	return newPromptDoer(cli, "123"), cli, ctrl.Finish
+}
+
+func TestPromptDoer_Accept(t *testing.T) {
+	doer, cli, done := newPromptDoerWithMock(t)
+	defer done()
+
+	ctx := context.TODO()
+	var haveText string
+	doer.setHandler(func(text string) PromptAction {
+		haveText = text
+		return PromptAccept
+	})
+
+	gomock.InOrder(
+		cli.EXPECT().Do(ctx, http.MethodGet, "/session/123/title", nil).Return(nil, unexpectedAlertErr),
+		cli.EXPECT().Do(ctx, http.MethodGet, "/session/123/alert/text", nil).Return(
+			&w3cproto.Response{Value: []byte(`"are you sure?"`)}, nil),
+		cli.EXPECT().Do(ctx, http.MethodPost, "/session/123/alert/accept", nil).Return(
+			&w3cproto.Response{Value: []byte(`null`)}, nil),
+		cli.EXPECT().Do(ctx, http.MethodGet, "/session/123/title", nil).Return(
+			&w3cproto.Response{Value: []byte(`"title"`)}, nil),
+	)
+	resp, err := doer.Do(ctx, http.MethodGet, "/session/123/title", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, `"title"`, string(resp.Value))
+	assert.Equal(t, "are you sure?", haveText)
+}
+
+func TestPromptDoer_Dismiss(t *testing.T) {
+	doer, cli, done := newPromptDoerWithMock(t)
+	defer done()
+
+	ctx := context.TODO()
+	doer.setHandler(DismissPrompts())
+
+	gomock.InOrder(
+		cli.EXPECT().Do(ctx, http.MethodPost, "/session/123/url", nil).Return(nil, unexpectedAlertErr),
+		cli.EXPECT().Do(ctx, http.MethodGet, "/session/123/alert/text", nil).Return(
+			&w3cproto.Response{Value: []byte(`"text"`)}, nil),
+		cli.EXPECT().Do(ctx, http.MethodPost, "/session/123/alert/dismiss", nil).Return(
+			&w3cproto.Response{Value: []byte(`null`)}, nil),
+		cli.EXPECT().Do(ctx, http.MethodPost, "/session/123/url", nil).Return(
+			&w3cproto.Response{Value: []byte(`null`)}, nil),
+	)
+	_, err := doer.Do(ctx, http.MethodPost, "/session/123/url", nil)
+	assert.Nil(t, err)
+}
+
+func TestPromptDoer_AlreadyClosed(t *testing.T) {
+	doer, cli, done := newPromptDoerWithMock(t)
+	defer done()
+
+	ctx := context.TODO()
+	doer.setHandler(AcceptPrompts())
+
+	gomock.InOrder(
+		cli.EXPECT().Do(ctx, http.MethodGet, "/session/123/url", nil).Return(nil, unexpectedAlertErr),
+		cli.EXPECT().Do(ctx, http.MethodGet, "/session/123/alert/text", nil).Return(nil, noSuchAlertErr),
+		cli.EXPECT().Do(ctx, http.MethodGet, "/session/123/url", nil).Return(
+			&w3cproto.Response{Value: []byte(`"http://example.com"`)}, nil),
+	)
+	_, err := doer.Do(ctx, http.MethodGet, "/session/123/url", nil)
+	assert.Nil(t, err)
+}
+
+func TestPromptDoer_Ignore(t *testing.T) {
+	doer, cli, done := newPromptDoerWithMock(t)
+	defer done()
+
+	ctx := context.TODO()
+
+	// without handler
+	cli.EXPECT().Do(ctx, http.MethodGet, "/session/123/title", nil).Return(nil, unexpectedAlertErr)
+	_, err := doer.Do(ctx, http.MethodGet, "/session/123/title", nil)
+	assert.Equal(t, unexpectedAlertErr, err)
+
+	// handler ignores the prompt
+	doer.setHandler(func(string) PromptAction {
+		return PromptIgnore
+	})
+	gomock.InOrder(
+		cli.EXPECT().Do(ctx, http.MethodGet, "/session/123/title", nil).Return(nil, unexpectedAlertErr),
+		cli.EXPECT().Do(ctx, http.MethodGet, "/session/123/alert/text", nil).Return(
+			&w3cproto.Response{Value: []byte(`"text"`)}, nil),
+	)
+	_, err = doer.Do(ctx, http.MethodGet, "/session/123/title", nil)
+	assert.Equal(t, unexpectedAlertErr, err)
+
+	// other errors are not handled
+	cli.EXPECT().Do(ctx, http.MethodGet, "/session/123/title", nil).Return(nil, w3cproto.ErrInvalidResponse)
+	_, err = doer.Do(ctx, http.MethodGet, "/session/123/title", nil)
+	assert.Equal(t, w3cproto.ErrInvalidResponse, err)
+}
@@ -6,15 +6,24 @@ import (
 	"github.com/mediabuyerbot/go-webdriver/pkg/w3cproto"
 )
 
-// OpenRemoteBrowser creates a new instance of the remote browser.
-func OpenRemoteBrowser(ctx context.Context, addr string, opts w3cproto.BrowserOptions) (*Browser, error) {
-	sess, err := NewSession(ctx, addr, opts)
+// OpenRemoteBrowser creates a new instance of the remote browser. The context is the default context
+// of the browser methods without the context parameter.
+// The commands pass through the middlewares, the first middleware is the outermost one.
+func OpenRemoteBrowser(ctx context.Context, addr string, opts w3cproto.BrowserOptions, middlewares ...w3cproto.Middleware) (*Browser, error) {
+	sess, err := NewSession(ctx, addr, opts, middlewares...)
 	if err != nil {
 		return nil, err
 	}
-	return &Browser{
-		ctx:    context.Background(),
-		driver: nil,
-		sess:   sess,
-	}, nil
+	return newBrowser(ctx, sess, nil), nil
+}
+
+// OpenBrowserFromDoer creates a new instance of the browser on top of the doer, e.g. the cassette player.
+// The context is the default context of the browser methods without the context parameter.
+// The commands pass through the middlewares, the first middleware is the outermost one.
+func OpenBrowserFromDoer(ctx context.Context, doer w3cproto.Doer, opts w3cproto.BrowserOptions, middlewares ...w3cproto.Middleware) (*Browser, error) {
+	sess, err := NewSessionFromDoer(ctx, doer, opts, middlewares...)
+	if err != nil {
+		return nil, err
+	}
+	return newBrowser(ctx, sess, nil), nil
 }
@@ -0,0 +1,94 @@
+package webdriver
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mediabuyerbot/go-webdriver/pkg/cassette"
+	"github.com/mediabuyerbot/go-webdriver/pkg/w3cproto"
+	"github.com/mediabuyerbot/go-webdriver/pkg/w3ctest"
+)
+
+func TestOpenBrowserFromDoer(t *testing.T) {
+	player, err := cassette.NewPlayer(strings.NewReader(`
+{"method":"POST","path":"/session","params":{"capabilities":{}},"response":{"value":{"sessionId":"session-1","capabilities":{"browserName":"chrome"}}}}
+{"method":"POST","path":"/session/session-1/url","params":{"url":"https://example.com"},"response":{"value":null}}
+{"method":"POST","path":"/session/session-1/element","params":{"using":"css selector","value":"#login"},"response":{"value":{"element-6066-11e4-a52e-4f735466cecf":"element-1"}}}
+{"method":"GET","path":"/session/session-1/element/element-1/text","response":{"value":"Log in"}}
+{"method":"DELETE","path":"/session/session-1","response":{"value":null}}
+`))
+	assert.Nil(t, err)
+
+	browser, err := OpenBrowserFromDoer(context.Background(), player, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "session-1", browser.UID())
+	assert.Nil(t, browser.NavigateTo("https://example.com"))
+	elem, err := browser.FindElement(By.CSS("#login"))
+	assert.Nil(t, err)
+	text, err := elem.Text()
+	assert.Nil(t, err)
+	assert.Equal(t, "Log in", text)
+	assert.Nil(t, browser.Close())
+	assert.Equal(t, 0, player.Remaining())
+}
+
+func TestOpenRemoteBrowser_FakeRemoteEnd(t *testing.T) {
+	confirm := w3ctest.El("button", w3ctest.Attrs{"id": "delete"}).WithText("Delete")
+	confirm.OnClick = func(s *w3ctest.Session) {
+		s.OpenAlert("Are you sure?")
+	}
+	srv := w3ctest.NewServer(&w3ctest.Page{
+		URL:   "https://example.com/",
+		Title: "Example",
+		Body: []*w3ctest.Element{
+			w3ctest.El("ul", w3ctest.Attrs{"class": "items"},
+				w3ctest.El("li", nil).WithText("One"),
+				w3ctest.El("li", w3ctest.Attrs{"hidden": ""}).WithText("Two"),
+			),
+			confirm,
+		},
+	})
+	defer srv.Close()
+	srv.Handle(http.MethodGet, "/element/*/css/*", func(s *w3ctest.Session, cmd *w3ctest.Command) (interface{}, error) {
+		return "rgb(0, 0, 0)", nil
+	})
+
+	browser, err := OpenRemoteBrowser(context.Background(), srv.URL, w3cproto.NewBrowserOptions(nil, nil))
+	assert.Nil(t, err)
+	assert.Nil(t, browser.NavigateTo("https://example.com/"))
+	title, err := browser.TitleContext(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, "Example", title)
+
+	items, err := browser.FindElements(By.CSS("ul.items > li"))
+	assert.Nil(t, err)
+	assert.Len(t, items, 2)
+	displayed, err := items[0].IsDisplayed()
+	assert.Nil(t, err)
+	assert.True(t, displayed)
+	displayed, err = items[1].IsDisplayed()
+	assert.Nil(t, err)
+	assert.False(t, displayed)
+	color, err := items[0].CSSValue("color")
+	assert.Nil(t, err)
+	assert.Equal(t, "rgb(0, 0, 0)", color)
+
+	// the prompt opened by the click blocks the next command until it is handled
+	button, err := browser.FindElementByID("delete")
+	assert.Nil(t, err)
+	assert.Nil(t, button.Click())
+	_, err = browser.TitleContext(context.Background())
+	assert.True(t, w3cproto.IsUnexpectedAlertOpen(err))
+	assert.Nil(t, button.Click())
+	browser.SetPromptHandler(AcceptPrompts())
+	_, err = browser.TitleContext(context.Background())
+	assert.Nil(t, err)
+
+	assert.Nil(t, browser.Close())
+	_, ok := srv.Session(browser.UID())
+	assert.False(t, ok)
+}
@@ -19,34 +19,69 @@ type Session struct {
 	document      w3cproto.Document
 	screenCapture w3cproto.ScreenCapture
 	elements      w3cproto.Elements
+	actions       w3cproto.Actions
+	print         w3cproto.Print
+	alert         w3cproto.Alert
+	prompts       *promptDoer
+	lock          *commandLock
+
+	// addr is the address of the remote end, empty if the session is created on top of the doer.
+	addr string
+}
+
+// NewSessionFromClient creates a new session using the HTTP client. The commands pass through
+// the middlewares, the first middleware is the outermost one.
+func NewSessionFromClient(ctx context.Context, client httpclient.Client, opts w3cproto.BrowserOptions, middlewares ...w3cproto.Middleware) (*Session, error) {
+	return NewSessionFromDoer(ctx, w3cproto.WithClient(client), opts, middlewares...)
 }
 
-func NewSessionFromClient(ctx context.Context, client httpclient.Client, opts w3cproto.BrowserOptions) (*Session, error) {
-	cli := w3cproto.WithClient(client)
+// NewSessionFromDoer creates a new session on top of the doer, e.g. the cassette player.
+// The commands pass through the middlewares, the first middleware is the outermost one.
+func NewSessionFromDoer(ctx context.Context, doer w3cproto.Doer, opts w3cproto.BrowserOptions, middlewares ...w3cproto.Middleware) (*Session, error) {
+	cli := w3cproto.Chain(doer, middlewares...)
 	sess, err := w3cproto.NewSession(ctx, cli, opts)
 	if err != nil {
 		return nil, err
 	}
+	return newSession(cli, sess), nil
+}
 
-	browser := Session{
+// newSession builds the protocols of the session on top of the doer. The commands of the session
+// are serialized, the blocked command and the handling of the user prompt are sent holding the lock.
+func newSession(cli w3cproto.Doer, sess w3cproto.Session) *Session {
+	lock := newCommandLock()
+	prompts := newPromptDoer(cli, sess.ID())
+	doer := &lockedDoer{doer: prompts, lock: lock}
+	return &Session{
 		session:       sess,
-		timeouts:      w3cproto.NewTimeouts(cli, sess.ID()),
-		navigation:    w3cproto.NewNavigation(cli, sess.ID()),
-		context:       w3cproto.NewContext(cli, sess.ID()),
-		cookies:       w3cproto.NewCookies(cli, sess.ID()),
-		document:      w3cproto.NewDocument(cli, sess.ID()),
-		elements:      w3cproto.NewElements(cli, sess.ID()),
-		screenCapture: w3cproto.NewScreenCapture(cli, sess.ID()),
+		timeouts:      w3cproto.NewTimeouts(doer, sess.ID()),
+		navigation:    w3cproto.NewNavigation(doer, sess.ID()),
+		context:       w3cproto.NewContext(doer, sess.ID()),
+		cookies:       w3cproto.NewCookies(doer, sess.ID()),
+		document:      w3cproto.NewDocument(doer, sess.ID()),
+		elements:      w3cproto.NewElements(doer, sess.ID()),
+		screenCapture: w3cproto.NewScreenCapture(doer, sess.ID()),
+		actions:       w3cproto.NewActions(doer, sess.ID()),
+		print:         w3cproto.NewPrint(doer, sess.ID()),
+		alert:         w3cproto.NewAlert(&lockedDoer{doer: cli, lock: lock}, sess.ID()),
+		prompts:       prompts,
+		lock:          lock,
 	}
-	return &browser, nil
 }
 
-func NewSession(ctx context.Context, addr string, opts w3cproto.BrowserOptions) (*Session, error) {
+// NewSession creates a new session on the remote end at the address. The commands pass through
+// the middlewares, e.g. w3cproto.LogCommands or w3cproto.InjectHeaders.
+func NewSession(ctx context.Context, addr string, opts w3cproto.BrowserOptions, middlewares ...w3cproto.Middleware) (*Session, error) {
 	client, err := httpclient.New(httpclient.WithBaseURL(addr))
 	if err != nil {
 		return nil, err
 	}
-	return NewSessionFromClient(ctx, client, opts)
+	sess, err := NewSessionFromClient(ctx, client, opts, middlewares...)
+	if err != nil {
+		return nil, err
+	}
+	sess.addr = addr
+	return sess, nil
 }
 
 // SessionID returns the unique session id.
@@ -145,7 +180,34 @@ func (b *Session) Elements() w3cproto.Elements {
 	return b.elements
 }
 
+// Actions returns an actions protocol.
+func (b *Session) Actions() w3cproto.Actions {
+	return b.actions
+}
+
+// Print returns a print protocol.
+func (b *Session) Print() w3cproto.Print {
+	return b.print
+}
+
+// Alert returns a user prompts protocol.
+func (b *Session) Alert() w3cproto.Alert {
+	return b.alert
+}
+
+// SetPromptHandler sets the handler of the user prompts that block commands
+// with an "unexpected alert open" error. The blocked command is retried once
+// after the prompt has been handled. A nil handler disables the handling.
+func (b *Session) SetPromptHandler(h PromptHandler) {
+	b.prompts.setHandler(h)
+}
+
 // Close close the current session.
 func (b *Session) Close(ctx context.Context) error {
+	ctx, release, err := b.lock.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
 	return b.session.Delete(ctx)
 }
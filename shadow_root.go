@@ -0,0 +1,37 @@
+package webdriver
+
+import (
+	"context"
+
+	"github.com/mediabuyerbot/go-webdriver/pkg/w3cproto"
+)
+
+// ShadowRoot represents a shadow root of the element.
+type ShadowRoot struct {
+	root w3cproto.ShadowRoot
+	sess *Session
+	ctx  context.Context
+}
+
+// ID returns the identifier of the shadow root.
+func (s ShadowRoot) ID() string {
+	return s.root.ID()
+}
+
+// FindElementContext finds an element inside the shadow root.
+func (s ShadowRoot) FindElementContext(ctx context.Context, loc Locator) (we WebElement, err error) {
+	w3cWebElem, err := s.root.FindOne(ctx, loc.Strategy, loc.Value)
+	if err != nil {
+		return we, err
+	}
+	return newWebElement(s.ctx, s.sess, w3cWebElem, loc), nil
+}
+
+// FindElementsContext finds multiple elements inside the shadow root.
+func (s ShadowRoot) FindElementsContext(ctx context.Context, loc Locator) ([]WebElement, error) {
+	w3cWebElems, err := s.root.Find(ctx, loc.Strategy, loc.Value)
+	if err != nil {
+		return nil, err
+	}
+	return newWebElements(s.ctx, s.sess, w3cWebElems, loc), nil
+}
@@ -0,0 +1,364 @@
+package webdriver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/url"
+	"strings"
+
+	"github.com/mediabuyerbot/go-webdriver/pkg/w3cproto"
+)
+
+// Snapshot is the state of the browser session: the cookies, the web storage and the open windows.
+// The snapshot is restored into another session, e.g. to reuse the state of the logged in user
+// in the short sessions.
+type Snapshot struct {
+	// URL is the URL of the current window.
+	URL     string            `json:"url"`
+	Windows []WindowSnapshot  `json:"windows"`
+	Cookies []w3cproto.Cookie `json:"cookies"`
+	Origins []OriginSnapshot  `json:"origins"`
+}
+
+// WindowSnapshot is the state of the window.
+type WindowSnapshot struct {
+	URL string `json:"url"`
+
+	// SessionStorage is the session storage of the document origin.
+	// The session storage is not shared by the windows.
+	SessionStorage map[string]string `json:"sessionStorage,omitempty"`
+}
+
+// OriginSnapshot is the state of the origin, e.g. https://example.com.
+type OriginSnapshot struct {
+	Origin       string            `json:"origin"`
+	LocalStorage map[string]string `json:"localStorage,omitempty"`
+}
+
+// Save writes the snapshot to the file as JSON.
+func (s *Snapshot) Save(filename string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filename, data, 0600)
+}
+
+// LoadSnapshot reads the snapshot written by Snapshot.Save.
+func LoadSnapshot(filename string) (*Snapshot, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	s := new(Snapshot)
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Snapshot captures the state of the session. The cookies and the local storage are read in each
+// open window and in the origins, e.g. the origin of the login page which is not open anymore.
+// The origins are visited in the new tab, the tab is closed afterwards. The other commands
+// of the session wait until the snapshot is captured.
+func (b *Browser) Snapshot(ctx context.Context, origins ...string) (snap *Snapshot, err error) {
+	ctx, release, err := b.sess.lock.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	current, err := b.ActiveWindowContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	handles, err := b.WindowsContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if switchErr := b.SwitchToContext(ctx, current); err == nil && switchErr != nil {
+			snap, err = nil, switchErr
+		}
+	}()
+
+	c := &snapshotCollector{
+		browser: b,
+		snap:    new(Snapshot),
+		cookies: make(map[string]bool),
+		origins: make(map[string]bool),
+	}
+	for _, handle := range handles {
+		if err := b.SwitchToContext(ctx, handle); err != nil {
+			return nil, err
+		}
+		win, err := c.collectWindow(ctx)
+		if err != nil {
+			return nil, err
+		}
+		c.snap.Windows = append(c.snap.Windows, win)
+		if handle == current {
+			c.snap.URL = win.URL
+		}
+	}
+	for _, rawURL := range origins {
+		o := origin(rawURL)
+		if len(o) == 0 || c.origins[o] {
+			continue
+		}
+		if err := c.collectOrigin(ctx, o); err != nil {
+			return nil, err
+		}
+	}
+	return c.snap, nil
+}
+
+type snapshotCollector struct {
+	browser *Browser
+	snap    *Snapshot
+	cookies map[string]bool
+	origins map[string]bool
+}
+
+func (c *snapshotCollector) collectWindow(ctx context.Context) (win WindowSnapshot, err error) {
+	if win.URL, err = c.browser.CurrentURLContext(ctx); err != nil {
+		return win, err
+	}
+	if err := c.collect(ctx, win.URL); err != nil {
+		return win, err
+	}
+	win.SessionStorage, err = c.browser.SessionStorage().Dump(ctx)
+	if errors.Is(err, ErrOpaqueOrigin) {
+		return win, nil
+	}
+	return win, err
+}
+
+// collectOrigin visits the origin in the new tab. The tab is closed and the window
+// that was current before is selected again.
+func (c *snapshotCollector) collectOrigin(ctx context.Context, o string) (err error) {
+	current, err := c.browser.ActiveWindowContext(ctx)
+	if err != nil {
+		return err
+	}
+	tab, err := c.browser.OpenTabContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		closeErr := c.browser.CloseWindowContext(ctx, tab)
+		if closeErr == nil {
+			closeErr = c.browser.SwitchToContext(ctx, current)
+		}
+		if err == nil {
+			err = closeErr
+		}
+	}()
+	if err := c.browser.SwitchToContext(ctx, tab); err != nil {
+		return err
+	}
+	if err := c.browser.NavigateToContext(ctx, o+"/"); err != nil {
+		return err
+	}
+	rawURL, err := c.browser.CurrentURLContext(ctx)
+	if err != nil {
+		return err
+	}
+	if err := c.collect(ctx, rawURL); err != nil {
+		return err
+	}
+	// the origin is visited even if the page redirects to another origin
+	c.origins[o] = true
+	return nil
+}
+
+// collect reads the cookies and the local storage visible to the document with the URL.
+func (c *snapshotCollector) collect(ctx context.Context, rawURL string) error {
+	cookies, err := c.browser.CookiesContext(ctx)
+	if err != nil {
+		return err
+	}
+	for _, cookie := range cookies {
+		key := cookie.Name() + ";" + cookie.Domain() + ";" + cookie.Path()
+		if !c.cookies[key] {
+			c.cookies[key] = true
+			c.snap.Cookies = append(c.snap.Cookies, cookie)
+		}
+	}
+
+	o := origin(rawURL)
+	if len(o) == 0 || c.origins[o] {
+		return nil
+	}
+	items, err := c.browser.LocalStorage().Dump(ctx)
+	if errors.Is(err, ErrOpaqueOrigin) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	c.origins[o] = true
+	c.snap.Origins = append(c.snap.Origins, OriginSnapshot{Origin: o, LocalStorage: items})
+	return nil
+}
+
+// Restore replays the snapshot into the session, e.g. the session of the fresh browser. Each origin
+// of the cookies and the local storage is visited in the current window first, so that the cookie
+// domains are accepted. The host-only cookies are set in the origin of the cookie host. Then the
+// windows are restored: the current window loads the URL of the first window, the new tabs are
+// opened for the other ones. The window with the current URL of the snapshot becomes current.
+// The other commands of the session wait until the snapshot is restored.
+func (b *Browser) Restore(ctx context.Context, snap *Snapshot) error {
+	ctx, release, err := b.sess.lock.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	for _, v := range snap.visits() {
+		if err := b.NavigateToContext(ctx, v.origin+"/"); err != nil {
+			return err
+		}
+		for _, cookie := range v.cookies {
+			if err := b.AddCookieContext(ctx, restoredCookie(cookie)); err != nil {
+				return err
+			}
+		}
+		if err := b.LocalStorage().setItems(ctx, v.localStorage); err != nil {
+			return err
+		}
+	}
+
+	windows := snap.Windows
+	if len(windows) == 0 && len(snap.URL) > 0 {
+		windows = []WindowSnapshot{{URL: snap.URL}}
+	}
+	handle, err := b.ActiveWindowContext(ctx)
+	if err != nil {
+		return err
+	}
+	var current w3cproto.WindowHandle
+	for i, win := range windows {
+		if i > 0 {
+			if handle, err = b.OpenTabContext(ctx); err != nil {
+				return err
+			}
+			if err := b.SwitchToContext(ctx, handle); err != nil {
+				return err
+			}
+		}
+		if err := b.NavigateToContext(ctx, win.URL); err != nil {
+			return err
+		}
+		if len(win.SessionStorage) > 0 {
+			if err := b.SessionStorage().setItems(ctx, win.SessionStorage); err != nil {
+				return err
+			}
+			// reloads the page, so that the page sees the session storage
+			if err := b.RefreshContext(ctx); err != nil {
+				return err
+			}
+		}
+		if current.IsEmpty() && win.URL == snap.URL {
+			current = handle
+		}
+	}
+	if current.IsEmpty() || current == handle {
+		return nil
+	}
+	return b.SwitchToContext(ctx, current)
+}
+
+// originVisit is the state restored in the origin.
+type originVisit struct {
+	origin       string
+	cookies      []w3cproto.Cookie
+	localStorage map[string]string
+}
+
+// visits groups the cookies and the local storage by the origins to visit. The cookie is set
+// in the first origin matching the cookie domain, the origin of the cookie domain is visited
+// if the snapshot has no matching origin.
+func (s *Snapshot) visits() []*originVisit {
+	var visits []*originVisit
+	visit := func(o string) *originVisit {
+		for _, v := range visits {
+			if v.origin == o {
+				return v
+			}
+		}
+		v := &originVisit{origin: o}
+		visits = append(visits, v)
+		return v
+	}
+	for _, o := range s.Origins {
+		visit(o.Origin).localStorage = o.LocalStorage
+	}
+	for _, cookie := range s.Cookies {
+		var match *originVisit
+		for _, v := range visits {
+			if cookieMatches(cookie, v.origin) {
+				match = v
+				break
+			}
+		}
+		if match == nil {
+			match = visit(cookieOrigin(cookie))
+		}
+		match.cookies = append(match.cookies, cookie)
+	}
+	n := 0
+	for _, v := range visits {
+		if len(v.cookies) > 0 || len(v.localStorage) > 0 {
+			visits[n] = v
+			n++
+		}
+	}
+	return visits[:n]
+}
+
+// cookieMatches reports whether the document of the origin accepts the cookie.
+func cookieMatches(cookie w3cproto.Cookie, o string) bool {
+	u, err := url.Parse(o)
+	if err != nil {
+		return false
+	}
+	if cookie.Secure() && u.Scheme != "https" {
+		return false
+	}
+	domain := cookie.Domain()
+	host := u.Hostname()
+	if !strings.HasPrefix(domain, ".") {
+		// the host-only cookie
+		return len(domain) == 0 || host == domain
+	}
+	domain = domain[1:]
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
+
+// restoredCookie returns the cookie added to the browser by Restore. The browser reports the domain
+// of the host-only cookie without the leading dot, the cookie added with the domain would be visible
+// to the subdomains, so the domain is dropped.
+func restoredCookie(cookie w3cproto.Cookie) w3cproto.Cookie {
+	if strings.HasPrefix(cookie.Domain(), ".") {
+		return cookie
+	}
+	c := make(w3cproto.Cookie, len(cookie))
+	for key, value := range cookie {
+		if key != w3cproto.CookieDomainKey {
+			c[key] = value
+		}
+	}
+	return c
+}
+
+// cookieOrigin returns the origin of the cookie domain.
+func cookieOrigin(cookie w3cproto.Cookie) string {
+	scheme := "http://"
+	if cookie.Secure() {
+		scheme = "https://"
+	}
+	return scheme + strings.TrimPrefix(cookie.Domain(), ".")
+}
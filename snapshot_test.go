@@ -0,0 +1,176 @@
+package webdriver
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mediabuyerbot/go-webdriver/pkg/w3cproto"
+	"github.com/mediabuyerbot/go-webdriver/pkg/w3ctest"
+)
+
+func TestBrowser_SnapshotRestore(t *testing.T) {
+	ctx := context.Background()
+	srv := w3ctest.NewServer()
+	defer srv.Close()
+	handleStorage(srv)
+
+	browser, err := OpenRemoteBrowser(ctx, srv.URL, w3cproto.NewBrowserOptions(nil, nil))
+	assert.Nil(t, err)
+	defer browser.Close()
+
+	// the login page is not open when the snapshot is captured
+	assert.Nil(t, browser.NavigateTo("https://login.example.org/"))
+	assert.Nil(t, browser.AddCookie(w3cproto.MakeCookie().SetName("sso").SetValue("1")))
+	assert.Nil(t, browser.NavigateTo("https://example.com/account"))
+	assert.Nil(t, browser.AddCookie(w3cproto.MakeCookie().SetName("token").SetValue("abc")))
+	assert.Nil(t, browser.LocalStorage().setItems(ctx, map[string]string{"theme": "dark"}))
+	assert.Nil(t, browser.SessionStorage().setItems(ctx, map[string]string{"step": "2"}))
+	main, err := browser.ActiveWindow()
+	assert.Nil(t, err)
+	tab, err := browser.OpenTab()
+	assert.Nil(t, err)
+	assert.Nil(t, browser.SwitchTo(tab))
+	assert.Nil(t, browser.NavigateTo("https://shop.example.net/cart"))
+	assert.Nil(t, browser.AddCookie(w3cproto.MakeCookie().SetName("cart").SetValue("42").SetSecure(true)))
+	assert.Nil(t, browser.SwitchTo(main))
+
+	// each of the origins is visited in its own tab
+	snap, err := browser.Snapshot(ctx, "https://login.example.org/login", "https://example.com/", "https://static.example.io/")
+	assert.Nil(t, err)
+	assert.Equal(t, "https://example.com/account", snap.URL)
+	assert.Equal(t, []WindowSnapshot{
+		{URL: "https://example.com/account", SessionStorage: map[string]string{"step": "2"}},
+		{URL: "https://shop.example.net/cart", SessionStorage: map[string]string{}},
+	}, snap.Windows)
+	assert.Len(t, snap.Cookies, 3)
+	assert.Equal(t, []OriginSnapshot{
+		{Origin: "https://example.com", LocalStorage: map[string]string{"theme": "dark"}},
+		{Origin: "https://shop.example.net", LocalStorage: map[string]string{}},
+		{Origin: "https://login.example.org", LocalStorage: map[string]string{}},
+		{Origin: "https://static.example.io", LocalStorage: map[string]string{}},
+	}, snap.Origins)
+
+	// the tabs of the visited origins are closed and the current window is kept
+	windows, err := browser.Windows()
+	assert.Nil(t, err)
+	assert.Equal(t, []w3cproto.WindowHandle{main, tab}, windows)
+	active, err := browser.ActiveWindow()
+	assert.Nil(t, err)
+	assert.Equal(t, main, active)
+
+	filename := filepath.Join(t.TempDir(), "snapshot.json")
+	assert.Nil(t, snap.Save(filename))
+	loaded, err := LoadSnapshot(filename)
+	assert.Nil(t, err)
+	assert.Equal(t, snap.URL, loaded.URL)
+	assert.Len(t, loaded.Cookies, 3)
+
+	// restores into the fresh session
+	restored, err := OpenRemoteBrowser(ctx, srv.URL, w3cproto.NewBrowserOptions(nil, nil))
+	assert.Nil(t, err)
+	defer restored.Close()
+	assert.Nil(t, restored.Restore(ctx, loaded))
+
+	url, err := restored.CurrentURL()
+	assert.Nil(t, err)
+	assert.Equal(t, "https://example.com/account", url)
+	windows, err = restored.Windows()
+	assert.Nil(t, err)
+	assert.Len(t, windows, 2)
+	s, ok := srv.Session(restored.UID())
+	assert.True(t, ok)
+	names := make(map[string]string)
+	for _, c := range s.Cookies() {
+		names[c.Name()] = c.Domain()
+	}
+	assert.Equal(t, map[string]string{"sso": "login.example.org", "token": "example.com", "cart": "shop.example.net"}, names)
+	items, err := restored.LocalStorage().Dump(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"theme": "dark"}, items)
+	items, err = restored.SessionStorage().Dump(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"step": "2"}, items)
+}
+
+func TestBrowser_RestoreHostOnlyCookies(t *testing.T) {
+	ctx := context.Background()
+	srv := w3ctest.NewServer()
+	defer srv.Close()
+	handleStorage(srv)
+
+	var added []w3cproto.Cookie
+	record := func(next w3cproto.Doer) w3cproto.Doer {
+		return w3cproto.DoerFunc(func(ctx context.Context, method string, path string, p w3cproto.Params) (*w3cproto.Response, error) {
+			if method == http.MethodPost && strings.HasSuffix(path, "/cookie") {
+				added = append(added, w3cproto.Cookie(p["cookie"].(w3cproto.Params)))
+			}
+			return next.Do(ctx, method, path, p)
+		})
+	}
+	browser, err := OpenRemoteBrowser(ctx, srv.URL, w3cproto.NewBrowserOptions(nil, nil), record)
+	assert.Nil(t, err)
+	defer browser.Close()
+
+	snap := &Snapshot{
+		URL: "https://www.example.com/",
+		Cookies: []w3cproto.Cookie{
+			w3cproto.MakeCookie().SetName("sid").SetValue("1").SetDomain("example.com").SetSecure(true),
+			w3cproto.MakeCookie().SetName("lang").SetValue("en").SetDomain(".example.com"),
+		},
+		Origins: []OriginSnapshot{
+			{Origin: "https://www.example.com", LocalStorage: map[string]string{"k": "v"}},
+		},
+	}
+	assert.Nil(t, browser.Restore(ctx, snap))
+
+	// the host-only cookie is added without the domain in the origin of the cookie host
+	if assert.Len(t, added, 2) {
+		assert.Equal(t, "lang", added[0].Name())
+		assert.Equal(t, ".example.com", added[0].Domain())
+		assert.Equal(t, "sid", added[1].Name())
+		_, ok := added[1][w3cproto.CookieDomainKey]
+		assert.False(t, ok)
+	}
+	s, ok := srv.Session(browser.UID())
+	assert.True(t, ok)
+	domains := make(map[string]string)
+	for _, c := range s.Cookies() {
+		domains[c.Name()] = c.Domain()
+	}
+	assert.Equal(t, map[string]string{"sid": "example.com", "lang": ".example.com"}, domains)
+	// the snapshot is not modified
+	assert.Equal(t, "example.com", snap.Cookies[0].Domain())
+}
+
+func TestSnapshot_Visits(t *testing.T) {
+	snap := &Snapshot{
+		Cookies: []w3cproto.Cookie{
+			w3cproto.MakeCookie().SetName("a").SetValue("1").SetDomain(".example.com"),
+			w3cproto.MakeCookie().SetName("b").SetValue("2").SetDomain("api.example.net").SetSecure(true),
+			w3cproto.MakeCookie().SetName("c").SetValue("3").SetDomain("example.org"),
+			w3cproto.MakeCookie().SetName("d").SetValue("4").SetDomain("example.com"),
+		},
+		Origins: []OriginSnapshot{
+			{Origin: "https://www.example.com"},
+			{Origin: "http://api.example.net", LocalStorage: map[string]string{"k": "v"}},
+			{Origin: "https://empty.example.com"},
+		},
+	}
+	visits := snap.visits()
+	var origins []string
+	for _, v := range visits {
+		origins = append(origins, v.origin)
+	}
+	assert.Equal(t, []string{"https://www.example.com", "http://api.example.net", "https://api.example.net", "http://example.org", "http://example.com"}, origins)
+	assert.Equal(t, "a", visits[0].cookies[0].Name())
+	assert.Empty(t, visits[1].cookies)
+	assert.Equal(t, "b", visits[2].cookies[0].Name())
+	// the host-only cookie is not set in the subdomain
+	assert.Len(t, visits[0].cookies, 1)
+	assert.Equal(t, "d", visits[4].cookies[0].Name())
+}
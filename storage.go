@@ -0,0 +1,170 @@
+package webdriver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+var (
+	ErrOpaqueOrigin      = errors.New("webdriver: web storage is not available for the opaque origin")
+	ErrNoSuchStorageItem = errors.New("webdriver: no such storage item")
+)
+
+const (
+	localStorageArea   = "localStorage"
+	sessionStorageArea = "sessionStorage"
+)
+
+// storageScript runs the storage operation in the current document. The documents with the opaque
+// origin, e.g. about:blank or data: URLs, have no storage, so the script returns the origin
+// without accessing the storage, which throws the SecurityError.
+const storageScript = `var area = arguments[0], op = arguments[1], arg = arguments[2];
+var origin = window.location.origin;
+if (!origin || origin === 'null') {
+	return {origin: 'null', url: window.location.href};
+}
+var storage = window[area], result = null;
+switch (op) {
+case 'get':
+	result = storage.getItem(arg);
+	break;
+case 'set':
+	for (var name in arg) {
+		storage.setItem(name, arg[name]);
+	}
+	break;
+case 'remove':
+	storage.removeItem(arg);
+	break;
+case 'keys':
+	result = [];
+	for (var i = 0; i < storage.length; i++) {
+		result.push(storage.key(i));
+	}
+	break;
+case 'clear':
+	storage.clear();
+	break;
+case 'dump':
+	result = {};
+	for (var i = 0; i < storage.length; i++) {
+		var key = storage.key(i);
+		result[key] = storage.getItem(key);
+	}
+	break;
+}
+return {origin: origin, url: window.location.href, result: result};`
+
+type storageResponse struct {
+	Origin string          `json:"origin"`
+	URL    string          `json:"url"`
+	Result json.RawMessage `json:"result"`
+}
+
+// Storage is the web storage of the document in the current browsing context, the local storage
+// or the session storage. The values are the strings. The storage is not available to the documents
+// with the opaque origin, e.g. about:blank, the methods return ErrOpaqueOrigin.
+type Storage struct {
+	browser *Browser
+	area    string
+}
+
+// LocalStorage returns the local storage of the current document, the storage is shared
+// by the documents of the origin.
+func (b *Browser) LocalStorage() *Storage {
+	return &Storage{browser: b, area: localStorageArea}
+}
+
+// SessionStorage returns the session storage of the current document, the storage is shared
+// by the documents of the origin in the window.
+func (b *Browser) SessionStorage() *Storage {
+	return &Storage{browser: b, area: sessionStorageArea}
+}
+
+// Get returns the value of the item by the key. Returns ErrNoSuchStorageItem if the item does not exist.
+func (s *Storage) Get(ctx context.Context, key string) (string, error) {
+	var value *string
+	if err := s.do(ctx, "get", key, &value); err != nil {
+		return "", err
+	}
+	if value == nil {
+		return "", fmt.Errorf("%w: %s", ErrNoSuchStorageItem, key)
+	}
+	return *value, nil
+}
+
+// Set adds the item or replaces the value of the item.
+func (s *Storage) Set(ctx context.Context, key string, value string) error {
+	return s.do(ctx, "set", map[string]string{key: value}, nil)
+}
+
+// Remove removes the item by the key. The missing item is not an error.
+func (s *Storage) Remove(ctx context.Context, key string) error {
+	return s.do(ctx, "remove", key, nil)
+}
+
+// Keys returns the keys of the items in the storage order.
+func (s *Storage) Keys(ctx context.Context) (keys []string, err error) {
+	err = s.do(ctx, "keys", nil, &keys)
+	return keys, err
+}
+
+// Clear removes all the items.
+func (s *Storage) Clear(ctx context.Context) error {
+	return s.do(ctx, "clear", nil, nil)
+}
+
+// Dump returns all the items.
+func (s *Storage) Dump(ctx context.Context) (items map[string]string, err error) {
+	err = s.do(ctx, "dump", nil, &items)
+	return items, err
+}
+
+// setItems adds the items or replaces the values of the items.
+func (s *Storage) setItems(ctx context.Context, items map[string]string) error {
+	if len(items) == 0 {
+		return nil
+	}
+	return s.do(ctx, "set", items, nil)
+}
+
+// do runs the storage operation with the argument in the current document
+// and decodes the result to v if v is not nil.
+func (s *Storage) do(ctx context.Context, op string, arg interface{}, v interface{}) error {
+	data, err := s.browser.ExecuteContext(ctx, storageScript, []interface{}{s.area, op, arg})
+	if err != nil {
+		return err
+	}
+	var resp storageResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return err
+	}
+	if resp.Origin == "null" {
+		return fmt.Errorf("%w: %s of %s", ErrOpaqueOrigin, s.area, resp.URL)
+	}
+	if v == nil {
+		return nil
+	}
+	return json.Unmarshal(resp.Result, v)
+}
+
+// origin returns the origin of the URL, e.g. https://example.com:8080,
+// the empty string if the URL has the opaque origin.
+func origin(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || len(u.Host) == 0 {
+		return ""
+	}
+	host := strings.ToLower(u.Host)
+	switch scheme := strings.ToLower(u.Scheme); scheme {
+	case "http":
+		return "http://" + strings.TrimSuffix(host, ":80")
+	case "https":
+		return "https://" + strings.TrimSuffix(host, ":443")
+	}
+	return ""
+}
@@ -0,0 +1,126 @@
+package webdriver
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mediabuyerbot/go-webdriver/pkg/w3cproto"
+	"github.com/mediabuyerbot/go-webdriver/pkg/w3ctest"
+)
+
+// handleStorage emulates the web storage of the sessions running the storage script.
+// The keys are sorted in the storage order.
+func handleStorage(srv *w3ctest.Server) {
+	var lock sync.Mutex
+	storages := make(map[string]map[string]string)
+	srv.HandleScript(func(s *w3ctest.Session, script string, args []interface{}) (interface{}, bool, error) {
+		if script != storageScript {
+			return nil, false, nil
+		}
+		url := s.CurrentURL()
+		o := origin(url)
+		if len(o) == 0 {
+			return map[string]interface{}{"origin": "null", "url": url}, true, nil
+		}
+		lock.Lock()
+		defer lock.Unlock()
+		key := s.ID() + " " + args[0].(string) + " " + o
+		items, ok := storages[key]
+		if !ok {
+			items = make(map[string]string)
+			storages[key] = items
+		}
+		var result interface{}
+		switch args[1].(string) {
+		case "get":
+			if v, ok := items[args[2].(string)]; ok {
+				result = v
+			}
+		case "set":
+			for k, v := range args[2].(map[string]interface{}) {
+				items[k] = v.(string)
+			}
+		case "remove":
+			delete(items, args[2].(string))
+		case "keys":
+			keys := make([]string, 0, len(items))
+			for k := range items {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			result = keys
+		case "clear":
+			storages[key] = make(map[string]string)
+		case "dump":
+			dump := make(map[string]interface{}, len(items))
+			for k, v := range items {
+				dump[k] = v
+			}
+			result = dump
+		}
+		return map[string]interface{}{"origin": o, "url": url, "result": result}, true, nil
+	})
+}
+
+func TestStorage(t *testing.T) {
+	ctx := context.Background()
+	srv := w3ctest.NewServer()
+	defer srv.Close()
+	handleStorage(srv)
+	browser, err := OpenRemoteBrowser(ctx, srv.URL, w3cproto.NewBrowserOptions(nil, nil))
+	assert.Nil(t, err)
+	defer browser.Close()
+
+	// returns error if the origin is opaque
+	_, err = browser.LocalStorage().Keys(ctx)
+	assert.True(t, errors.Is(err, ErrOpaqueOrigin))
+	assert.Contains(t, err.Error(), "localStorage of about:blank")
+
+	assert.Nil(t, browser.NavigateTo("https://example.com/"))
+	local := browser.LocalStorage()
+	assert.Nil(t, local.Set(ctx, "theme", "dark"))
+	assert.Nil(t, local.Set(ctx, "cart", `{"items":[1,2]}`))
+	value, err := local.Get(ctx, "cart")
+	assert.Nil(t, err)
+	assert.Equal(t, `{"items":[1,2]}`, value)
+	_, err = local.Get(ctx, "token")
+	assert.True(t, errors.Is(err, ErrNoSuchStorageItem))
+	keys, err := local.Keys(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"cart", "theme"}, keys)
+
+	assert.Nil(t, local.Remove(ctx, "cart"))
+	assert.Nil(t, local.Remove(ctx, "cart"))
+	items, err := local.Dump(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"theme": "dark"}, items)
+
+	// the areas are separate
+	session := browser.SessionStorage()
+	assert.Nil(t, session.Set(ctx, "step", "1"))
+	assert.Nil(t, local.Clear(ctx))
+	items, err = local.Dump(ctx)
+	assert.Nil(t, err)
+	assert.Empty(t, items)
+	value, err = session.Get(ctx, "step")
+	assert.Nil(t, err)
+	assert.Equal(t, "1", value)
+}
+
+func TestOrigin(t *testing.T) {
+	for rawURL, want := range map[string]string{
+		"https://Example.com/path?q=1": "https://example.com",
+		"https://example.com:443/":     "https://example.com",
+		"http://example.com:8080/":     "http://example.com:8080",
+		"about:blank":                  "",
+		"data:text/html,hello":         "",
+		"file:///tmp/index.html":       "",
+	} {
+		assert.Equal(t, want, origin(rawURL), rawURL)
+	}
+}
@@ -0,0 +1,340 @@
+package webdriver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/mediabuyerbot/go-webdriver/pkg/w3cproto"
+)
+
+const (
+	DefaultWaitTimeout  = 10 * time.Second
+	DefaultWaitInterval = 500 * time.Millisecond
+)
+
+var ErrWaitTimeout = errors.New("webdriver: wait timeout")
+
+// Condition reports whether the expected state of the browser has been reached.
+type Condition func(b *Browser) (bool, error)
+
+// Wait polls the condition until it is satisfied, the timeout expires
+// or the context is cancelled.
+type Wait struct {
+	browser  *Browser
+	ctx      context.Context
+	timeout  time.Duration
+	interval time.Duration
+	ignored  []error
+}
+
+// Wait returns a new explicit wait with the given timeout. By default the condition
+// is polled every 500ms and the ErrNoSuchElement errors are ignored.
+func (b *Browser) Wait(timeout time.Duration) *Wait {
+	if timeout <= 0 {
+		timeout = DefaultWaitTimeout
+	}
+	return &Wait{
+		browser:  b,
+		ctx:      b.context(),
+		timeout:  timeout,
+		interval: DefaultWaitInterval,
+		ignored:  []error{w3cproto.ErrNoSuchElement},
+	}
+}
+
+// WithInterval sets the interval between the condition polls.
+func (w *Wait) WithInterval(d time.Duration) *Wait {
+	if d > 0 {
+		w.interval = d
+	}
+	return w
+}
+
+// WithContext sets the context that cancels the wait.
+func (w *Wait) WithContext(ctx context.Context) *Wait {
+	if ctx != nil {
+		w.ctx = ctx
+	}
+	return w
+}
+
+// Ignoring adds the errors that do not stop the wait. The errors are matched using errors.Is.
+func (w *Wait) Ignoring(errs ...error) *Wait {
+	w.ignored = append(w.ignored, errs...)
+	return w
+}
+
+// Until polls the condition until it returns true. Returns an error that wraps ErrWaitTimeout
+// and the last ignored error if the timeout expires.
+func (w *Wait) Until(cond Condition) error {
+	ctx := w.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, w.timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	var lastErr error
+	// the commands of the condition are bound to the wait
+	browser := w.browser.withContext(ctx)
+	for {
+		ok, err := cond(browser)
+		if err != nil && ctx.Err() != nil {
+			// the condition is interrupted by the end of the wait
+			return w.stopped(ctx, lastErr)
+		}
+		if err != nil && !w.isIgnored(err) {
+			return err
+		}
+		if err == nil && ok {
+			return nil
+		}
+		lastErr = err
+
+		select {
+		case <-ctx.Done():
+			return w.stopped(ctx, lastErr)
+		case <-ticker.C:
+		}
+	}
+}
+
+// stopped returns the error of the wait stopped by the context.
+func (w *Wait) stopped(ctx context.Context, lastErr error) error {
+	if ctx.Err() != context.DeadlineExceeded {
+		return ctx.Err()
+	}
+	if lastErr != nil {
+		return fmt.Errorf("%w after %v: %v", ErrWaitTimeout, w.timeout, lastErr)
+	}
+	return fmt.Errorf("%w after %v", ErrWaitTimeout, w.timeout)
+}
+
+func (w *Wait) isIgnored(err error) bool {
+	for _, ignored := range w.ignored {
+		if errors.Is(err, ignored) {
+			return true
+		}
+	}
+	return false
+}
+
+// Not negates the condition.
+func Not(cond Condition) Condition {
+	return func(b *Browser) (bool, error) {
+		ok, err := cond(b)
+		if err != nil {
+			return false, err
+		}
+		return !ok, nil
+	}
+}
+
+// ElementPresent waits until the element is present in the DOM.
+func ElementPresent(loc Locator) Condition {
+	return func(b *Browser) (bool, error) {
+		if _, err := b.sess.Elements().FindOne(b.context(), loc.Strategy, loc.Value); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+}
+
+// ElementVisible waits until the element is present in the DOM and visible.
+// The element replaced after it is found is checked on the next poll.
+func ElementVisible(loc Locator) Condition {
+	return func(b *Browser) (bool, error) {
+		elem, err := b.sess.Elements().FindOne(b.context(), loc.Strategy, loc.Value)
+		if err != nil {
+			return false, err
+		}
+		return notStale(b.isDisplayed(elem))
+	}
+}
+
+// ElementClickable waits until the element is visible and enabled.
+// The element replaced after it is found is checked on the next poll.
+func ElementClickable(loc Locator) Condition {
+	return func(b *Browser) (bool, error) {
+		elem, err := b.sess.Elements().FindOne(b.context(), loc.Strategy, loc.Value)
+		if err != nil {
+			return false, err
+		}
+		visible, err := b.isDisplayed(elem)
+		if err != nil || !visible {
+			return notStale(false, err)
+		}
+		return notStale(elem.IsEnabled(b.context()))
+	}
+}
+
+// ElementStaleness waits until the element is no longer attached to the DOM.
+func ElementStaleness(we WebElement) Condition {
+	return func(b *Browser) (bool, error) {
+		_, err := we.elem.IsEnabled(b.context())
+		if err == nil {
+			return false, nil
+		}
+		if errors.Is(err, w3cproto.ErrStaleElementReference) {
+			return true, nil
+		}
+		return false, err
+	}
+}
+
+// ElementTextContains waits until the text of the element contains the substring.
+func ElementTextContains(loc Locator, substr string) Condition {
+	return func(b *Browser) (bool, error) {
+		elem, err := b.sess.Elements().FindOne(b.context(), loc.Strategy, loc.Value)
+		if err != nil {
+			return false, err
+		}
+		text, err := elem.Text(b.context())
+		if err != nil {
+			return notStale(false, err)
+		}
+		return strings.Contains(text, substr), nil
+	}
+}
+
+// notStale reports the stale element as the condition which is not satisfied yet,
+// e.g. the element is found and then replaced by the page script.
+func notStale(ok bool, err error) (bool, error) {
+	if errors.Is(err, w3cproto.ErrStaleElementReference) {
+		return false, nil
+	}
+	return ok, err
+}
+
+// TitleIs waits until the title of the current page is equal to the title.
+func TitleIs(title string) Condition {
+	return func(b *Browser) (bool, error) {
+		have, err := b.sess.Navigation().GetTitle(b.context())
+		if err != nil {
+			return false, err
+		}
+		return have == title, nil
+	}
+}
+
+// TitleContains waits until the title of the current page contains the substring.
+func TitleContains(substr string) Condition {
+	return func(b *Browser) (bool, error) {
+		have, err := b.sess.Navigation().GetTitle(b.context())
+		if err != nil {
+			return false, err
+		}
+		return strings.Contains(have, substr), nil
+	}
+}
+
+// TitleMatches waits until the title of the current page matches the regular expression.
+func TitleMatches(re *regexp.Regexp) Condition {
+	return func(b *Browser) (bool, error) {
+		have, err := b.sess.Navigation().GetTitle(b.context())
+		if err != nil {
+			return false, err
+		}
+		return re.MatchString(have), nil
+	}
+}
+
+// URLIs waits until the URL of the current page is equal to the url.
+func URLIs(url string) Condition {
+	return func(b *Browser) (bool, error) {
+		have, err := b.sess.Navigation().GetCurrentURL(b.context())
+		if err != nil {
+			return false, err
+		}
+		return have == url, nil
+	}
+}
+
+// URLContains waits until the URL of the current page contains the substring.
+func URLContains(substr string) Condition {
+	return func(b *Browser) (bool, error) {
+		have, err := b.sess.Navigation().GetCurrentURL(b.context())
+		if err != nil {
+			return false, err
+		}
+		return strings.Contains(have, substr), nil
+	}
+}
+
+// URLMatches waits until the URL of the current page matches the regular expression.
+func URLMatches(re *regexp.Regexp) Condition {
+	return func(b *Browser) (bool, error) {
+		have, err := b.sess.Navigation().GetCurrentURL(b.context())
+		if err != nil {
+			return false, err
+		}
+		return re.MatchString(have), nil
+	}
+}
+
+// AlertPresent waits until an alert(), confirm(), or prompt() dialog is displayed.
+func AlertPresent() Condition {
+	return func(b *Browser) (bool, error) {
+		_, err := b.sess.Alert().Text(b.context())
+		if err == nil {
+			return true, nil
+		}
+		if w3cproto.IsNoSuchAlert(err) {
+			return false, nil
+		}
+		return false, err
+	}
+}
+
+// NumberOfWindows waits until the number of the opened windows is equal to n.
+func NumberOfWindows(n int) Condition {
+	return func(b *Browser) (bool, error) {
+		handles, err := b.sess.Context().GetWindowHandles(b.context())
+		if err != nil {
+			return false, err
+		}
+		return len(handles) == n, nil
+	}
+}
+
+// JSPredicate waits until the script returns a truthy value.
+func JSPredicate(script string, args ...interface{}) Condition {
+	return func(b *Browser) (bool, error) {
+		result, err := b.sess.Document().ExecuteScript(b.context(), script, args)
+		if err != nil {
+			return false, err
+		}
+		return isTruthy(result), nil
+	}
+}
+
+func (b *Browser) isDisplayed(elem w3cproto.WebElement) (bool, error) {
+	return newWebElement(b.context(), b.sess, elem, Locator{}).IsDisplayedContext(b.context())
+}
+
+func isTruthy(result []byte) bool {
+	var v interface{}
+	if err := json.Unmarshal(result, &v); err != nil {
+		return false
+	}
+	switch value := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return value
+	case float64:
+		return value != 0
+	case string:
+		return len(value) > 0
+	default:
+		return true
+	}
+}
@@ -0,0 +1,194 @@
+package webdriver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mediabuyerbot/go-webdriver/pkg/w3cproto"
+)
+
+// withDeadline matches the contexts with the deadline.
+type withDeadline struct{}
+
+func (withDeadline) Matches(x interface{}) bool {
+	ctx, ok := x.(context.Context)
+	if !ok {
+		return false
+	}
+	_, ok = ctx.Deadline()
+	return ok
+}
+
+func (withDeadline) String() string {
+	return "has deadline"
+}
+
+func TestWait_Until(t *testing.T) {
+	browser, cli, done := newTestBrowser(t)
+	defer done()
+
+	// returns success after the retries, the commands are bound to the wait
+	gomock.InOrder(
+		cli.EXPECT().Do(withDeadline{}, http.MethodPost, "/session/123/element", gomock.Any()).Times(2).Return(
+			nil, &w3cproto.Error{Code: "no such element"}),
+		cli.EXPECT().Do(withDeadline{}, http.MethodPost, "/session/123/element", gomock.Any()).Times(1).Return(
+			&w3cproto.Response{Value: []byte(`{"element-6066-11e4-a52e-4f735466cecf":"1"}`)}, nil),
+	)
+	err := browser.Wait(time.Second).
+		WithInterval(time.Millisecond).
+		Until(ElementPresent(By.CSS("#id")))
+	assert.Nil(t, err)
+
+	// returns success after the element is replaced by the page
+	elemResp := &w3cproto.Response{Value: []byte(`{"element-6066-11e4-a52e-4f735466cecf":"1"}`)}
+	gomock.InOrder(
+		cli.EXPECT().Do(withDeadline{}, http.MethodPost, "/session/123/element", gomock.Any()).Times(1).Return(elemResp, nil),
+		cli.EXPECT().Do(withDeadline{}, http.MethodGet, "/session/123/element/1/displayed", nil).Times(1).Return(
+			nil, &w3cproto.Error{Code: "stale element reference"}),
+		cli.EXPECT().Do(withDeadline{}, http.MethodPost, "/session/123/element", gomock.Any()).Times(1).Return(elemResp, nil),
+		cli.EXPECT().Do(withDeadline{}, http.MethodGet, "/session/123/element/1/displayed", nil).Times(1).Return(
+			&w3cproto.Response{Value: []byte(`true`)}, nil),
+	)
+	err = browser.Wait(time.Second).
+		WithInterval(time.Millisecond).
+		Until(ElementVisible(By.CSS("#id")))
+	assert.Nil(t, err)
+
+	// returns error that is not ignored
+	cli.EXPECT().Do(withDeadline{}, http.MethodGet, "/session/123/title", nil).Times(1).Return(nil, w3cproto.ErrInvalidResponse)
+	err = browser.Wait(time.Second).Until(TitleIs("title"))
+	assert.Equal(t, w3cproto.ErrInvalidResponse, err)
+
+	// returns timeout
+	cli.EXPECT().Do(withDeadline{}, http.MethodGet, "/session/123/title", nil).MinTimes(1).Return(
+		&w3cproto.Response{Value: []byte(`"other"`)}, nil)
+	err = browser.Wait(20 * time.Millisecond).
+		WithInterval(time.Millisecond).
+		Until(TitleIs("title"))
+	assert.True(t, errors.Is(err, ErrWaitTimeout))
+
+	// returns timeout with the last ignored error
+	cli.EXPECT().Do(withDeadline{}, http.MethodGet, "/session/123/url", nil).MinTimes(1).Return(nil, w3cproto.ErrInvalidResponse)
+	err = browser.Wait(20 * time.Millisecond).
+		WithInterval(time.Millisecond).
+		Ignoring(w3cproto.ErrInvalidResponse).
+		Until(URLContains("example"))
+	assert.True(t, errors.Is(err, ErrWaitTimeout))
+	assert.Contains(t, err.Error(), w3cproto.ErrInvalidResponse.Error())
+
+	// returns context error, the commands are not sent
+	cancelCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = browser.Wait(time.Second).
+		WithContext(cancelCtx).
+		Until(NumberOfWindows(2))
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestConditions(t *testing.T) {
+	browser, cli, done := newTestBrowser(t)
+	defer done()
+
+	ctx := context.Background()
+	elemResp := &w3cproto.Response{Value: []byte(`{"element-6066-11e4-a52e-4f735466cecf":"1"}`)}
+
+	// visible
+	cli.EXPECT().Do(ctx, http.MethodPost, "/session/123/element", gomock.Any()).Times(1).Return(elemResp, nil)
+	cli.EXPECT().Do(ctx, http.MethodGet, "/session/123/element/1/displayed", nil).Times(1).Return(
+		&w3cproto.Response{Value: []byte(`true`)}, nil)
+	ok, err := ElementVisible(By.CSS("#id"))(browser)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	// clickable
+	cli.EXPECT().Do(ctx, http.MethodPost, "/session/123/element", gomock.Any()).Times(1).Return(elemResp, nil)
+	cli.EXPECT().Do(ctx, http.MethodGet, "/session/123/element/1/displayed", nil).Times(1).Return(
+		&w3cproto.Response{Value: []byte(`true`)}, nil)
+	cli.EXPECT().Do(ctx, http.MethodGet, "/session/123/element/1/enabled", nil).Times(1).Return(
+		&w3cproto.Response{Value: []byte(`false`)}, nil)
+	ok, err = ElementClickable(By.CSS("#id"))(browser)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	// visible, clickable and text contains, the element is stale
+	staleErr := &w3cproto.Error{Code: "stale element reference"}
+	cli.EXPECT().Do(ctx, http.MethodPost, "/session/123/element", gomock.Any()).Times(3).Return(elemResp, nil)
+	cli.EXPECT().Do(ctx, http.MethodGet, "/session/123/element/1/displayed", nil).Times(2).Return(nil, staleErr)
+	cli.EXPECT().Do(ctx, http.MethodGet, "/session/123/element/1/text", nil).Times(1).Return(nil, staleErr)
+	ok, err = ElementVisible(By.CSS("#id"))(browser)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+	ok, err = ElementClickable(By.CSS("#id"))(browser)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+	ok, err = ElementTextContains(By.CSS("#id"), "world")(browser)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	// text contains
+	cli.EXPECT().Do(ctx, http.MethodPost, "/session/123/element", gomock.Any()).Times(1).Return(elemResp, nil)
+	cli.EXPECT().Do(ctx, http.MethodGet, "/session/123/element/1/text", nil).Times(1).Return(
+		&w3cproto.Response{Value: []byte(`"Hello, world"`)}, nil)
+	ok, err = ElementTextContains(By.CSS("#id"), "world")(browser)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	// staleness
+	cli.EXPECT().Do(ctx, http.MethodPost, "/session/123/element", gomock.Any()).Times(1).Return(elemResp, nil)
+	elem, err := browser.sess.Elements().FindOne(ctx, w3cproto.ByCSSSelector, "#id")
+	assert.Nil(t, err)
+	cli.EXPECT().Do(ctx, http.MethodGet, "/session/123/element/1/enabled", nil).Times(1).Return(
+		nil, &w3cproto.Error{Code: "stale element reference"})
+	ok, err = ElementStaleness(WebElement{elem: elem, ctx: ctx})(browser)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	// title and url
+	cli.EXPECT().Do(ctx, http.MethodGet, "/session/123/title", nil).Times(2).Return(
+		&w3cproto.Response{Value: []byte(`"Welcome page"`)}, nil)
+	ok, err = TitleContains("Welcome")(browser)
+	assert.True(t, ok)
+	ok, err = TitleMatches(regexp.MustCompile(`^W\w+ page$`))(browser)
+	assert.True(t, ok)
+	cli.EXPECT().Do(ctx, http.MethodGet, "/session/123/url", nil).Times(2).Return(
+		&w3cproto.Response{Value: []byte(`"http://example.com/"`)}, nil)
+	ok, err = URLIs("http://example.com/")(browser)
+	assert.True(t, ok)
+	ok, err = Not(URLMatches(regexp.MustCompile(`^https`)))(browser)
+	assert.True(t, ok)
+
+	// alert
+	cli.EXPECT().Do(ctx, http.MethodGet, "/session/123/alert/text", nil).Times(1).Return(
+		nil, &w3cproto.Error{Code: "no such alert"})
+	ok, err = AlertPresent()(browser)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	// js predicate
+	cli.EXPECT().Do(ctx, http.MethodPost, "/session/123/execute/sync", w3cproto.Params{
+		"script": "return document.readyState === arguments[0]",
+		"args":   []interface{}{"complete"},
+	}).Times(1).Return(&w3cproto.Response{Value: []byte(`true`)}, nil)
+	ok, err = JSPredicate("return document.readyState === arguments[0]", "complete")(browser)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+}
+
+func TestIsTruthy(t *testing.T) {
+	assert.True(t, isTruthy([]byte(`true`)))
+	assert.True(t, isTruthy([]byte(`1`)))
+	assert.True(t, isTruthy([]byte(`"a"`)))
+	assert.True(t, isTruthy([]byte(`{}`)))
+	assert.False(t, isTruthy([]byte(`null`)))
+	assert.False(t, isTruthy([]byte(`0`)))
+	assert.False(t, isTruthy([]byte(`""`)))
+	assert.False(t, isTruthy([]byte(`false`)))
+	assert.False(t, isTruthy([]byte(`{`)))
+}